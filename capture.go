@@ -0,0 +1,264 @@
+package kite
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/packet"
+)
+
+// A capture file holds the packets of a connection, to replay a session without its remote (see Recorder and
+// CaptureReader). It starts with a header:
+//
+//	magic   [4]byte  "KCAP"
+//	format  Byte     captureFormat
+//	start   Long     Unix time of the start of the capture, in microseconds
+//
+// followed by a record for every packet:
+//
+//	time      VarLong    Microseconds since the start of the capture
+//	direction Byte       packet.Direction
+//	state     Byte       packet.State
+//	version   VarInt     packet.Version the packet is encoded for
+//	id        VarInt     Wire ID of the packet in the version
+//	payload   ByteArray  Packet data after the ID, decrypted and decompressed
+//
+// Packets are recorded as they are encoded, so packets unknown to the packet package are captured too.
+const captureFormat = 1
+
+var captureMagic = []byte("KCAP")
+
+// A CapturedPacket is a packet read from a capture file.
+type CapturedPacket struct {
+	Time      time.Time
+	Direction packet.Direction
+	State     packet.State
+	Version   packet.Version
+	ID        int // The ID constant of the packet, or packet.InvalidState if it is unknown in its version
+	WireID    int
+	Payload   []byte
+}
+
+// Buffer returns a packet buffer holding the packet, encoded for its version.
+func (p CapturedPacket) Buffer() PacketBuffer {
+	buf := buffer.NewBuffer(5 + len(p.Payload))
+	buf.WriteVarInt(int32(p.WireID))
+	_, _ = buf.Write(p.Payload)
+	return wrapPacket(p.ID, buf.Bytes(), p.Version)
+}
+
+// A Recorder writes the packets of a connection to a capture file, for example to reproduce a bug report
+// from a production session. Recording is started with Attach. Packets are written to w unbuffered, one
+// Write call per packet, so that a capture is usable up to the last packet if the process exits.
+type Recorder struct {
+	mu    sync.Mutex
+	w     io.Writer
+	buf   *buffer.Buffer
+	start time.Time
+	err   error
+}
+
+// NewRecorder writes the header of a capture file to w and returns a recorder writing packets after it.
+func NewRecorder(w io.Writer) (*Recorder, error) {
+	r := &Recorder{w: w, buf: buffer.NewBuffer(64), start: time.Now()}
+	_, _ = r.buf.Write(captureMagic)
+	_ = r.buf.WriteByte(captureFormat)
+	r.buf.WriteLong(r.start.UnixMicro())
+	if _, err := w.Write(r.buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Attach adds interceptors to c recording its inbound and outbound packets, along with the state and
+// protocol version of c when they pass. A recorder should be attached to a single connection: a proxy
+// attached to both its client and server connections would record each forwarded packet twice.
+//
+// Like any interceptor, the recorder sees packets at its position in the chains. Attach it before adding
+// other inbound interceptors and after adding other outbound interceptors to record the packets as they
+// are on the wire.
+func (r *Recorder) Attach(c *Conn) {
+	c.AddInboundInterceptor(func(c *Conn, pb PacketBuffer, next PacketHandler) error {
+		r.record(c.direction, c.GetState(), pb)
+		return next(pb)
+	})
+	c.AddOutboundInterceptor(func(c *Conn, pb PacketBuffer, next PacketHandler) error {
+		direction := packet.Clientbound
+		if c.direction == packet.Clientbound {
+			direction = packet.Serverbound
+		}
+		r.record(direction, c.GetState(), pb)
+		return next(pb)
+	})
+}
+
+// Err returns the error which stopped recording, if writing a packet failed. Failing to record a packet
+// does not affect the connection.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(direction packet.Direction, state packet.State, pb PacketBuffer) {
+	position := pb.internal.Mark()
+	defer pb.internal.Reset(position)
+	pb.internal.Reset(pb.mark)
+	wireID, _ := pb.internal.ReadVarInt()
+	payload := pb.internal.RemainingSlice()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	r.buf.Clear()
+	r.buf.WriteVarLong(time.Since(r.start).Microseconds())
+	_ = r.buf.WriteByte(byte(direction))
+	_ = r.buf.WriteByte(byte(state))
+	r.buf.WriteVarInt(int32(pb.version))
+	r.buf.WriteVarInt(wireID)
+	r.buf.WriteByteArray(payload)
+	_, r.err = r.w.Write(r.buf.Bytes())
+}
+
+// A CaptureReader reads the packets of a capture file written by a Recorder.
+type CaptureReader struct {
+	r     *bufio.Reader
+	start time.Time
+}
+
+// NewCaptureReader reads the header of a capture file from r.
+func NewCaptureReader(r io.Reader) (*CaptureReader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(captureMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("invalid capture header: %w", err)
+	}
+	if !bytes.Equal(header[:len(captureMagic)], captureMagic) {
+		return nil, errors.New("not a capture file")
+	}
+	if header[len(captureMagic)] != captureFormat {
+		return nil, fmt.Errorf("unsupported capture format %d", header[len(captureMagic)])
+	}
+	start, err := buffer.Long.Read(br)
+	if err != nil {
+		return nil, fmt.Errorf("invalid capture header: %w", err)
+	}
+	return &CaptureReader{r: br, start: time.UnixMicro(start)}, nil
+}
+
+// Start returns the time the capture was started.
+func (r *CaptureReader) Start() time.Time {
+	return r.start
+}
+
+// Next returns the next packet of the capture, or io.EOF at the end of it. A capture ending in the middle of
+// a packet, for example because the recording process exited, returns io.ErrUnexpectedEOF.
+func (r *CaptureReader) Next() (p CapturedPacket, err error) {
+	if _, err = r.r.Peek(1); err != nil {
+		return p, err // io.EOF at the end of the capture
+	}
+	offset, err := buffer.VarLong.Read(r.r)
+	if err != nil {
+		return p, captureError(err)
+	}
+	direction, state, err := buffer.Read2(r.r, buffer.Byte, buffer.Byte)
+	if err != nil {
+		return p, captureError(err)
+	}
+	version, wireID, err := buffer.Read2(r.r, buffer.VarInt, buffer.VarInt)
+	if err != nil {
+		return p, captureError(err)
+	}
+	if p.Payload, err = buffer.LimitedByteArray(maxUncompressedSize).Read(r.r); err != nil {
+		return p, captureError(err)
+	}
+
+	p.Time = r.start.Add(time.Duration(offset) * time.Microsecond)
+	p.Direction, p.State, p.Version, p.WireID = packet.Direction(direction), packet.State(state), packet.Version(version), int(wireID)
+	if p.Direction != packet.Clientbound && p.Direction != packet.Serverbound {
+		return p, fmt.Errorf("invalid captured packet: direction %d", direction)
+	}
+	if p.WireID < 0 {
+		return p, fmt.Errorf("invalid captured packet: wire ID %d", p.WireID)
+	}
+	p.ID, _ = p.Version.PacketID(p.State, p.Direction, p.WireID)
+	return p, nil
+}
+
+// captureError returns the error for a record which could not be read, which is io.ErrUnexpectedEOF if the
+// capture ends in the middle of it.
+func captureError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("invalid captured packet: %w", err)
+}
+
+// Replay passes the packets of a capture received by c, that is with the direction of c, through its inbound
+// interceptors and handler as if they had been read from the connection. Before each packet the state and
+// protocol version of c are set to those recorded, with SetState and SetVersion. Packets are replayed
+// immediately, without the delays between them.
+//
+// Replay returns nil at the end of the capture or once c is closed, and otherwise fails like Serve, with a
+// *PacketError if a packet is not handled.
+func (c *Conn) Replay(r *CaptureReader) error {
+	for !c.closed.Load() {
+		p, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if p.Direction != c.direction {
+			continue
+		}
+		if p.State != c.state {
+			c.SetState(p.State)
+		}
+		if p.Version != c.version {
+			c.SetVersion(p.Version)
+		}
+
+		pb := p.Buffer()
+		err = c.inbound(pb)
+		if err == nil && pb.internal.Remaining() > 0 {
+			err = ErrUnconsumedPacket
+		}
+		if err != nil {
+			return &PacketError{Direction: c.direction, State: c.state, ID: p.ID, Err: err}
+		}
+	}
+	return nil
+}
+
+// NewReplayConn creates a connection without a remote, to Replay a capture into. Packets written to it are
+// discarded after passing through its outbound interceptors, and Serve returns immediately.
+func NewReplayConn(direction packet.Direction, handler PacketHandler) *Conn {
+	return NewConn(direction, replayNetConn{}, handler)
+}
+
+// replayNetConn is the net.Conn of a replay connection, which has no data to read and discards writes.
+type replayNetConn struct{}
+
+func (replayNetConn) Read([]byte) (int, error)         { return 0, io.EOF }
+func (replayNetConn) Write(p []byte) (int, error)      { return len(p), nil }
+func (replayNetConn) Close() error                     { return nil }
+func (replayNetConn) LocalAddr() net.Addr              { return replayAddr{} }
+func (replayNetConn) RemoteAddr() net.Addr             { return replayAddr{} }
+func (replayNetConn) SetDeadline(time.Time) error      { return nil }
+func (replayNetConn) SetReadDeadline(time.Time) error  { return nil }
+func (replayNetConn) SetWriteDeadline(time.Time) error { return nil }
+
+type replayAddr struct{}
+
+func (replayAddr) Network() string { return "replay" }
+func (replayAddr) String() string  { return "replay" }
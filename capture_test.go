@@ -0,0 +1,215 @@
+package kite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mworzala/kite/pkg/mojang"
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/stretchr/testify/require"
+)
+
+// handledPacket is a packet received by loginHandler, along with the state it was received in.
+type handledPacket struct {
+	state packet.State
+	pkt   packet.Packet
+}
+
+// loginHandler returns a handler for a server connection which goes through the handshake and login into
+// the config state, enabling compression, like a minimal proxy would.
+func loginHandler(c **Conn, out chan<- handledPacket) PacketHandler {
+	return func(pb PacketBuffer) error {
+		conn := *c
+		pkt, err := pb.Decode(packet.DefaultRegistry, conn.GetState(), packet.Serverbound)
+		if err != nil {
+			return err
+		}
+		pb.Consume()
+		out <- handledPacket{conn.GetState(), pkt}
+
+		switch pkt.(type) {
+		case *packet.ClientHandshake:
+			conn.SetState(packet.Login)
+		case *packet.ClientLoginStart:
+			return conn.SendPacket(&packet.ServerLoginSuccess{GameProfile: mojang.GameProfile{Name: "kite"}})
+		case *packet.ClientLoginAcknowledged:
+			conn.SetState(packet.Config)
+			conn.EnableCompression(16)
+		}
+		return nil
+	}
+}
+
+// readCapture returns every packet of a capture, without their times.
+func readCapture(t *testing.T, data []byte) []CapturedPacket {
+	t.Helper()
+	r, err := NewCaptureReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	var packets []CapturedPacket
+	for {
+		p, err := r.Next()
+		if err == io.EOF {
+			return packets
+		}
+		require.NoError(t, err)
+		require.False(t, p.Time.Before(r.Start()))
+		p.Time = time.Time{}
+		packets = append(packets, p)
+	}
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	received := make(chan handledPacket, 8)
+	cc, sc := net.Pipe()
+	client := NewConn(packet.Clientbound, cc, func(pb PacketBuffer) error {
+		pb.Consume()
+		return nil
+	})
+	var server *Conn
+	server = NewConn(packet.Serverbound, sc, loginHandler(&server, received))
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	var capture bytes.Buffer
+	recorder, err := NewRecorder(&capture)
+	require.NoError(t, err)
+	recorder.Attach(server)
+	go func() { _, _ = io.Copy(io.Discard, cc) }() // The client state changes while responses arrive, so they are not read
+	go server.ReadLoop()
+
+	version := packet.OldestVersion
+	require.NoError(t, client.SendPacket(&packet.ClientHandshake{ProtocolVersion: int32(version), Intent: packet.IntentLogin}))
+	client.SetState(packet.Login)
+	require.NoError(t, client.SendPacket(&packet.ClientLoginStart{Name: "kite"}))
+	require.NoError(t, client.SendPacket(&packet.ClientLoginAcknowledged{}))
+	client.SetState(packet.Config)
+	client.EnableCompression(16)
+	plugin := &packet.ClientPluginMessage{Channel: "kite:test", Data: bytes.Repeat([]byte("kite"), 64)}
+	require.NoError(t, client.SendPacket(plugin))
+
+	var handled []handledPacket
+	for range 4 {
+		handled = append(handled, receive(t, received))
+	}
+	require.Equal(t, handledPacket{packet.Config, plugin}, handled[3])
+	require.NoError(t, recorder.Err())
+
+	// Packets are recorded decompressed, with the state and version they were handled in.
+	captured := readCapture(t, capture.Bytes())
+	require.Len(t, captured, 5)
+	for i, expected := range []struct {
+		direction packet.Direction
+		state     packet.State
+		id        int
+	}{
+		{packet.Serverbound, packet.Handshake, packet.ClientHandshakeHandshakeID},
+		{packet.Serverbound, packet.Login, packet.ClientLoginLoginStartID},
+		{packet.Clientbound, packet.Login, packet.ServerLoginLoginSuccessID},
+		{packet.Serverbound, packet.Login, packet.ClientLoginLoginAcknowledgedID},
+		{packet.Serverbound, packet.Config, packet.ClientConfigPluginMessageID},
+	} {
+		require.Equal(t, expected.direction, captured[i].Direction, i)
+		require.Equal(t, expected.state, captured[i].State, i)
+		require.Equal(t, expected.id, captured[i].ID, i)
+		require.Equal(t, version, captured[i].Version, i)
+	}
+	decoded, err := captured[4].Buffer().Decode(packet.DefaultRegistry, packet.Config, packet.Serverbound)
+	require.NoError(t, err)
+	require.Equal(t, plugin, decoded)
+
+	// Replaying the capture handles the same packets, and the handler sends the same responses.
+	replayed := make(chan handledPacket, 8)
+	var replay *Conn
+	replay = NewReplayConn(packet.Serverbound, loginHandler(&replay, replayed))
+	var replayCapture bytes.Buffer
+	replayRecorder, err := NewRecorder(&replayCapture)
+	require.NoError(t, err)
+	replayRecorder.Attach(replay)
+
+	r, err := NewCaptureReader(bytes.NewReader(capture.Bytes()))
+	require.NoError(t, err)
+	require.NoError(t, replay.Replay(r))
+	close(replayed)
+	var handledReplay []handledPacket
+	for p := range replayed {
+		handledReplay = append(handledReplay, p)
+	}
+	require.Equal(t, handled, handledReplay)
+	require.Equal(t, captured, readCapture(t, replayCapture.Bytes()))
+	require.Equal(t, version, replay.GetVersion())
+}
+
+func TestCaptureReader_Invalid(t *testing.T) {
+	var capture bytes.Buffer
+	recorder, err := NewRecorder(&capture)
+	require.NoError(t, err)
+	conn := NewReplayConn(packet.Serverbound, func(pb PacketBuffer) error { return nil })
+	conn.SetState(packet.Config)
+	recorder.Attach(conn)
+	require.NoError(t, conn.SendPacket(&packet.ServerConfigFeatureFlags{Features: []string{"minecraft:vanilla"}}))
+	data := capture.Bytes()
+
+	// A capture cut anywhere in a packet is truncated, rather than ending early.
+	for end := len(data) - 1; end > 13; end-- {
+		r, err := NewCaptureReader(bytes.NewReader(data[:end]))
+		require.NoError(t, err)
+		_, err = r.Next()
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF, end)
+	}
+
+	_, err = NewCaptureReader(bytes.NewReader([]byte("KCAX\x01")))
+	require.ErrorContains(t, err, "not a capture file")
+	_, err = NewCaptureReader(bytes.NewReader([]byte("KCAP\x02")))
+	require.ErrorContains(t, err, "unsupported capture format 2")
+
+	// Records with an invalid direction or wire ID are rejected. The header is the first 13 bytes.
+	for _, record := range [][]byte{
+		{0x00, 0x02, byte(packet.Config), 0x01, 0x00, 0x00},
+		{0x00, byte(packet.Clientbound), byte(packet.Config), 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F, 0x00},
+	} {
+		r, err := NewCaptureReader(bytes.NewReader(append(bytes.Clone(data[:13]), record...)))
+		require.NoError(t, err)
+		_, err = r.Next()
+		require.ErrorContains(t, err, "invalid captured packet")
+	}
+}
+
+func TestRecorder_UnknownPacket(t *testing.T) {
+	var capture bytes.Buffer
+	_, err := NewRecorder(&capture)
+	require.NoError(t, err)
+	// A serverbound play packet with a wire ID unknown in the latest version, recorded like a Recorder would.
+	wireID := 0x7f
+	capture.Write([]byte{0x00, byte(packet.Serverbound), byte(packet.Play)})
+	capture.Write(binary.AppendUvarint(nil, uint64(packet.LatestVersion)))
+	capture.Write([]byte{byte(wireID), 0x02, 0x01, 0x02})
+
+	captured := readCapture(t, capture.Bytes())
+	require.Len(t, captured, 1)
+	require.Equal(t, packet.InvalidState, captured[0].ID)
+	require.Equal(t, wireID, captured[0].WireID)
+
+	// Unknown packets are replayed to the handler, and recorded again.
+	var handled []PacketBuffer
+	replay := NewReplayConn(packet.Serverbound, func(pb PacketBuffer) error {
+		handled = append(handled, pb)
+		pb.Consume()
+		return nil
+	})
+	var replayCapture bytes.Buffer
+	recorder, err := NewRecorder(&replayCapture)
+	require.NoError(t, err)
+	recorder.Attach(replay)
+	r, err := NewCaptureReader(bytes.NewReader(capture.Bytes()))
+	require.NoError(t, err)
+	require.NoError(t, replay.Replay(r))
+	require.Len(t, handled, 1)
+	require.Equal(t, packet.InvalidState, handled[0].Id)
+	require.Equal(t, captured, readCapture(t, replayCapture.Bytes()))
+}
@@ -0,0 +1,77 @@
+package kite
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/valyala/bytebufferpool"
+)
+
+const (
+	// compressionDisabled is the threshold value used when compression is not enabled.
+	compressionDisabled = -1
+
+	// maxUncompressedSize is the largest data length a compressed packet may claim, matching vanilla.
+	maxUncompressedSize = 8 * 1024 * 1024
+)
+
+var (
+	inflaterPool sync.Pool // *inflater
+	deflaterPool sync.Pool // *zlib.Writer
+
+	inflatePool bytebufferpool.Pool // Holds decompressed packet data for the duration of a handler call
+)
+
+// inflater is a reusable zlib reader along with its source, so neither needs to be allocated per packet.
+type inflater struct {
+	src bytes.Reader
+	zr  io.ReadCloser
+}
+
+// inflate decompresses src into dst, which must be exactly the expected uncompressed length.
+func inflate(dst, src []byte) (err error) {
+	inf, _ := inflaterPool.Get().(*inflater)
+	if inf == nil {
+		inf = &inflater{}
+	}
+	defer inflaterPool.Put(inf)
+
+	inf.src.Reset(src)
+	if inf.zr == nil {
+		if inf.zr, err = zlib.NewReader(&inf.src); err != nil {
+			return err
+		}
+	} else if err = inf.zr.(zlib.Resetter).Reset(&inf.src, nil); err != nil {
+		return err
+	}
+
+	if _, err = io.ReadFull(inf.zr, dst); err != nil {
+		return fmt.Errorf("failed to inflate packet: %w", err)
+	}
+	// The data length must match exactly, there must not be any trailing data.
+	var extra [1]byte
+	if n, _ := inf.zr.Read(extra[:]); n != 0 {
+		return errors.New("compressed packet is larger than declared length")
+	}
+	return nil
+}
+
+// deflate compresses src, appending the result to dst.
+func deflate(dst *bytebufferpool.ByteBuffer, src []byte) (err error) {
+	zw, _ := deflaterPool.Get().(*zlib.Writer)
+	if zw == nil {
+		zw = zlib.NewWriter(dst)
+	} else {
+		zw.Reset(dst)
+	}
+	defer deflaterPool.Put(zw)
+
+	if _, err = zw.Write(src); err != nil {
+		return err
+	}
+	return zw.Close()
+}
@@ -1,6 +1,7 @@
 package kite
 
 import (
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -8,13 +9,19 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"runtime/debug"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/mworzala/kite/internal/pkg/crypto"
 	"github.com/mworzala/kite/pkg/buffer"
 	"github.com/mworzala/kite/pkg/packet"
+	"github.com/mworzala/kite/pkg/proxyproto"
+	"github.com/mworzala/kite/pkg/text"
 	"github.com/valyala/bytebufferpool"
 )
 
@@ -26,36 +33,76 @@ const (
 	// to the max packet size in memory (for each connection).
 	maxPacketSizePreConfig = 5 * 1024
 
+	// disconnectTimeout bounds how long Disconnect waits for the message to be written, so that a
+	// remote which does not read cannot hold the connection open.
+	disconnectTimeout = 5 * time.Second
+
+	// maxFrameLengthSize is the maximum size of the frame length VarInt, enough to represent maxPacketSize.
+	maxFrameLengthSize = 3
+	// maxVarIntSize is the maximum size of any VarInt.
+	maxVarIntSize = 5
+
 	nonceLength = 16
 )
 
-var (
-	writePool     bytebufferpool.Pool
-	readCachePool bytebufferpool.Pool
-)
+var writePool bytebufferpool.Pool
+
+// DefaultPacketTooLargeReason is the disconnect reason used when a peer exceeds the packet size limits,
+// unless changed with Conn.SetPacketTooLargeReason.
+var DefaultPacketTooLargeReason text.Component = &text.Text{Text: "Packet too large"}
 
 // A Conn represents a connection to a Minecraft server or client. It wraps the
 // underlying net.Conn and provides utilities for processing and writing packets.
 type Conn struct {
 	direction packet.Direction
 	delegate  net.Conn
-	closed    bool
+	closed    atomic.Bool
 
 	// reader and writer should be used instead of directly accessing the delegate.
 	reader io.Reader
 	writer io.Writer
 	wlock  sync.Mutex
-
-	readBuffer  []byte
-	cacheBuffer *bytebufferpool.ByteBuffer // Used for caching partially read packets. Pooled in readCachePool
-
-	state   packet.State
-	handler func(pb PacketBuffer) error
+	queue  atomic.Pointer[writeQueue] // Optional, see EnableWriteQueue
+
+	// readBuffer holds received data from readStart to readEnd. It is taken from readBufferPools and
+	// grows only while a large frame is being received. Only accessed by the read loop.
+	readBuffer *[]byte
+	readStart  int
+	readEnd    int
+	readNeeded int  // Total size of the partially received frame, if its length is known
+	readHint   int  // Preferred buffer size based on recent reads, see adaptReadBuffer
+	readFull   bool // Whether the last read filled the buffer, so more data may be available immediately
+
+	// threshold is the compression threshold, or compressionDisabled. It applies to both reading and writing.
+	threshold int
+
+	state         packet.State
+	version       packet.Version
+	handler       PacketHandler
+	recoverPanics bool
+
+	// inbound and outbound are the handler and writer wrapped by their interceptors, see interceptor.go.
+	inbound              PacketHandler
+	outbound             PacketHandler
+	inboundInterceptors  []Interceptor
+	outboundInterceptors []Interceptor
+
+	packetTooLargeReason text.Component
+	legacyPingHandler    LegacyPingHandler
+
+	timeouts    Timeouts
+	stateSince  time.Time // When the current state was entered, for Timeouts.State
+	deadlineSet bool      // Whether the read loop has set a read deadline
+
+	// proxyHeader is set by the read loop once a PROXY protocol header is received, see SetProxyProtocol.
+	proxyMode   ProxyProtocolMode
+	proxyDone   bool
+	proxyHeader atomic.Pointer[proxyproto.Header]
 
 	nonce []byte // Login state
 }
 
-func NewConn(direction packet.Direction, conn net.Conn, handler func(pb PacketBuffer) error) *Conn {
+func NewConn(direction packet.Direction, conn net.Conn, handler PacketHandler) *Conn {
 	if handler == nil {
 		panic("handler must not be nil")
 	}
@@ -67,34 +114,109 @@ func NewConn(direction packet.Direction, conn net.Conn, handler func(pb PacketBu
 		writer: conn,
 		wlock:  sync.Mutex{},
 
-		// TODO: don't always allocate 4mb
-		readBuffer: make([]byte, 1024*1024*4),
+		threshold: compressionDisabled,
+
+		state:      packet.Handshake,
+		stateSince: time.Now(),
+		version:    packet.LatestVersion,
+		handler:    handler,
 
-		state:   packet.Handshake,
-		handler: handler,
+		packetTooLargeReason: DefaultPacketTooLargeReason,
 	}
+	c.inbound = handler
+	c.outbound = c.writePacketBuffer
 	return c
 }
 
+// RemoteAddr returns the address of the peer. If a PROXY protocol header was received it is the
+// source address from the header, rather than the address of the proxy.
 func (c *Conn) RemoteAddr() net.Addr {
+	if header := c.proxyHeader.Load(); header != nil && header.Source != nil {
+		return header.Source
+	}
 	return c.delegate.RemoteAddr()
 }
 
 func (c *Conn) Close() {
-	if c == nil || c.closed {
+	if c == nil || !c.closed.CompareAndSwap(false, true) {
 		return
 	}
 
-	c.closed = true
+	if q := c.queue.Load(); q != nil {
+		q.close()
+	}
 	c.delegate.Close()
 }
 
+// Disconnect sends a disconnect message with the given reason (if possible in the current state),
+// then closes the connection.
+//
+// The message is only sent to clients, in the login, config and play states. In other states, or
+// when connected to a server, the connection is closed without a message. Clients of an unsupported
+// protocol version are only sent the message during login, whose disconnect packet is the same in
+// every version.
+func (c *Conn) Disconnect(reason text.Component) (err error) {
+	if c == nil || c.closed.Load() {
+		return nil
+	}
+
+	var pkt packet.Packet
+	if c.direction == packet.Serverbound && reason != nil {
+		switch c.state {
+		case packet.Login:
+			pkt = &packet.ServerLoginDisconnect{Reason: reason}
+			if !c.version.Supported() {
+				c.SetVersion(packet.LatestVersion)
+			}
+		case packet.Config, packet.Play:
+			if c.version.Supported() {
+				pkt = &packet.ServerDisconnect{Reason: reason}
+			}
+		}
+	}
+	if pkt != nil {
+		// Packets are written synchronously, so once sent the message has been flushed to the socket.
+		_ = c.delegate.SetWriteDeadline(time.Now().Add(disconnectTimeout))
+		if err = c.SendPacket(pkt); err == nil {
+			err = c.Flush()
+		}
+	}
+
+	c.Close()
+	return err
+}
+
+// SetPacketTooLargeReason sets the disconnect reason sent when the remote exceeds the packet size limits.
+// A nil reason closes the connection without a message.
+func (c *Conn) SetPacketTooLargeReason(reason text.Component) {
+	c.packetTooLargeReason = reason
+}
+
 func (c *Conn) GetState() packet.State {
 	return c.state
 }
 
+// SetState changes the state of the connection. It takes the write lock, so that packets being written
+// (or queued) concurrently are ordered before or after the change.
 func (c *Conn) SetState(state packet.State) {
+	c.wlock.Lock()
+	defer c.wlock.Unlock()
 	c.state = state
+	c.stateSince = time.Now()
+}
+
+// GetVersion returns the protocol version of the connection. It is taken from the handshake, whether
+// received or sent, and defaults to packet.LatestVersion.
+func (c *Conn) GetVersion() packet.Version {
+	return c.version
+}
+
+// SetVersion sets the protocol version used to translate packet IDs and encode packets. It is only
+// needed if the handshake is not sent or received through this connection.
+func (c *Conn) SetVersion(version packet.Version) {
+	c.wlock.Lock()
+	defer c.wlock.Unlock()
+	c.version = version
 }
 
 func (c *Conn) GetNonce() []byte {
@@ -116,16 +238,47 @@ func (c *Conn) EnableEncryption(sharedSecret []byte) error {
 	cfb := crypto.NewCFB8Decrypt(block, sharedSecret)
 	c.reader = &cipher.StreamReader{S: cfb, R: c.reader}
 
+	// Anything already queued must be written before the writer is replaced.
+	c.wlock.Lock()
+	defer c.wlock.Unlock()
+	if err = c.Flush(); err != nil {
+		return err
+	}
+
 	cfb = crypto.NewCFB8Encrypt(block, sharedSecret)
 	c.writer = &cipher.StreamWriter{S: cfb, W: c.writer}
 
 	return nil
 }
 
+// EnableCompression switches both the read and write paths to the compressed packet format. Packets
+// with an uncompressed size of at least threshold bytes will be compressed when written. A negative
+// threshold disables compression again.
+//
+// The Set Compression packet must be sent (or received) before calling this method, it is not sent automatically.
+func (c *Conn) EnableCompression(threshold int) {
+	c.wlock.Lock()
+	defer c.wlock.Unlock()
+
+	if threshold < 0 {
+		threshold = compressionDisabled
+	}
+	c.threshold = threshold
+}
+
+// GetCompressionThreshold returns the current compression threshold, or a negative value if disabled.
+func (c *Conn) GetCompressionThreshold() int {
+	return c.threshold
+}
+
+// ForwardPacket writes a packet received on another connection, passing it through the outbound
+// interceptors. The packet is consumed, even if an interceptor drops it.
+//
+// If the connections use different protocol versions, the packet ID is translated but the payload
+// is forwarded as is.
 func (c *Conn) ForwardPacket(pb PacketBuffer) (err error) {
-	// Write the raw packet to the remote
-	pb.internal.Reset(pb.mark)
-	_, err = c.writer.Write(pb.internal.RemainingSlice())
+	err = c.outbound(pb)
+	pb.Consume()
 	if errors.Is(err, syscall.EPIPE) || errors.Is(err, net.ErrClosed) {
 		c.Close()
 		return
@@ -134,7 +287,7 @@ func (c *Conn) ForwardPacket(pb PacketBuffer) (err error) {
 }
 
 func (c *Conn) SendPacket(pkt packet.Packet) (err error) {
-	if c.closed {
+	if c.closed.Load() {
 		return io.EOF
 	}
 
@@ -145,140 +298,371 @@ func (c *Conn) SendPacket(pkt packet.Packet) (err error) {
 	if pktId < 0 {
 		return fmt.Errorf("packet %T is not applicable to state %s", pkt, c.state.String())
 	}
+	if handshake, ok := pkt.(*packet.ClientHandshake); ok {
+		c.SetVersion(packet.Version(handshake.ProtocolVersion))
+	}
+	wireId, ok := c.version.WireID(c.state, pkt.Direction(), pktId)
+	if !ok {
+		return fmt.Errorf("packet %T is not available in version %s", pkt, c.version.String())
+	}
 
 	// Write the packet (without length prefix, it will be written depending on compression during write)
 	buf := writePool.Get()
 	defer writePool.Put(buf) // Always return the buffer to the pool
 
-	if err = buffer.VarInt.Write(buf, int32(pktId)); err != nil {
+	if err = buffer.VarInt.Write(buf, int32(wireId)); err != nil {
 		return
 	}
-	if err = pkt.Write(buf); err != nil {
+	if err = packet.Write(buf, pkt, c.version); err != nil {
 		return
 	}
 
-	if err = c.writePacketSync(buf.B); err != nil {
+	return c.outbound(wrapPacket(pktId, buf.B, c.version))
+}
+
+// writePacketBuffer writes a packet after it has passed through the outbound interceptors.
+func (c *Conn) writePacketBuffer(pb PacketBuffer) (err error) {
+	pb.internal.Reset(pb.mark)
+	data := pb.internal.RemainingSlice()
+
+	if pb.version != c.version {
+		translated := writePool.Get()
+		defer writePool.Put(translated)
+		if err = c.translatePacket(translated, pb); err != nil {
+			return
+		}
+		data, pb.frame = translated.B, nil
+	}
+
+	c.lockWriter()
+	defer c.wlock.Unlock()
+
+	if pb.frame != nil && pb.threshold == c.threshold {
+		// Both sides use the same framing, so the raw frame can be written as is.
+		return c.writeLocked(pb.frame, pb.Id)
+	}
+
+	frame := writePool.Get()
+	defer writePool.Put(frame)
+	if err = c.appendFrame(frame, data); err != nil {
 		return
 	}
+	return c.writeLocked(frame.B, pb.Id)
+}
 
-	return nil
+// translatePacket writes pb, encoded for another protocol version, to buf as encoded for the version of c.
+// The wire ID may differ between versions, and so may the payload of packets implementing
+// packet.VersionedPacket, which are decoded and encoded again. Packets not registered in
+// packet.DefaultRegistry cannot be translated, as their encoding in either version is not known.
+func (c *Conn) translatePacket(buf *bytebufferpool.ByteBuffer, pb PacketBuffer) error {
+	direction := packet.Clientbound
+	if c.direction == packet.Clientbound {
+		direction = packet.Serverbound
+	}
+	pkt, ok := packet.DefaultRegistry.Lookup(c.state, direction, pb.Id)
+	if pb.Id == packet.InvalidState || !ok {
+		return fmt.Errorf("%w: cannot translate packet %d from version %s to %s", ErrUnknownPacket,
+			pb.Id, pb.version.String(), c.version.String())
+	}
+	wireId, ok := c.version.WireID(c.state, direction, pb.Id)
+	if !ok {
+		return fmt.Errorf("packet %d is not available in version %s", pb.Id, c.version.String())
+	}
+	if err := buffer.VarInt.Write(buf, int32(wireId)); err != nil {
+		return err
+	}
+	if _, ok := pkt.(packet.VersionedPacket); !ok {
+		buf.B = append(buf.B, pb.payload()...)
+		return nil
+	}
+
+	payload := buffer.Wrap(pb.payload())
+	if err := packet.Read(payload, pkt, pb.version); err != nil {
+		return fmt.Errorf("translating packet %T from version %s: %w", pkt, pb.version.String(), err)
+	}
+	if payload.Remaining() > 0 {
+		return fmt.Errorf("translating packet %T from version %s: %w", pkt, pb.version.String(), ErrUnconsumedPacket)
+	}
+	return packet.Write(buf, pkt, c.version)
 }
 
-func (c *Conn) writePacketSync(buf []byte) (err error) {
+// lockWriter takes wlock to write a frame, first waiting for room in the write queue if its policy blocks.
+func (c *Conn) lockWriter() {
+	if q := c.queue.Load(); q != nil {
+		q.wait()
+	}
 	c.wlock.Lock()
-	defer c.wlock.Unlock()
+}
+
+// writeLocked writes data to the remote, or adds it to the write queue if enabled. id is the ID of the
+// packet contained in data, or -1 if it is not a packet. Must be called while holding wlock.
+func (c *Conn) writeLocked(data []byte, id int) error {
+	q := c.queue.Load()
+	if q == nil {
+		_, err := c.writer.Write(data)
+		return err
+	}
 
-	if err = buffer.VarInt.Write(c.writer, int32(len(buf))); err != nil {
+	err := q.enqueue(data, c.state, id)
+	if errors.Is(err, ErrWriteQueueFull) {
+		c.Close()
+	}
+	return err
+}
+
+// appendFrame appends the length prefixed (and possibly compressed) frame for data to frame.
+// Must be called while holding wlock so the compression threshold cannot change.
+func (c *Conn) appendFrame(frame *bytebufferpool.ByteBuffer, data []byte) (err error) {
+	if c.threshold < 0 {
+		if err = buffer.VarInt.Write(frame, int32(len(data))); err != nil {
+			return
+		}
+		_, err = frame.Write(data)
 		return
 	}
 
-	n := len(buf)
-	for n > 0 {
-		written, err := c.writer.Write(buf)
-		n -= written
-		if err != nil {
-			return err
+	if len(data) < c.threshold {
+		// Below the threshold the packet is sent uncompressed with a data length of zero.
+		if err = buffer.VarInt.Write(frame, int32(len(data)+1)); err != nil {
+			return
 		}
+		if err = buffer.VarInt.Write(frame, 0); err != nil {
+			return
+		}
+		_, err = frame.Write(data)
+		return
 	}
-	return nil
+
+	compressed := writePool.Get()
+	defer writePool.Put(compressed)
+	if err = buffer.VarInt.Write(compressed, int32(len(data))); err != nil {
+		return
+	}
+	if err = deflate(compressed, data); err != nil {
+		return
+	}
+	if err = buffer.VarInt.Write(frame, int32(len(compressed.B))); err != nil {
+		return
+	}
+	_, err = frame.Write(compressed.B)
+	return
 }
 
+// ReadLoop serves the connection until it is closed or fails, then closes it.
+// Errors are printed, use Serve to handle them instead.
 func (c *Conn) ReadLoop() {
-	defer func() {
-		if r := recover(); r != nil {
-			println(fmt.Sprintf("panic in readLoop: %v\n", r))
-			debug.PrintStack()
-			if c != nil {
-				c.Close()
+	if err := c.Serve(context.Background()); err != nil {
+		println(err.Error())
+	}
+	c.Close()
+}
+
+// Serve reads and handles packets until the connection is closed, an error occurs, or ctx is cancelled.
+//
+// A nil error is returned if the connection was closed, either by the remote or by calling Close. If ctx
+// is cancelled, its error is returned. Otherwise, the error describes why reading stopped, for example
+// ErrPacketTooLarge, ErrMalformedFrame, ErrLegacyPing (see SetLegacyPingHandler) or a *PacketError
+// wrapping a handler error.
+//
+// If the remote exceeds the packet size limits or a timeout (see SetPacketTooLargeReason and SetTimeouts),
+// it is disconnected before ErrPacketTooLarge or ErrTimeout is returned. For any other error the connection
+// is left open so that the caller may decide whether to send a disconnect message. It is the responsibility
+// of the caller to Close it.
+func (c *Conn) Serve(ctx context.Context) (err error) {
+	if c.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				println(fmt.Sprintf("panic in readLoop: %v\n", r))
+				debug.PrintStack()
+				err = fmt.Errorf("panic in read loop: %v", r)
 			}
+		}()
+	}
+
+	// Interrupt any pending read when the context is cancelled.
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.delegate.SetReadDeadline(time.Unix(1, 0))
+	})
+	defer func() {
+		if !stop() || c.deadlineSet {
+			_ = c.delegate.SetReadDeadline(time.Time{})
+			c.deadlineSet = false
 		}
 	}()
+
+	// The buffer is kept if Serve stops in the middle of a frame, so that it can be resumed.
+	defer c.releaseReadBuffer()
+
 	for {
-		var start int
-		if c.cacheBuffer != nil {
-			start = copy(c.readBuffer, c.cacheBuffer.B)
-			readCachePool.Put(c.cacheBuffer)
-			c.cacheBuffer = nil
+		c.ensureReadBuffer(c.readBufferSize())
+		if err = c.updateReadDeadline(ctx); err != nil {
+			return err
 		}
 
-		n, err := c.reader.Read(c.readBuffer[start:])
+		n, readErr := c.reader.Read((*c.readBuffer)[c.readEnd:])
 		if n > 0 {
-			buf := buffer.Wrap(c.readBuffer[:start+n])
-
-			c.processPackets(buf)
-			if c.closed {
-				return
-			}
-
-			if buf.Remaining() > 0 {
-				c.cacheBuffer = readCachePool.Get()
-				buf.AllocRemainderTo(c.cacheBuffer)
+			c.adaptReadBuffer(n, len(*c.readBuffer)-c.readEnd)
+			c.readEnd += n
+			if err = c.processPackets(); err != nil || c.closed.Load() {
+				if errors.Is(err, ErrPacketTooLarge) {
+					_ = c.Disconnect(c.packetTooLargeReason)
+				}
+				return err
 			}
 		}
-		if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
-			c.Close()
-			return
-		} else if err != nil {
-			//todo handle appropriately and close connection.
-			panic(err)
+		if readErr != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if c.deadlineSet && errors.Is(readErr, os.ErrDeadlineExceeded) {
+				err = fmt.Errorf("%w in %s state", ErrTimeout, c.state.String())
+				_ = c.Disconnect(c.timeouts.Reason)
+				return err
+			}
+			if c.closed.Load() || errors.Is(readErr, io.EOF) || errors.Is(readErr, net.ErrClosed) || errors.Is(readErr, io.ErrClosedPipe) {
+				c.Close()
+				return nil
+			}
+			return readErr
 		}
 	}
 }
 
-func (c *Conn) processPackets(buf *buffer.Buffer) {
+// SetRecoverPanics controls whether panics in packet handlers are recovered by Serve. When enabled, the
+// panic and its stack trace are printed and Serve returns an error instead of crashing the program.
+func (c *Conn) SetRecoverPanics(enabled bool) {
+	c.recoverPanics = enabled
+}
+
+// processPackets handles every complete frame in the read buffer. If the buffer ends with a partial
+// frame, readStart is left at the start of it and readNeeded is set to its size if known.
+func (c *Conn) processPackets() error {
+	data := (*c.readBuffer)[:c.readEnd]
+	buf := buffer.Wrap(data)
+	buf.Reset(c.readStart)
+	c.readNeeded = 0
+
+	if n, ok, err := c.readProxyHeader(data[c.readStart:]); err != nil || !ok {
+		return err
+	} else if n > 0 {
+		buf.Reset(c.readStart + n)
+	}
+
 	for {
+		c.readStart = buf.Mark()
 		if buf.Remaining() == 0 {
-			return
+			return nil
 		}
 
 		packetStart := buf.Mark()
-		length, err := buffer.VarInt.Read(buf)
-		if err != nil {
-			panic(err)
+
+		// Legacy (pre-netty) clients start with 0xFE rather than a packet length.
+		if c.state == packet.Handshake && data[packetStart] == 0xFE {
+			return c.handleLegacyPing(data[packetStart:])
 		}
 
-		// We just do not support legacy clients for now, just close the connection
-		if c.state == packet.Handshake && length == 0xFE {
-			c.Close()
-			return
+		length, err := buffer.VarInt.Read(buf)
+		if errors.Is(err, buffer.ErrBufferOverflow) && buf.Mark()-packetStart < maxVarIntSize {
+			// The length prefix itself is incomplete, wait for more data. The whole VarInt is read before
+			// deciding whether it is valid, so that the result does not depend on how the data was split.
+			buf.Reset(packetStart)
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%w: invalid length: %w", ErrMalformedFrame, err)
+		}
+		if length <= 0 {
+			return fmt.Errorf("%w: invalid length %d", ErrMalformedFrame, length)
 		}
 
 		// See comment on maxPacketSizePreConfig
-		if length > maxPacketSize {
-			//todo this should disconnect with a message most likely.
-			c.Close()
-			return
-		} else if c.state <= packet.Login && length > maxPacketSizePreConfig {
-			c.Close()
-			return
+		if length > maxPacketSize || (c.state <= packet.Login && length > maxPacketSizePreConfig) {
+			return fmt.Errorf("%w: %d bytes in %s state", ErrPacketTooLarge, length, c.state.String())
+		}
+		if buf.Mark()-packetStart > maxFrameLengthSize {
+			return fmt.Errorf("%w: length prefix is %d bytes", ErrMalformedFrame, buf.Mark()-packetStart)
 		}
 
-		// If the packet contains more data than is available in the buffer, cache the remainder.
+		// If the packet contains more data than is available, wait for the remainder.
+		frameEnd := buf.Mark() + int(length)
 		if int(length) > buf.Remaining() {
-			buf.Reset(packetStart)
-			buf.Limit(-1)
-			return
+			c.readNeeded = frameEnd - packetStart
+			return nil
 		}
 		buf.Limit(int(length)) // Cap the read buffer to the packet length
 
-		packetID, err := buffer.VarInt.Read(buf)
-		if errors.Is(err, net.ErrClosed) {
-			c.Close()
-			return
-		} else if err != nil {
-			panic(err)
+		// With compression enabled the packet is prefixed by its uncompressed length, or zero if not compressed.
+		pkt, inflated := buf, (*bytebufferpool.ByteBuffer)(nil)
+		if c.threshold >= 0 {
+			dataLength, err := buffer.VarInt.Read(buf)
+			if err != nil {
+				return fmt.Errorf("%w: invalid data length: %w", ErrMalformedFrame, err)
+			}
+			if dataLength != 0 {
+				// The uncompressed size is bound by the same limit as the frame, see maxPacketSizePreConfig
+				if dataLength > maxUncompressedSize || (c.state <= packet.Login && dataLength > maxPacketSizePreConfig) {
+					return fmt.Errorf("%w: %d bytes uncompressed in %s state", ErrPacketTooLarge, dataLength, c.state.String())
+				} else if dataLength < int32(c.threshold) {
+					return fmt.Errorf("%w: invalid data length %d (threshold %d)", ErrMalformedFrame, dataLength, c.threshold)
+				}
+				inflated = inflatePool.Get()
+				inflated.B = slices.Grow(inflated.B[:0], int(dataLength))[:dataLength]
+				if err = inflate(inflated.B, buf.RemainingSlice()); err != nil {
+					inflatePool.Put(inflated)
+					return fmt.Errorf("%w: %w", ErrMalformedFrame, err)
+				}
+				pkt = buffer.Wrap(inflated.B)
+			}
 		}
 
-		err = c.handler(PacketBuffer{Id: int(packetID), internal: buf, mark: packetStart})
+		mark := pkt.Mark()
+		wireID, err := buffer.VarInt.Read(pkt)
 		if err != nil {
-			println(fmt.Errorf("packet processing failed: %w (%s/%s/%d)", err, c.direction.String(), c.state.String(), packetID).Error())
-			c.Close()
-			return
+			if inflated != nil {
+				inflatePool.Put(inflated)
+			}
+			return fmt.Errorf("%w: invalid packet id: %w", ErrMalformedFrame, err)
 		}
-		if buf.Remaining() > 0 {
-			panic(fmt.Errorf("%s: %s/%s/%d", "packet not fully read", c.direction.String(), c.state.String(), packetID))
+		if c.state == packet.Handshake && c.direction == packet.Serverbound && wireID == packet.ClientHandshakeHandshakeID {
+			c.readHandshakeVersion(pkt)
+		}
+		// Packets unknown to the version (or of an unsupported version) are passed on with InvalidState as
+		// their ID, so that they can still be forwarded as is.
+		packetID, ok := c.version.PacketID(c.state, c.direction, int(wireID))
+		if !ok {
+			packetID = packet.InvalidState
 		}
 
+		err = c.inbound(PacketBuffer{
+			Id:        packetID,
+			internal:  pkt,
+			mark:      mark,
+			version:   c.version,
+			frame:     data[packetStart:frameEnd],
+			threshold: c.threshold,
+		})
+		if inflated != nil {
+			inflatePool.Put(inflated)
+		}
+		if err == nil && pkt.Remaining() > 0 {
+			err = ErrUnconsumedPacket
+		}
+		if err != nil {
+			return &PacketError{Direction: c.direction, State: c.state, ID: packetID, Err: err}
+		}
+		buf.Reset(frameEnd)
 		buf.Limit(-1)
+		if c.closed.Load() {
+			c.readStart = frameEnd
+			return nil
+		}
+	}
+}
+
+// readHandshakeVersion takes the protocol version from a received handshake before it is handled.
+func (c *Conn) readHandshakeVersion(pkt *buffer.Buffer) {
+	mark := pkt.Mark()
+	if version, err := buffer.VarInt.Read(pkt); err == nil {
+		c.SetVersion(packet.Version(version))
 	}
+	pkt.Reset(mark)
 }
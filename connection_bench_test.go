@@ -0,0 +1,142 @@
+package kite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/packet"
+)
+
+// memConn is a net.Conn reading from an in memory stream. Writes are discarded.
+type memConn struct {
+	net.Conn
+	r io.Reader
+
+	// If set, reads block on idle once r is exhausted instead of returning io.EOF.
+	idle chan struct{}
+}
+
+func (c *memConn) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if errors.Is(err, io.EOF) && c.idle != nil {
+		<-c.idle
+	}
+	return n, err
+}
+func (c *memConn) Write(p []byte) (int, error)         { return len(p), nil }
+func (c *memConn) Close() error                        { return nil }
+func (c *memConn) SetReadDeadline(_ time.Time) error   { return nil }
+func (c *memConn) RemoteAddr() net.Addr                { return &net.TCPAddr{} }
+func (c *memConn) SetWriteDeadline(_ time.Time) error  { return nil }
+func (c *memConn) SetDeadline(_ time.Time) error       { return nil }
+func (c *memConn) LocalAddr() net.Addr                 { return &net.TCPAddr{} }
+func (c *memConn) setIdle(idle chan struct{}) *memConn { c.idle = idle; return c }
+
+// repeatReader endlessly repeats data, returning at most chunk bytes per read like a TCP socket would.
+type repeatReader struct {
+	data  []byte
+	pos   int
+	chunk int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if len(p) > r.chunk {
+		p = p[:r.chunk]
+	}
+	n := copy(p, r.data[r.pos:])
+	r.pos = (r.pos + n) % len(r.data)
+	return n, nil
+}
+
+// encodeFrames returns count uncompressed frames each containing a packet with the given payload size.
+func encodeFrames(count, size int) []byte {
+	var out bytes.Buffer
+	payload := bytes.Repeat([]byte{0x42}, size)
+	for range count {
+		_ = buffer.VarInt.Write(&out, int32(size+1))
+		out.WriteByte(0x00)
+		out.Write(payload)
+	}
+	return out.Bytes()
+}
+
+var errBenchDone = errors.New("done")
+
+func BenchmarkConn_Serve(b *testing.B) {
+	for _, size := range []int{16, 1024, 64 * 1024} {
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			data := encodeFrames(max(1, 256*1024/size), size)
+			conn := &memConn{r: &repeatReader{data: data, chunk: 16 * 1024}}
+
+			var handled int
+			c := NewConn(packet.Serverbound, conn, func(pb PacketBuffer) error {
+				pb.Consume()
+				if handled++; handled == b.N {
+					return errBenchDone
+				}
+				return nil
+			})
+			c.SetState(packet.Play)
+
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			b.ResetTimer()
+			if err := c.Serve(context.Background()); !errors.Is(err, errBenchDone) {
+				b.Fatal(err)
+			}
+		})
+	}
+}
+
+// BenchmarkConn_IdleMemory reports the heap retained by each connection once it has handled
+// some traffic and is waiting for more.
+func BenchmarkConn_IdleMemory(b *testing.B) {
+	for _, size := range []int{16, 64 * 1024} {
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			data := encodeFrames(4, size)
+			idle := make(chan struct{})
+			var handled, done sync.WaitGroup
+
+			conns := make([]*Conn, b.N)
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			handled.Add(b.N)
+			done.Add(b.N)
+			for i := range conns {
+				var count int
+				conns[i] = NewConn(packet.Serverbound, (&memConn{r: bytes.NewReader(data)}).setIdle(idle), func(pb PacketBuffer) error {
+					pb.Consume()
+					if count++; count == 4 {
+						handled.Done()
+					}
+					return nil
+				})
+				conns[i].SetState(packet.Play)
+				go func(c *Conn) {
+					defer done.Done()
+					_ = c.Serve(context.Background())
+				}(conns[i])
+			}
+			handled.Wait()
+
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/float64(b.N), "B/conn")
+
+			close(idle)
+			done.Wait()
+			runtime.KeepAlive(conns)
+		})
+	}
+}
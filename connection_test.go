@@ -0,0 +1,569 @@
+package kite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/mworzala/kite/pkg/text"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/bytebufferpool"
+)
+
+// newPipe creates a pair of connections, where the client sends serverbound packets to the server.
+func newPipe(t *testing.T, handler func(pb PacketBuffer) error) (client, server *Conn) {
+	t.Helper()
+	cc, sc := net.Pipe()
+	client = NewConn(packet.Clientbound, cc, func(pb PacketBuffer) error {
+		pb.Consume()
+		return nil
+	})
+	server = NewConn(packet.Serverbound, sc, handler)
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	go client.ReadLoop()
+	go server.ReadLoop()
+	return
+}
+
+// collectPluginMessages returns a handler decoding every packet as a config plugin message.
+func collectPluginMessages(out chan<- *packet.ClientPluginMessage) func(pb PacketBuffer) error {
+	return func(pb PacketBuffer) error {
+		pkt := new(packet.ClientPluginMessage)
+		if err := pb.Read(pkt); err != nil {
+			return err
+		}
+		out <- pkt
+		return nil
+	}
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for packet")
+		panic("unreachable")
+	}
+}
+
+func TestConn_Compression(t *testing.T) {
+	received := make(chan *packet.ClientPluginMessage, 4)
+	client, server := newPipe(t, collectPluginMessages(received))
+	for _, c := range []*Conn{client, server} {
+		c.SetState(packet.Config)
+		c.EnableCompression(64)
+	}
+
+	small := &packet.ClientPluginMessage{Channel: "kite:small", Data: []byte("hello")}
+	large := &packet.ClientPluginMessage{Channel: "kite:large", Data: bytes.Repeat([]byte("kite"), 1024)}
+	require.NoError(t, client.SendPacket(small))
+	require.NoError(t, client.SendPacket(large))
+
+	require.Equal(t, small, receive(t, received))
+	require.Equal(t, large, receive(t, received))
+}
+
+func TestConn_ForwardPacket_DifferentThresholds(t *testing.T) {
+	tests := []struct {
+		name         string
+		src, forward int
+	}{
+		{"compressed to uncompressed", 16, compressionDisabled},
+		{"uncompressed to compressed", compressionDisabled, 16},
+		{"different thresholds", 16, 1024},
+		{"same threshold", 16, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan *packet.ClientPluginMessage, 4)
+			remote, backend := newPipe(t, collectPluginMessages(received))
+
+			var client, proxy *Conn
+			client, proxy = newPipe(t, func(pb PacketBuffer) error {
+				return remote.ForwardPacket(pb)
+			})
+
+			for _, c := range []*Conn{client, proxy, remote, backend} {
+				c.SetState(packet.Config)
+			}
+			client.EnableCompression(tt.src)
+			proxy.EnableCompression(tt.src)
+			remote.EnableCompression(tt.forward)
+			backend.EnableCompression(tt.forward)
+
+			for _, size := range []int{0, 15, 16, 100, 4096} {
+				pkt := &packet.ClientPluginMessage{Channel: "kite:test", Data: bytes.Repeat([]byte{0x42}, size)}
+				require.NoError(t, client.SendPacket(pkt))
+
+				got := receive(t, received)
+				require.Equal(t, pkt.Channel, got.Channel)
+				require.Len(t, got.Data, size)
+			}
+		})
+	}
+}
+
+// serveRaw writes data to a fresh server connection and returns the result of Serve.
+func serveRaw(t *testing.T, state packet.State, handler func(pb PacketBuffer) error, data []byte) error {
+	t.Helper()
+	cc, sc := net.Pipe()
+	server := NewConn(packet.Serverbound, sc, handler)
+	server.SetState(state)
+	t.Cleanup(func() {
+		_ = cc.Close()
+		server.Close()
+	})
+
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(context.Background()) }()
+	go func() { _, _ = io.Copy(io.Discard, cc) }()
+	go func() { _, _ = cc.Write(data) }()
+	return receive(t, result)
+}
+
+func TestConn_Serve_Errors(t *testing.T) {
+	consume := func(pb PacketBuffer) error {
+		pb.Consume()
+		return nil
+	}
+	handlerErr := errors.New("handler failed")
+
+	tests := []struct {
+		name    string
+		state   packet.State
+		handler func(pb PacketBuffer) error
+		data    []byte
+		err     error
+	}{
+		{"too large pre config", packet.Login, consume, []byte{0x80, 0x80, 0x01}, ErrPacketTooLarge},
+		{"too large", packet.Play, consume, []byte{0xff, 0xff, 0xff, 0x01}, ErrPacketTooLarge},
+		{"negative length", packet.Play, consume, []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, ErrMalformedFrame},
+		{"zero length", packet.Play, consume, []byte{0x00}, ErrMalformedFrame},
+		{"wide length", packet.Play, consume, []byte{0x81, 0x80, 0x80, 0x00, 0x00}, ErrMalformedFrame},
+		{"legacy ping", packet.Handshake, consume, []byte{0xfe, 0x01}, ErrLegacyPing},
+		{"unconsumed", packet.Play, func(pb PacketBuffer) error { return nil }, []byte{0x02, 0x00, 0x01}, ErrUnconsumedPacket},
+		{"handler error", packet.Play, func(pb PacketBuffer) error { return handlerErr }, []byte{0x01, 0x05}, handlerErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := serveRaw(t, tt.state, tt.handler, tt.data)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestConn_Serve_CompressedPreConfigLimit(t *testing.T) {
+	cc, sc := net.Pipe()
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error {
+		pb.Consume()
+		return nil
+	})
+	server.SetState(packet.Login)
+	server.EnableCompression(256)
+	t.Cleanup(func() {
+		_ = cc.Close()
+		server.Close()
+	})
+
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(context.Background()) }()
+	go func() { _, _ = io.Copy(io.Discard, cc) }()
+	// A small frame claiming 6000 bytes uncompressed, above the pre config limit.
+	go func() { _, _ = cc.Write([]byte{0x03, 0xf0, 0x2e, 0x00}) }()
+	require.ErrorIs(t, receive(t, result), ErrPacketTooLarge)
+}
+
+func TestConn_Serve_PacketError(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+	err := serveRaw(t, packet.Play, func(pb PacketBuffer) error { return handlerErr }, []byte{0x01, 0x05})
+
+	var pe *PacketError
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, packet.Serverbound, pe.Direction)
+	require.Equal(t, packet.Play, pe.State)
+	require.Equal(t, 5, pe.ID)
+}
+
+func TestConn_Serve_Closed(t *testing.T) {
+	cc, sc := net.Pipe()
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error { return nil })
+
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(context.Background()) }()
+	require.NoError(t, cc.Close())
+	require.NoError(t, receive(t, result))
+}
+
+func TestConn_Serve_ContextCancelled(t *testing.T) {
+	cc, sc := net.Pipe()
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error {
+		pb.Consume()
+		return nil
+	})
+	t.Cleanup(func() {
+		_ = cc.Close()
+		server.Close()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(ctx) }()
+	cancel()
+	require.ErrorIs(t, receive(t, result), context.Canceled)
+
+	// The connection is left usable after cancellation
+	go func() { result <- server.Serve(context.Background()) }()
+	_, err := cc.Write([]byte{0x01, 0x00})
+	require.NoError(t, err)
+	require.NoError(t, cc.Close())
+	require.NoError(t, receive(t, result))
+}
+
+func TestConn_Serve_RecoverPanics(t *testing.T) {
+	cc, sc := net.Pipe()
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error { panic("oops") })
+	server.SetState(packet.Play)
+	server.SetRecoverPanics(true)
+	t.Cleanup(func() {
+		_ = cc.Close()
+		server.Close()
+	})
+
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(context.Background()) }()
+	go func() { _, _ = cc.Write([]byte{0x01, 0x00}) }()
+	require.ErrorContains(t, receive(t, result), "oops")
+}
+
+// chunkReader returns data at most chunk bytes at a time.
+type chunkReader struct {
+	data  []byte
+	chunk int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p[:min(len(p), r.chunk)], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestConn_ReadBuffer(t *testing.T) {
+	sizes := []int{10, 100_000, 10, 1_000_000, 5000, 10}
+	var data []byte
+	for _, size := range sizes {
+		data = append(data, encodeFrames(1, size)...)
+	}
+
+	var received []int
+	var bufferSizes []int
+	var c *Conn
+	c = NewConn(packet.Serverbound, &memConn{r: &chunkReader{data: data, chunk: 1500}}, func(pb PacketBuffer) error {
+		received = append(received, len(pb.internal.RemainingSlice()))
+		bufferSizes = append(bufferSizes, len(*c.readBuffer))
+		return nil
+	})
+	c.SetState(packet.Play)
+
+	require.NoError(t, c.Serve(context.Background()))
+	require.Equal(t, sizes, received)
+
+	// Large frames require a larger buffer, but it shrinks again once they have been handled.
+	require.GreaterOrEqual(t, bufferSizes[3], 1_000_000)
+	require.LessOrEqual(t, bufferSizes[5], 2*minReadBufferSize)
+	require.Nil(t, c.readBuffer, "buffer should be returned to the pool")
+}
+
+// sizeReader records the size of the buffer passed to each read of r.
+type sizeReader struct {
+	r     io.Reader
+	sizes []int
+}
+
+func (r *sizeReader) Read(p []byte) (int, error) {
+	r.sizes = append(r.sizes, len(p))
+	return r.r.Read(p)
+}
+
+func TestConn_ReadBuffer_Idle(t *testing.T) {
+	reader := &sizeReader{r: &chunkReader{data: encodeFrames(2000, 100), chunk: 1 << 20}}
+	c := NewConn(packet.Serverbound, &memConn{r: reader}, func(pb PacketBuffer) error {
+		pb.Consume()
+		return nil
+	})
+	c.SetState(packet.Play)
+	require.NoError(t, c.Serve(context.Background()))
+
+	// Reads grow the buffer while they fill it, and the read after the data runs out (where an idle
+	// connection would block) uses the smallest buffer.
+	require.Greater(t, slices.Max(reader.sizes), minReadBufferSize)
+	require.Equal(t, minReadBufferSize, reader.sizes[len(reader.sizes)-1])
+}
+
+func TestConn_ReadBuffer_PreConfigLimit(t *testing.T) {
+	data := encodeFrames(64, 1000)
+	var c *Conn
+	c = NewConn(packet.Serverbound, &memConn{r: &chunkReader{data: data, chunk: 1 << 20}}, func(pb PacketBuffer) error {
+		pb.Consume()
+		require.LessOrEqual(t, len(*c.readBuffer), 2*(maxPacketSizePreConfig+maxFrameLengthSize))
+		return nil
+	})
+	c.SetState(packet.Login)
+	require.NoError(t, c.Serve(context.Background()))
+}
+
+func TestConn_Disconnect(t *testing.T) {
+	reason := &text.Text{Text: "Goodbye", S: text.Style{Color: text.Red}}
+	tests := []struct {
+		state    packet.State
+		version  packet.Version
+		expected packet.Packet
+	}{
+		{packet.Handshake, packet.LatestVersion, nil},
+		{packet.Status, packet.LatestVersion, nil},
+		{packet.Login, packet.LatestVersion, &packet.ServerLoginDisconnect{Reason: reason}},
+		{packet.Config, packet.LatestVersion, &packet.ServerDisconnect{Reason: reason}},
+		{packet.Play, packet.LatestVersion, &packet.ServerDisconnect{Reason: reason}},
+		// The login disconnect is the same in every version, later ones are unknown.
+		{packet.Login, packet.LatestVersion + 1, &packet.ServerLoginDisconnect{Reason: reason}},
+		{packet.Play, packet.LatestVersion + 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d", tt.state.String(), tt.version), func(t *testing.T) {
+			cc, sc := net.Pipe()
+			received := make(chan packet.Packet, 1)
+			client := NewConn(packet.Clientbound, cc, func(pb PacketBuffer) error {
+				var pkt packet.Packet = new(packet.ServerDisconnect)
+				if tt.state == packet.Login {
+					pkt = new(packet.ServerLoginDisconnect)
+				}
+				if err := pb.Read(pkt); err != nil {
+					return err
+				}
+				received <- pkt
+				return nil
+			})
+			server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error { return nil })
+			client.SetState(tt.state)
+			server.SetState(tt.state)
+			server.SetVersion(tt.version)
+
+			result := make(chan error, 1)
+			go func() { result <- client.Serve(context.Background()) }()
+
+			require.NoError(t, server.Disconnect(reason))
+			require.NoError(t, receive(t, result))
+			if tt.expected == nil {
+				require.Empty(t, received)
+			} else {
+				require.Equal(t, tt.expected, receive(t, received))
+			}
+		})
+	}
+}
+
+func TestConn_Disconnect_PacketTooLarge(t *testing.T) {
+	reason := &text.Text{Text: "Too big!"}
+	cc, sc := net.Pipe()
+	received := make(chan *packet.ServerLoginDisconnect, 1)
+	client := NewConn(packet.Clientbound, cc, func(pb PacketBuffer) error {
+		pkt := new(packet.ServerLoginDisconnect)
+		if err := pb.Read(pkt); err != nil {
+			return err
+		}
+		received <- pkt
+		return nil
+	})
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error { return nil })
+	client.SetState(packet.Login)
+	server.SetState(packet.Login)
+	server.SetPacketTooLargeReason(reason)
+
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(context.Background()) }()
+	go func() { _ = client.Serve(context.Background()) }()
+
+	// Claim a packet bigger than allowed before the config state
+	_, err := cc.Write([]byte{0x80, 0x80, 0x01})
+	require.NoError(t, err)
+
+	require.Equal(t, reason, receive(t, received).Reason)
+	require.ErrorIs(t, receive(t, result), ErrPacketTooLarge)
+}
+
+func TestConn_Version(t *testing.T) {
+	forwarded := newGateConn()
+	close(forwarded.gate)
+	backend := NewConn(packet.Clientbound, forwarded, func(pb PacketBuffer) error { return nil })
+	backend.SetState(packet.Play)
+
+	received := make(chan PacketBuffer, 1)
+	var server *Conn
+	client, server := newPipe(t, func(pb PacketBuffer) error {
+		if server.GetState() == packet.Handshake {
+			pb.Consume()
+			server.SetState(packet.Play)
+			received <- pb
+			return nil
+		}
+		defer pb.Consume()
+		received <- pb
+		return backend.ForwardPacket(pb)
+	})
+
+	// The version is taken from the handshake on both sides.
+	require.NoError(t, client.SendPacket(&packet.ClientHandshake{ProtocolVersion: int32(packet.Version1_20_2), Intent: packet.IntentLogin}))
+	receive(t, received)
+	require.Equal(t, packet.Version1_20_2, client.GetVersion())
+	require.Equal(t, packet.Version1_20_2, server.GetVersion())
+	client.SetState(packet.Play)
+
+	// IDs are translated on both sides, 1.20.2 uses 0x14 for keep alive.
+	require.NoError(t, client.SendPacket(&packet.ClientKeepAlive{KeepAliveID: 1}))
+	pb := receive(t, received)
+	require.Equal(t, packet.ClientPlayKeepAliveID, pb.Id)
+	require.Equal(t, packet.Version1_20_2, pb.Version())
+	require.Equal(t, byte(0x14), pb.frame[1])
+
+	// Forwarding to a connection with another version translates the ID.
+	require.Eventually(t, func() bool { return len(forwarded.written()) > 0 }, time.Second, time.Millisecond)
+	require.Equal(t, []byte{0x09, 0x1A, 0, 0, 0, 0, 0, 0, 0, 1}, forwarded.written())
+
+	// Packets which do not exist in the version cannot be sent.
+	cookie := packet.NewRawPacket(packet.Play, packet.Serverbound, packet.ClientPlayCookieResponseID, nil)
+	require.ErrorContains(t, client.SendPacket(cookie), "is not available in version")
+}
+
+func TestConn_ForwardPacket_Translate(t *testing.T) {
+	forwarded := newGateConn()
+	close(forwarded.gate)
+	backend := NewConn(packet.Clientbound, forwarded, func(pb PacketBuffer) error { return nil })
+	backend.SetState(packet.Play)
+
+	forwardErr := make(chan error, 1)
+	client, server := newPipe(t, func(pb PacketBuffer) error {
+		forwardErr <- backend.ForwardPacket(pb)
+		return nil
+	})
+	for _, c := range []*Conn{client, server} {
+		c.SetState(packet.Play)
+		c.SetVersion(packet.OldestVersion)
+	}
+
+	// Item stacks are encoded differently, so the packet is decoded and encoded again.
+	slot := &packet.ClientPlaySetCreativeModeSlot{Slot: 36, Item: packet.ItemStack{Item: 1, Count: 1}}
+	require.NoError(t, client.SendPacket(slot))
+	require.NoError(t, receive(t, forwardErr))
+	expected, err := EncodePacket(packet.Play, packet.LatestVersion, slot)
+	require.NoError(t, err)
+	expected.internal.Reset(expected.mark)
+	require.Equal(t, expected.internal.Bytes(), forwarded.written()[1:])
+
+	// The encoding of unregistered packets is not known.
+	unknown := packet.NewRawPacket(packet.Play, packet.Serverbound, packet.ClientPlayChatCommandID, []byte{0x00})
+	require.NoError(t, client.SendPacket(unknown))
+	require.ErrorIs(t, receive(t, forwardErr), ErrUnknownPacket)
+}
+
+func TestConn_UnknownPacket(t *testing.T) {
+	unsupported := packet.LatestVersion + 1
+	same, other := newGateConn(), newGateConn()
+	close(same.gate)
+	close(other.gate)
+	sameBackend := NewConn(packet.Clientbound, same, func(pb PacketBuffer) error { return nil })
+	otherBackend := NewConn(packet.Clientbound, other, func(pb PacketBuffer) error { return nil })
+	for _, c := range []*Conn{sameBackend, otherBackend} {
+		c.SetState(packet.Play)
+	}
+	sameBackend.SetVersion(unsupported)
+
+	cc, sc := net.Pipe()
+	forwardErr := make(chan error, 1)
+	var server *Conn
+	server = NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error {
+		require.Equal(t, packet.InvalidState, pb.Id)
+		forwardErr <- otherBackend.ForwardPacket(pb)
+		return sameBackend.ForwardPacket(pb)
+	})
+	server.SetState(packet.Play)
+	server.SetVersion(unsupported)
+	t.Cleanup(func() {
+		_ = cc.Close()
+		server.Close()
+	})
+	go server.ReadLoop()
+	_, err := cc.Write([]byte{0x03, 0x7f, 0x01, 0x02})
+	require.NoError(t, err)
+
+	// Packets of an unsupported version are forwarded as is to a connection with the same version, and
+	// cannot be translated for another.
+	require.ErrorIs(t, receive(t, forwardErr), ErrUnknownPacket)
+	require.Eventually(t, func() bool { return len(same.written()) > 0 }, time.Second, time.Millisecond)
+	require.Equal(t, []byte{0x03, 0x7f, 0x01, 0x02}, same.written())
+	require.Empty(t, other.written())
+}
+
+// encodeFuzzFrames returns the frames for packets (each a wire ID and payload) with a compression threshold.
+func encodeFuzzFrames(threshold int, packets ...[]byte) []byte {
+	c := NewConn(packet.Serverbound, &memConn{}, func(pb PacketBuffer) error { return nil })
+	c.EnableCompression(threshold)
+	var frames bytebufferpool.ByteBuffer
+	for _, pkt := range packets {
+		_ = c.appendFrame(&frames, pkt)
+	}
+	return frames.B
+}
+
+// FuzzConn_Serve reads arbitrary data as frames from a connection, which must never panic. The same
+// packets must be received whether the data arrives at once or in chunks, and each is also decoded with
+// packet.DefaultRegistry.
+func FuzzConn_Serve(f *testing.F) {
+	handshake := []byte{0x00, 0x80, 0x06, 0x09, 'l', 'o', 'c', 'a', 'l', 'h', 'o', 's', 't', 0x63, 0xDD, 0x02}
+	keepAlive := []byte{0x1A, 0, 0, 0, 0, 0, 0, 0, 1}
+	large := append([]byte{0x14}, bytes.Repeat([]byte("kite"), 64)...)
+
+	f.Add(uint8(packet.Handshake), int16(-1), uint16(1), encodeFuzzFrames(-1, handshake))
+	f.Add(uint8(packet.Play), int16(-1), uint16(3), encodeFrames(3, 10))
+	f.Add(uint8(packet.Play), int16(64), uint16(7), encodeFuzzFrames(64, keepAlive, large, keepAlive))
+	f.Add(uint8(packet.Config), int16(0), uint16(100), encodeFuzzFrames(0, large))
+	f.Add(uint8(packet.Login), int16(-1), uint16(2), []byte{0xFF, 0xFF, 0x7F})
+	f.Add(uint8(packet.Handshake), int16(-1), uint16(5), []byte{0xFE, 0x01, 0xFA})
+	f.Fuzz(func(t *testing.T, state uint8, threshold int16, chunk uint16, data []byte) {
+		type received struct {
+			id      int
+			payload []byte
+		}
+		st := packet.State(state % uint8(packet.Play+1))
+		serve := func(chunk int) ([]received, error) {
+			var packets []received
+			c := NewConn(packet.Serverbound, &memConn{r: &chunkReader{data: data, chunk: chunk}}, func(pb PacketBuffer) error {
+				packets = append(packets, received{pb.Id, bytes.Clone(pb.payload())})
+				_, _ = pb.Decode(packet.DefaultRegistry, st, packet.Serverbound)
+				pb.Consume()
+				return nil
+			})
+			c.SetState(st)
+			c.EnableCompression(int(threshold))
+			return packets, c.Serve(context.Background())
+		}
+
+		packets, err := serve(len(data) + 1)
+		chunked, chunkedErr := serve(int(chunk%1024) + 1)
+		require.Equal(t, packets, chunked)
+		require.Equal(t, err == nil, chunkedErr == nil, "%v, %v", err, chunkedErr)
+	})
+}
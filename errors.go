@@ -0,0 +1,46 @@
+package kite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mworzala/kite/pkg/packet"
+)
+
+var (
+	// ErrPacketTooLarge is returned when a frame exceeds the size allowed in the current state.
+	ErrPacketTooLarge = errors.New("packet too large")
+	// ErrMalformedFrame is returned when a frame cannot be decoded, for example an invalid length
+	// prefix or corrupt compressed data.
+	ErrMalformedFrame = errors.New("malformed frame")
+	// ErrUnconsumedPacket is returned when a handler did not fully read or consume a packet.
+	ErrUnconsumedPacket = errors.New("packet not fully read")
+	// ErrUnknownPacket is returned when a packet is forwarded to a connection with a different protocol
+	// version, and it is unknown to its version or not registered in packet.DefaultRegistry, so that its
+	// encoding cannot be translated.
+	ErrUnknownPacket = errors.New("unknown packet id")
+	// ErrLegacyPing is returned when a pre-1.7 client sends a legacy server list ping and no
+	// LegacyPingHandler is set.
+	ErrLegacyPing = errors.New("legacy server list ping")
+	// ErrTimeout is returned when the remote does not send anything within the configured timeouts.
+	ErrTimeout = errors.New("timed out")
+	// ErrWriteQueueFull is returned when a packet is written while the write queue is full and its
+	// policy is WriteQueueDisconnect.
+	ErrWriteQueueFull = errors.New("write queue full")
+)
+
+// A PacketError describes a failure while handling a single packet.
+type PacketError struct {
+	Direction packet.Direction
+	State     packet.State
+	ID        int
+	Err       error
+}
+
+func (e *PacketError) Error() string {
+	return fmt.Sprintf("packet processing failed: %s (%s/%s/%d)", e.Err, e.Direction.String(), e.State.String(), e.ID)
+}
+
+func (e *PacketError) Unwrap() error {
+	return e.Err
+}
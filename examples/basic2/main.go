@@ -25,6 +25,8 @@ func main() {
 	proxy := &Proxy{
 		MojKeyPair:     keyPair,
 		VelocitySecret: "abcdef",
+
+		CompressionThreshold: 256,
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
@@ -34,7 +36,7 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	go clientListenLoop(proxy, listener)
+	go clientListenLoop(ctx, proxy, listener)
 
 	<-ctx.Done()
 
@@ -45,7 +47,7 @@ func main() {
 	println("Goodbye, World!")
 }
 
-func clientListenLoop(proxy *Proxy, listener net.Listener) {
+func clientListenLoop(ctx context.Context, proxy *Proxy, listener net.Listener) {
 	for {
 		cc, err := listener.Accept()
 		if errors.Is(err, net.ErrClosed) {
@@ -57,6 +59,11 @@ func clientListenLoop(proxy *Proxy, listener net.Listener) {
 
 		p := Player{proxy: proxy}
 		p.conn = kite.NewConn(packet.Serverbound, cc, p.handleClientPacket)
-		go p.conn.ReadLoop()
+		p.conn.SetProxyProtocol(proxy.ProxyProtocol)
+		p.conn.SetLegacyPingHandler(p.handleLegacyPing)
+		p.conn.SetTimeouts(kite.DefaultTimeouts)
+		p.conn.EnableWriteQueue(kite.DefaultWriteQueueConfig) // A slow client must not stall the backend
+		p.conn.AddInboundInterceptor(kite.FilterPackets(packet.Play, p.interceptClientPlayChat, packet.ClientPlayChatID))
+		go p.serve(ctx)
 	}
 }
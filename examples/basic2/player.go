@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"log"
 
 	"github.com/mworzala/kite/pkg/mojang"
 	"github.com/mworzala/kite/pkg/text"
@@ -23,16 +25,23 @@ type Player struct {
 	remote           *kite.Conn
 }
 
-func (p *Player) Disconnect(reason string) {
-	//todo take chat component message
-	println("disconnect")
+// serve handles client packets until the connection is closed, then closes the backend connection.
+func (p *Player) serve(ctx context.Context) {
+	err := p.conn.Serve(ctx)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Printf("client %s disconnected: %s", p.conn.RemoteAddr(), err)
+	}
 	p.conn.Close()
+	p.remote.Close()
+}
+
+func (p *Player) Disconnect(reason string) {
+	p.Disconnect2(&text.Text{Text: reason})
 }
 
 func (p *Player) Disconnect2(message text.Component) {
 	println("disconnect", p, "for", text.MarshalPlain(message))
-	_ = p.conn.SendPacket(&packet.ServerDisconnect{Reason: message})
-	p.conn.Close()
+	_ = p.conn.Disconnect(message)
 }
 
 func (p *Player) handleClientPacket(pp kite.PacketBuffer) error {
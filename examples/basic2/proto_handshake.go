@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/mworzala/kite"
 	packet2 "github.com/mworzala/kite/pkg/packet"
@@ -25,6 +26,10 @@ func (p *Player) handleHandshake(pkt *packet2.ClientHandshake) error {
 		p.conn.SetState(packet2.Status)
 	case packet2.IntentLogin:
 		p.conn.SetState(packet2.Login)
+		if !p.conn.GetVersion().Supported() {
+			p.Disconnect(fmt.Sprintf("Unsupported version, please use %s to %s",
+				packet2.OldestVersion, packet2.LatestVersion))
+		}
 	case packet2.IntentTransfer:
 		p.Disconnect("Transfer not supported")
 	default:
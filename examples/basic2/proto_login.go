@@ -4,11 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/mworzala/kite"
 	"github.com/mworzala/kite/pkg/mojangutil"
 	"github.com/mworzala/kite/pkg/packet"
+	"github.com/mworzala/kite/pkg/proxyproto"
 	"github.com/mworzala/kite/pkg/velocity"
 )
 
@@ -56,6 +58,13 @@ func (p *Player) handleClientEncryptionResponse(pkt *packet.ClientEncryptionResp
 	p.Username = profile.Name
 	p.Profile = &profile
 
+	if threshold := p.proxy.CompressionThreshold; threshold >= 0 {
+		if err = p.conn.SendPacket(&packet.ServerLoginSetCompression{Threshold: int32(threshold)}); err != nil {
+			return err
+		}
+		p.conn.EnableCompression(threshold)
+	}
+
 	return p.conn.SendPacket(&packet.ServerLoginSuccess{
 		GameProfile: profile,
 	})
@@ -89,7 +98,7 @@ func (p *Player) connectServerSync(address string, port uint16) (*kite.Conn, err
 		p.pendingLoginChan = nil
 	}()
 
-	serverConn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", address, port))
+	serverConn, err := net.Dial("tcp", net.JoinHostPort(address, strconv.Itoa(int(port))))
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial remote: %w", err)
 	}
@@ -98,9 +107,16 @@ func (p *Player) connectServerSync(address string, port uint16) (*kite.Conn, err
 	p.remote = remote // TODO: this whole function is bad
 	go remote.ReadLoop()
 
+	if p.proxy.BackendProxyProtocol != 0 {
+		header := proxyproto.NewHeader(p.proxy.BackendProxyProtocol, p.conn.RemoteAddr(), serverConn.RemoteAddr())
+		if err = remote.SendProxyHeader(header); err != nil {
+			return nil, err
+		}
+	}
+
 	// Handshake immediately, then we are in login.
 	handshake := &packet.ClientHandshake{
-		ProtocolVersion: 768,
+		ProtocolVersion: int32(p.conn.GetVersion()),
 		ServerAddress:   address,
 		ServerPort:      port,
 		Intent:          packet.IntentLogin,
@@ -153,6 +169,13 @@ func (p *Player) handleServerLoginPacket(pb kite.PacketBuffer) (err error) {
 			return err
 		}
 		return p.handleServerLoginPluginRequest(pkt)
+	case packet.ServerLoginSetCompressionID:
+		pkt := new(packet.ServerLoginSetCompression)
+		if err = pb.Read(pkt); err != nil {
+			return err
+		}
+		p.remote.EnableCompression(int(pkt.Threshold))
+		return nil
 	case packet.ServerLoginLoginSuccessID:
 		pkt := new(packet.ServerLoginSuccess)
 		if err = pb.Read(pkt); err != nil {
@@ -182,7 +205,12 @@ func (p *Player) handleServerLoginPluginRequest(pkt *packet.ServerLoginPluginReq
 	if len(pkt.Data) > 0 {
 		requestVersion = int(pkt.Data[0])
 	}
-	forward, err := velocity.CreateSignedForwardingData(requestVersion, []byte(p.proxy.VelocitySecret), "127.0.0.1", p.Profile)
+	// RemoteAddr is the real client address when the listener expects a PROXY protocol header.
+	clientIP := p.conn.RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		clientIP = host
+	}
+	forward, err := velocity.CreateSignedForwardingData(requestVersion, []byte(p.proxy.VelocitySecret), clientIP, p.Profile)
 	if err != nil {
 		return err
 	}
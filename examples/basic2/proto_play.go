@@ -10,20 +10,19 @@ func (p *Player) handleClientPlayPacket(pb kite.PacketBuffer) (err error) {
 	if p.remote == nil {
 		panic("bad state")
 	}
-	switch pb.Id {
-	case packet.ClientPlayChatID:
-		pkt := new(packet.ClientPlayChat)
-		if err = pb.Read(pkt); err != nil {
-			return
-		}
-		pb.Consume() // Chat packet is only partially implemented
-		if err = p.handleClientPlayChat(pkt); err != nil {
-			return err
-		}
-		return p.remote.ForwardPacket(pb)
-	default:
-		return p.remote.ForwardPacket(pb)
+	return p.remote.ForwardPacket(pb)
+}
+
+// interceptClientPlayChat observes chat messages before they are forwarded to the server.
+func (p *Player) interceptClientPlayChat(_ *kite.Conn, pb kite.PacketBuffer, next kite.PacketHandler) error {
+	pkt := new(packet.ClientPlayChat)
+	if err := pb.Peek(pkt); err != nil {
+		return err
+	}
+	if err := p.handleClientPlayChat(pkt); err != nil {
+		return err
 	}
+	return next(pb)
 }
 
 func (p *Player) handleClientPlayChat(pkt *packet.ClientPlayChat) (err error) {
@@ -31,10 +31,15 @@ func (p *Player) handlePingRequest(pkt *packet.ClientStatusPingRequest) error {
 }
 
 func (p *Player) handleStatusRequest(_ *packet.ClientStatusRequest) error {
+	// Supported clients are shown as compatible, others are shown the latest version.
+	version := p.conn.GetVersion()
+	if !version.Supported() {
+		version = packet.LatestVersion
+	}
 	return p.conn.SendPacket(&packet.ServerStatusResponse{Payload: packet.StatusResponse{
 		Version: packet.ServerVersion{
-			Name:     "1.21.3",
-			Protocol: 768,
+			Name:     "1.20.2-1.21.3",
+			Protocol: int(version),
 		},
 		Players: packet.ServerPlayerList{
 			Max:    1000,
@@ -45,3 +50,13 @@ func (p *Player) handleStatusRequest(_ *packet.ClientStatusRequest) error {
 		EnforceSecureChat: true,
 	}})
 }
+
+func (p *Player) handleLegacyPing(_ *kite.Conn, _ *kite.LegacyPing) (*kite.LegacyPingResponse, error) {
+	return &kite.LegacyPingResponse{
+		Protocol: int(packet.LatestVersion),
+		Version:  "1.21.3",
+		MOTD:     "Hello, Kite",
+		Online:   0,
+		Max:      1000,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/mworzala/kite"
 	"github.com/mworzala/kite/pkg/mojang"
 )
 
@@ -8,4 +9,12 @@ type Proxy struct {
 	MojKeyPair mojang.KeyPair
 
 	VelocitySecret string
+
+	// CompressionThreshold is the threshold used toward clients, or negative to disable compression.
+	CompressionThreshold int
+
+	// ProxyProtocol controls whether clients connect through a load balancer sending a PROXY protocol header.
+	ProxyProtocol kite.ProxyProtocolMode
+	// BackendProxyProtocol is the PROXY protocol version sent to backend servers, or 0 to send none.
+	BackendProxyProtocol int
 }
@@ -0,0 +1,73 @@
+package kite
+
+import (
+	"slices"
+
+	"github.com/mworzala/kite/pkg/packet"
+)
+
+// A PacketHandler handles a single packet. See PacketBuffer for the consumption rules.
+type PacketHandler func(pb PacketBuffer) error
+
+// An Interceptor observes packets passing through a Conn. It is called with the connection and the packet,
+// and passes the packet on by calling next. An interceptor may:
+//   - observe the packet, using PacketBuffer.Peek to decode it without consuming it
+//   - modify the packet, by passing a different buffer to next (see EncodePacket and NewPacketBuffer)
+//   - drop the packet, by not calling next
+//   - inject packets, by calling next more than once
+//
+// The packet received must be handled like in any other handler: if it is not passed to next it must be
+// consumed, either by reading it or by calling PacketBuffer.Consume. Packet buffers are only valid for
+// the duration of the call.
+type Interceptor func(c *Conn, pb PacketBuffer, next PacketHandler) error
+
+// AddInboundInterceptor adds an interceptor for packets read from the connection, which runs before the
+// handler given to NewConn. Interceptors run in the order they are added.
+//
+// Interceptors must be added before the connection is served.
+func (c *Conn) AddInboundInterceptor(interceptor Interceptor) {
+	c.inboundInterceptors = append(c.inboundInterceptors, interceptor)
+	c.inbound = c.chain(c.inboundInterceptors, c.handler)
+}
+
+// AddOutboundInterceptor adds an interceptor for packets written using SendPacket or ForwardPacket.
+// Interceptors run in the order they are added.
+//
+// Interceptors must be added before any packets are written.
+func (c *Conn) AddOutboundInterceptor(interceptor Interceptor) {
+	c.outboundInterceptors = append(c.outboundInterceptors, interceptor)
+	c.outbound = c.chain(c.outboundInterceptors, c.writePacketBuffer)
+}
+
+// chain returns a handler calling each interceptor in order, then the given handler.
+func (c *Conn) chain(interceptors []Interceptor, handler PacketHandler) PacketHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(pb PacketBuffer) error {
+			return interceptor(c, pb, next)
+		}
+	}
+	return handler
+}
+
+// FilterPackets returns an interceptor which only calls interceptor for packets with one of the given IDs
+// in the given state. All other packets are passed on unchanged.
+func FilterPackets(state packet.State, interceptor Interceptor, ids ...int) Interceptor {
+	return func(c *Conn, pb PacketBuffer, next PacketHandler) error {
+		if c.GetState() != state || !slices.Contains(ids, pb.Id) {
+			return next(pb)
+		}
+		return interceptor(c, pb, next)
+	}
+}
+
+// FilterState returns an interceptor which only calls interceptor for packets in the given state.
+// All other packets are passed on unchanged.
+func FilterState(state packet.State, interceptor Interceptor) Interceptor {
+	return func(c *Conn, pb PacketBuffer, next PacketHandler) error {
+		if c.GetState() != state {
+			return next(pb)
+		}
+		return interceptor(c, pb, next)
+	}
+}
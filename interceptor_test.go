@@ -0,0 +1,140 @@
+package kite
+
+import (
+	"net"
+	"testing"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/stretchr/testify/require"
+)
+
+// newInterceptedPipe is like newPipe, but calls setup before either connection is served.
+func newInterceptedPipe(t *testing.T, handler PacketHandler, setup func(client, server *Conn)) (client, server *Conn) {
+	t.Helper()
+	cc, sc := net.Pipe()
+	client = NewConn(packet.Clientbound, cc, func(pb PacketBuffer) error {
+		pb.Consume()
+		return nil
+	})
+	server = NewConn(packet.Serverbound, sc, handler)
+	for _, c := range []*Conn{client, server} {
+		c.SetState(packet.Config)
+	}
+	setup(client, server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	go client.ReadLoop()
+	go server.ReadLoop()
+	return
+}
+
+// replaceChannel returns an interceptor replacing plugin messages with a copy on a different channel.
+func replaceChannel(channel string) Interceptor {
+	return func(c *Conn, pb PacketBuffer, next PacketHandler) error {
+		pkt := new(packet.ClientPluginMessage)
+		if err := pb.Read(pkt); err != nil {
+			return err
+		}
+		pkt.Channel = channel
+		replacement, err := EncodePacket(c.GetState(), c.GetVersion(), pkt)
+		if err != nil {
+			return err
+		}
+		return next(replacement)
+	}
+}
+
+func TestConn_InboundInterceptors(t *testing.T) {
+	received := make(chan *packet.ClientPluginMessage, 4)
+	var order []string
+	client, _ := newInterceptedPipe(t, collectPluginMessages(received), func(_, server *Conn) {
+		server.AddInboundInterceptor(func(c *Conn, pb PacketBuffer, next PacketHandler) error {
+			order = append(order, "first")
+			return next(pb)
+		})
+		server.AddInboundInterceptor(func(c *Conn, pb PacketBuffer, next PacketHandler) error {
+			order = append(order, "second")
+			pkt := new(packet.ClientPluginMessage)
+			require.NoError(t, pb.Peek(pkt))
+			switch pkt.Channel {
+			case "kite:drop":
+				pb.Consume()
+				return nil
+			case "kite:modify":
+				return replaceChannel("kite:modified")(c, pb, next)
+			case "kite:inject":
+				if err := next(pb); err != nil {
+					return err
+				}
+				return next(NewPacketBuffer(pb.Id, []byte{0x0d, 'k', 'i', 't', 'e', ':', 'i', 'n', 'j', 'e', 'c', 't', 'e', 'd'}))
+			}
+			return next(pb)
+		})
+	})
+
+	for _, channel := range []string{"kite:drop", "kite:modify", "kite:inject", "kite:plain"} {
+		require.NoError(t, client.SendPacket(&packet.ClientPluginMessage{Channel: channel, Data: []byte{1}}))
+	}
+	require.Equal(t, &packet.ClientPluginMessage{Channel: "kite:modified", Data: []byte{1}}, receive(t, received))
+	require.Equal(t, &packet.ClientPluginMessage{Channel: "kite:inject", Data: []byte{1}}, receive(t, received))
+	require.Equal(t, &packet.ClientPluginMessage{Channel: "kite:injected"}, receive(t, received))
+	require.Equal(t, &packet.ClientPluginMessage{Channel: "kite:plain", Data: []byte{1}}, receive(t, received))
+	require.Equal(t, []string{"first", "second", "first", "second", "first", "second", "first", "second"}, order)
+}
+
+func TestConn_OutboundInterceptors(t *testing.T) {
+	received := make(chan *packet.ClientPluginMessage, 4)
+	client, _ := newInterceptedPipe(t, collectPluginMessages(received), func(client, _ *Conn) {
+		client.AddOutboundInterceptor(FilterPackets(packet.Config, replaceChannel("kite:modified"), packet.ClientConfigPluginMessageID))
+		client.AddOutboundInterceptor(func(c *Conn, pb PacketBuffer, next PacketHandler) error {
+			pkt := new(packet.ClientPluginMessage)
+			require.NoError(t, pb.Peek(pkt))
+			if string(pkt.Data) == "drop" {
+				pb.Consume()
+				return nil
+			}
+			return next(pb)
+		})
+	})
+
+	require.NoError(t, client.SendPacket(&packet.ClientPluginMessage{Channel: "kite:test", Data: []byte("drop")}))
+	require.NoError(t, client.SendPacket(&packet.ClientPluginMessage{Channel: "kite:test", Data: []byte("keep")}))
+	require.Equal(t, &packet.ClientPluginMessage{Channel: "kite:modified", Data: []byte("keep")}, receive(t, received))
+}
+
+func TestConn_ForwardPacket_Interceptors(t *testing.T) {
+	received := make(chan *packet.ClientPluginMessage, 4)
+	backend, _ := newInterceptedPipe(t, collectPluginMessages(received), func(backend, _ *Conn) {
+		backend.AddOutboundInterceptor(func(c *Conn, pb PacketBuffer, next PacketHandler) error {
+			pkt := new(packet.ClientPluginMessage)
+			require.NoError(t, pb.Peek(pkt))
+			if pkt.Channel == "kite:drop" {
+				return nil // Forwarded packets are consumed by ForwardPacket
+			}
+			return next(pb)
+		})
+	})
+	client, _ := newInterceptedPipe(t, backend.ForwardPacket, func(_, _ *Conn) {})
+
+	require.NoError(t, client.SendPacket(&packet.ClientPluginMessage{Channel: "kite:drop", Data: []byte{1}}))
+	require.NoError(t, client.SendPacket(&packet.ClientPluginMessage{Channel: "kite:keep", Data: []byte{2}}))
+	require.Equal(t, &packet.ClientPluginMessage{Channel: "kite:keep", Data: []byte{2}}, receive(t, received))
+}
+
+func TestPacketBuffer_Peek(t *testing.T) {
+	pkt := &packet.ClientPluginMessage{Channel: "kite:test", Data: []byte{1, 2, 3}}
+	pb, err := EncodePacket(packet.Config, packet.LatestVersion, pkt)
+	require.NoError(t, err)
+	require.Equal(t, packet.ClientConfigPluginMessageID, pb.Id)
+
+	peeked := new(packet.ClientPluginMessage)
+	require.NoError(t, pb.Peek(peeked))
+	require.Equal(t, pkt, peeked)
+
+	read := new(packet.ClientPluginMessage)
+	require.NoError(t, pb.Read(read))
+	require.Equal(t, pkt, read)
+	require.Zero(t, pb.internal.Remaining())
+}
@@ -0,0 +1,553 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var states = []string{"Handshake", "Status", "Login", "Config", "Play"}
+
+// baseTypes are the buffer Types for each encoding, with the Go type they read and write.
+var baseTypes = map[string]struct{ expr, goType string }{
+	"bool":       {"buffer.Bool", "bool"},
+	"byte":       {"buffer.Byte", "byte"},
+	"short":      {"buffer.Short", "int16"},
+	"uint16":     {"buffer.Uint16", "uint16"},
+	"int":        {"buffer.Int", "int32"},
+	"varint":     {"buffer.VarInt", "int32"},
+	"long":       {"buffer.Long", "int64"},
+	"varlong":    {"buffer.VarLong", "int64"},
+	"float":      {"buffer.Float", "float32"},
+	"double":     {"buffer.Double", "float64"},
+	"string":     {"buffer.String", "string"},
+	"identifier": {"buffer.Identifier", "string"},
+	"bytearray":  {"buffer.ByteArray", "[]byte"},
+	"rawbytes":   {"buffer.RawBytes", "[]byte"},
+	"nbt":        {"buffer.RawNBT", "[]byte"},
+	"uuid":       {"buffer.UUID", "uuid.UUID"},
+	"position":   {"buffer.Position", "buffer.BlockPos"},
+	"text":       {"buffer.TextComponent", "text.Component"},
+	"textjson":   {"buffer.TextComponentJSON", "text.Component"},
+}
+
+// defaultEncodings are the encodings of untagged fields with builtin or well known types.
+var defaultEncodings = map[string]string{
+	"bool":            "bool",
+	"byte":            "byte",
+	"uint8":           "byte",
+	"int8":            "byte",
+	"int16":           "short",
+	"uint16":          "uint16",
+	"int32":           "varint",
+	"int64":           "long",
+	"float32":         "float",
+	"float64":         "double",
+	"string":          "string",
+	"uuid.UUID":       "uuid",
+	"buffer.BlockPos": "position",
+	"text.Component":  "text",
+	"nbt.Tag":         "nbt",
+}
+
+// A codecType is an annotated struct to generate methods for.
+type codecType struct {
+	name   string
+	fields *ast.FieldList
+	packet bool
+	states []string // State and ID constant pairs
+}
+
+type generator struct {
+	pkg     string
+	types   map[string]ast.Expr // Declared types and their underlying type expression
+	enums   map[string]bool     // Declared types with a Validate method
+	methods map[string]bool     // Declared methods, as Type.Method
+	packets []string            // Declared packet types, with a kite:packet directive or a Direction method
+	imports map[string]string   // Package name to import path, from the parsed files
+	used    map[string]bool     // Package names used by the generated code
+
+	b            bytes.Buffer
+	typ, field   string // Names of the type and field being generated, for errors
+	errorWrapped bool
+}
+
+// generate parses the Go files in fsys (except out and tests) and returns the generated source.
+func generate(fsys fs.FS, out string) ([]byte, error) {
+	g := &generator{
+		types:   map[string]ast.Expr{},
+		enums:   map[string]bool{},
+		methods: map[string]bool{},
+		imports: map[string]string{},
+		used:    map[string]bool{},
+	}
+	codecTypes, err := g.parse(fsys, out)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ct := range codecTypes {
+		if err := g.generateType(ct); err != nil {
+			return nil, fmt.Errorf("%s: %w", ct.name, err)
+		}
+	}
+	for _, name := range g.packets {
+		if !g.methods[name+".String"] {
+			fmt.Fprintf(&g.b, "func (p *%s) String() string { return FormatPacket(p) }\n", name)
+		}
+		if !g.methods[name+".LogValue"] {
+			fmt.Fprintf(&g.b, "func (p *%s) LogValue() slog.Value { return PacketLogValue(p) }\n", name)
+		}
+	}
+	var packets []string
+	for _, ct := range codecTypes {
+		if ct.packet {
+			packets = append(packets, fmt.Sprintf("_ Packet = (*%s)(nil)\n", ct.name))
+		}
+	}
+	if len(packets) > 0 {
+		g.b.WriteString("\nvar (\n" + strings.Join(packets, "") + ")\n")
+	}
+
+	var header bytes.Buffer
+	header.WriteString("// Code generated by codecgen; DO NOT EDIT.\n\npackage " + g.pkg + "\n\nimport (\n")
+	std := []string{strconv.Quote("io")}
+	if g.errorWrapped {
+		std = append(std, strconv.Quote("fmt"))
+	}
+	if len(g.packets) > 0 {
+		std = append(std, strconv.Quote("log/slog"))
+	}
+	var other []string
+	for name := range g.used {
+		importPath, ok := g.imports[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown package %s", name)
+		}
+		other = append(other, strconv.Quote(importPath))
+	}
+	slices.Sort(std)
+	slices.Sort(other)
+	header.WriteString(strings.Join(std, "\n") + "\n\n" + strings.Join(other, "\n") + "\n)\n\n")
+	header.Write(g.b.Bytes())
+
+	src, err := format.Source(header.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, header.Bytes())
+	}
+	return src, nil
+}
+
+// parse reads the declared types of the package and returns the annotated ones, in source order.
+func (g *generator) parse(fsys fs.FS, out string) ([]*codecType, error) {
+	names, err := fs.Glob(fsys, "*.go")
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var codecTypes []*codecType
+	for _, name := range names {
+		if name == out || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		g.pkg = file.Name.Name
+
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			if spec.Name != nil {
+				g.imports[spec.Name.Name] = importPath
+			} else {
+				g.imports[path.Base(importPath)] = importPath
+			}
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					continue
+				}
+				recv := receiverName(decl.Recv.List[0].Type)
+				g.methods[recv+"."+decl.Name.Name] = true
+				switch decl.Name.Name {
+				case "Validate":
+					g.enums[recv] = true
+				case "Direction":
+					g.packets = append(g.packets, recv)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					g.types[ts.Name.Name] = ts.Type
+					doc := ts.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					ct, err := parseDirective(ts, doc)
+					if err != nil {
+						return nil, fmt.Errorf("%s: %w", fset.Position(ts.Pos()), err)
+					}
+					if ct != nil {
+						codecTypes = append(codecTypes, ct)
+					}
+					if ct != nil && ct.packet {
+						g.packets = append(g.packets, ct.name)
+					}
+				}
+			}
+		}
+	}
+	return codecTypes, nil
+}
+
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// parseDirective returns the codec type for a type with a kite directive, or nil if it has none.
+func parseDirective(ts *ast.TypeSpec, doc *ast.CommentGroup) (*codecType, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	for _, comment := range doc.List {
+		words := strings.Fields(comment.Text)
+		if len(words) == 0 || !strings.HasPrefix(words[0], "//kite:") {
+			continue
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a struct", ts.Name.Name)
+		}
+		ct := &codecType{name: ts.Name.Name, fields: st.Fields}
+		switch words[0] {
+		case "//kite:codec":
+			if len(words) != 1 {
+				return nil, fmt.Errorf("unexpected arguments to kite:codec")
+			}
+		case "//kite:packet":
+			ct.packet = true
+			if !strings.HasPrefix(ct.name, "Client") && !strings.HasPrefix(ct.name, "Server") {
+				return nil, fmt.Errorf("packet %s must start with Client or Server", ct.name)
+			}
+			if len(words) < 2 || len(words) > 4 {
+				return nil, fmt.Errorf("kite:packet requires 1 to 3 State=ID arguments")
+			}
+			for _, arg := range words[1:] {
+				state, id, ok := strings.Cut(arg, "=")
+				if !ok || !slices.Contains(states, state) || !token.IsIdentifier(id) {
+					return nil, fmt.Errorf("invalid kite:packet argument %q, expected State=ID", arg)
+				}
+				ct.states = append(ct.states, state, id)
+			}
+		default:
+			return nil, fmt.Errorf("unknown directive %s", words[0])
+		}
+		return ct, nil
+	}
+	return nil, nil
+}
+
+func (g *generator) generateType(ct *codecType) error {
+	g.typ = ct.name
+	if ct.packet {
+		direction := "Serverbound"
+		if strings.HasPrefix(ct.name, "Server") {
+			direction = "Clientbound"
+		}
+		fmt.Fprintf(&g.b, "func (p *%s) Direction() Direction { return %s }\n", ct.name, direction)
+		fmt.Fprintf(&g.b, "func (p *%s) ID(state State) int {\n", ct.name)
+		n := len(ct.states) / 2
+		args := []string{"state"}
+		for i := 0; i < n; i++ {
+			args = append(args, ct.states[2*i])
+		}
+		for i := 0; i < n; i++ {
+			args = append(args, ct.states[2*i+1])
+		}
+		fmt.Fprintf(&g.b, "return stateId%d(%s)\n}\n", n, strings.Join(args, ", "))
+	}
+
+	type field struct {
+		name string
+		typ  ast.Expr
+		spec []string
+	}
+	var fields []field
+	for _, f := range ct.fields.List {
+		if len(f.Names) == 0 {
+			return fmt.Errorf("embedded field %s is not supported", types.ExprString(f.Type))
+		}
+		var spec []string
+		if f.Tag != nil {
+			tag, _ := strconv.Unquote(f.Tag.Value)
+			if value, ok := reflect.StructTag(tag).Lookup("mc"); ok {
+				if value == "-" {
+					continue
+				}
+				spec = strings.Split(value, ",")
+			}
+		}
+		for _, name := range f.Names {
+			if name.IsExported() {
+				fields = append(fields, field{name.Name, f.Type, spec})
+			}
+		}
+	}
+
+	fmt.Fprintf(&g.b, "func (p *%s) Read(r io.Reader) (err error) {\n", ct.name)
+	for _, f := range fields {
+		g.field = f.name
+		if err := g.read("p."+f.name, f.typ, f.spec, 0); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	g.b.WriteString("return nil\n}\n")
+
+	fmt.Fprintf(&g.b, "func (p *%s) Write(w io.Writer) (err error) {\n", ct.name)
+	for _, f := range fields {
+		g.field = f.name
+		if err := g.write("p."+f.name, f.typ, f.spec, 0); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	g.b.WriteString("return nil\n}\n\n")
+	return nil
+}
+
+// resolve returns the encoding of a value of type t with the given tag spec, filling in the default if
+// spec is empty. The first element is the modifier or base encoding.
+func (g *generator) resolve(t ast.Expr, spec []string) ([]string, error) {
+	if len(spec) > 0 {
+		if _, ok := baseTypes[spec[0]]; !ok && !slices.Contains([]string{"opt", "list", "enum", "struct"}, spec[0]) {
+			return nil, fmt.Errorf("unknown encoding %q", spec[0])
+		}
+		if spec[0] != "opt" && spec[0] != "list" && len(spec) > 1 {
+			return nil, fmt.Errorf("unexpected %q after %q", strings.Join(spec[1:], ","), spec[0])
+		}
+		return spec, nil
+	}
+	switch t := t.(type) {
+	case *ast.StarExpr:
+		return []string{"opt"}, nil
+	case *ast.ArrayType:
+		if t.Len == nil {
+			if elem, ok := t.Elt.(*ast.Ident); ok && (elem.Name == "byte" || elem.Name == "uint8") {
+				return []string{"bytearray"}, nil
+			}
+			return []string{"list"}, nil
+		}
+	case *ast.Ident:
+		if underlying, ok := g.types[t.Name]; ok {
+			if _, isStruct := underlying.(*ast.StructType); isStruct {
+				return []string{"struct"}, nil
+			}
+			if g.enums[t.Name] {
+				return []string{"enum"}, nil
+			}
+			return g.resolve(underlying, nil)
+		}
+	}
+	if encoding, ok := defaultEncodings[types.ExprString(t)]; ok {
+		return []string{encoding}, nil
+	}
+	return nil, fmt.Errorf("no default encoding for %s, add an mc tag", types.ExprString(t))
+}
+
+// typeString returns the source of a type expression, recording the packages it uses.
+func (g *generator) typeString(t ast.Expr) string {
+	ast.Inspect(t, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok {
+				g.used[pkg.Name] = true
+			}
+		}
+		return true
+	})
+	return types.ExprString(t)
+}
+
+// useBase returns the buffer Type for an encoding of a value of type typ, and the Go type it reads and
+// writes (which the value is converted to and from if it differs from typ).
+func (g *generator) useBase(encoding, typ string) (expr, goType string) {
+	base := baseTypes[encoding]
+	if encoding == "nbt" && typ == "nbt.Tag" {
+		base.expr, base.goType = "buffer.NBT", typ
+	}
+	g.used["buffer"] = true
+	if pkg, _, ok := strings.Cut(base.goType, "."); ok && base.goType != typ {
+		g.used[pkg] = true
+	}
+	return base.expr, base.goType
+}
+
+// check writes a statement returning the wrapped error if err is not nil.
+func (g *generator) check(stmt string) {
+	g.errorWrapped = true
+	fmt.Fprintf(&g.b, "if %s; err != nil {\nreturn fmt.Errorf(\"%s.%s: %%w\", err)\n}\n", stmt, g.typ, g.field)
+}
+
+// deref returns an expression for the value pointed to by v.
+func deref(v string) string {
+	return "(*" + v + ")"
+}
+
+func (g *generator) read(v string, t ast.Expr, spec []string, depth int) error {
+	spec, err := g.resolve(t, spec)
+	if err != nil {
+		return err
+	}
+	switch spec[0] {
+	case "opt":
+		present := fmt.Sprintf("present%d", depth)
+		fmt.Fprintf(&g.b, "{\nvar %s bool\n", present)
+		g.useBase("bool", "bool")
+		g.check(fmt.Sprintf("%s, err = buffer.Bool.Read(r)", present))
+		fmt.Fprintf(&g.b, "if %s {\n", present)
+		if star, ok := t.(*ast.StarExpr); ok {
+			fmt.Fprintf(&g.b, "%s = new(%s)\n", v, g.typeString(star.X))
+			err = g.read(deref(v), star.X, spec[1:], depth+1)
+		} else {
+			err = g.read(v, t, spec[1:], depth+1)
+		}
+		g.b.WriteString("}\n}\n")
+		return err
+
+	case "list":
+		array, ok := t.(*ast.ArrayType)
+		if !ok || array.Len != nil {
+			return fmt.Errorf("list encoding requires a slice, not %s", types.ExprString(t))
+		}
+		n, elem := fmt.Sprintf("n%d", depth), fmt.Sprintf("e%d", depth)
+		fmt.Fprintf(&g.b, "{\nvar %s int\n", n)
+		g.used["buffer"] = true
+		g.check(fmt.Sprintf("%s, err = buffer.ReadListLength(r, buffer.MaxLength)", n))
+		fmt.Fprintf(&g.b, "%s = make(%s, 0, buffer.Prealloc(r, %s))\nfor range %s {\n", v, g.typeString(t), n, n)
+		fmt.Fprintf(&g.b, "var %s %s\n", elem, g.typeString(array.Elt))
+		if err = g.read(elem, array.Elt, spec[1:], depth+1); err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "%s = append(%s, %s)\n}\n}\n", v, v, elem)
+		return nil
+
+	case "struct":
+		g.check(fmt.Sprintf("err = %s.Read(r)", v))
+	case "enum":
+		g.used["buffer"] = true
+		g.check(fmt.Sprintf("%s, err = (buffer.Enum[%s]{}).Read(r)", v, g.typeString(t)))
+	default:
+		typ := types.ExprString(t)
+		expr, goType := g.useBase(spec[0], typ)
+		if typ == goType {
+			g.check(fmt.Sprintf("%s, err = %s.Read(r)", v, expr))
+		} else {
+			value := fmt.Sprintf("v%d", depth)
+			fmt.Fprintf(&g.b, "{\nvar %s %s\n", value, goType)
+			g.check(fmt.Sprintf("%s, err = %s.Read(r)", value, expr))
+			fmt.Fprintf(&g.b, "%s = %s(%s)\n}\n", v, g.typeString(t), value)
+		}
+	}
+	return nil
+}
+
+func (g *generator) write(v string, t ast.Expr, spec []string, depth int) error {
+	spec, err := g.resolve(t, spec)
+	if err != nil {
+		return err
+	}
+	switch spec[0] {
+	case "opt":
+		var present string
+		star, isPointer := t.(*ast.StarExpr)
+		if isPointer {
+			present = v + " != nil"
+		} else if present, err = g.presentCondition(v, t, spec[1:]); err != nil {
+			return err
+		}
+		g.useBase("bool", "bool")
+		g.check(fmt.Sprintf("err = buffer.Bool.Write(w, %s)", present))
+		fmt.Fprintf(&g.b, "if %s {\n", present)
+		if isPointer {
+			err = g.write(deref(v), star.X, spec[1:], depth+1)
+		} else {
+			err = g.write(v, t, spec[1:], depth+1)
+		}
+		g.b.WriteString("}\n")
+		return err
+
+	case "list":
+		array, ok := t.(*ast.ArrayType)
+		if !ok || array.Len != nil {
+			return fmt.Errorf("list encoding requires a slice, not %s", types.ExprString(t))
+		}
+		value := fmt.Sprintf("v%d", depth)
+		g.useBase("varint", "int32")
+		g.check(fmt.Sprintf("err = buffer.VarInt.Write(w, int32(len(%s)))", v))
+		fmt.Fprintf(&g.b, "for _, %s := range %s {\n", value, v)
+		err = g.write(value, array.Elt, spec[1:], depth+1)
+		g.b.WriteString("}\n")
+		return err
+
+	case "struct":
+		g.check(fmt.Sprintf("err = %s.Write(w)", v))
+	case "enum":
+		g.used["buffer"] = true
+		g.check(fmt.Sprintf("err = (buffer.Enum[%s]{}).Write(w, %s)", g.typeString(t), v))
+	default:
+		typ := types.ExprString(t)
+		expr, goType := g.useBase(spec[0], typ)
+		if typ != goType {
+			v = fmt.Sprintf("%s(%s)", goType, v)
+		}
+		g.check(fmt.Sprintf("err = %s.Write(w, %s)", expr, v))
+	}
+	return nil
+}
+
+// presentCondition returns the condition for an optional value to be present, which is that it is not
+// nil (for slices and interfaces) or the zero value.
+func (g *generator) presentCondition(v string, t ast.Expr, spec []string) (string, error) {
+	spec, err := g.resolve(t, spec)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := t.(*ast.ArrayType); ok {
+		return v + " != nil", nil
+	}
+	switch spec[0] {
+	case "text", "textjson", "nbt":
+		return v + " != nil", nil
+	case "bool":
+		return v, nil
+	case "string", "identifier":
+		return v + ` != ""`, nil
+	case "byte", "short", "uint16", "int", "varint", "long", "varlong", "float", "double", "enum":
+		return v + " != 0", nil
+	}
+	return fmt.Sprintf("%s != (%s{})", v, g.typeString(t)), nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	src, err := generate(os.DirFS("../../../pkg/packet"), "codec_gen.go")
+	require.NoError(t, err)
+	existing, err := os.ReadFile("../../../pkg/packet/codec_gen.go")
+	require.NoError(t, err)
+	require.Equal(t, string(existing), string(src), "codec_gen.go is out of date, run go generate ./pkg/packet")
+}
+
+const testSource = `package packet
+
+import (
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/nbt"
+)
+
+type Mode int32
+
+func (m Mode) Validate() bool { return m >= 0 }
+
+type Count int32
+
+//kite:codec
+type Entry struct {
+	Name string
+}
+
+//kite:packet Config=ClientConfigTestID Play=ClientPlayTestID
+type ClientTest struct {
+	ID      int32 ` + "`mc:\"int\"`" + `
+	Mode    Mode
+	Count   Count
+	Owner   uuid.UUID ` + "`mc:\"opt\"`" + `
+	Entries []Entry
+	Parent  *Entry
+	Data    nbt.Tag
+	Skipped string ` + "`mc:\"-\"`" + `
+	private string
+}
+
+type ServerTest struct{}
+
+func (p *ServerTest) Direction() Direction { return Clientbound }
+func (p *ServerTest) String() string       { return "test" }
+`
+
+func TestGenerate(t *testing.T) {
+	src, err := generate(fstest.MapFS{
+		"test.go":      {Data: []byte(testSource)},
+		"codec_gen.go": {Data: []byte("not go")},
+	}, "codec_gen.go")
+	require.NoError(t, err)
+
+	for _, snippet := range []string{
+		"import (\n\t\"fmt\"\n\t\"io\"\n\t\"log/slog\"\n\n\t\"github.com/google/uuid\"\n\t\"github.com/mworzala/kite/pkg/buffer\"\n)",
+		"func (p *Entry) Read(r io.Reader) (err error) {\n\tif p.Name, err = buffer.String.Read(r); err != nil {",
+		"func (p *ClientTest) Direction() Direction { return Serverbound }",
+		"return stateId2(state, Config, Play, ClientConfigTestID, ClientPlayTestID)",
+		"if p.ID, err = buffer.Int.Read(r); err != nil {\n\t\treturn fmt.Errorf(\"ClientTest.ID: %w\", err)",
+		"if p.Mode, err = (buffer.Enum[Mode]{}).Read(r); err != nil {",
+		"var v0 int32\n\t\tif v0, err = buffer.VarInt.Read(r); err != nil {",
+		"p.Count = Count(v0)",
+		"if err = buffer.VarInt.Write(w, int32(p.Count)); err != nil {",
+		"if err = buffer.Bool.Write(w, p.Owner != (uuid.UUID{})); err != nil {",
+		"if n0, err = buffer.ReadListLength(r, buffer.MaxLength); err != nil {",
+		"p.Entries = make([]Entry, 0, buffer.Prealloc(r, n0))\n\t\tfor range n0 {\n\t\t\tvar e0 Entry\n\t\t\tif err = e0.Read(r); err != nil {",
+		"p.Entries = append(p.Entries, e0)",
+		"p.Parent = new(Entry)\n\t\t\tif err = (*p.Parent).Read(r); err != nil {",
+		"if p.Data, err = buffer.NBT.Read(r); err != nil {",
+		"func (p *ClientTest) String() string", // Aligned by gofmt
+		"{ return FormatPacket(p) }\n",
+		"func (p *ClientTest) LogValue() slog.Value { return PacketLogValue(p) }",
+		"func (p *ServerTest) LogValue() slog.Value { return PacketLogValue(p) }",
+		"var (\n\t_ Packet = (*ClientTest)(nil)\n)",
+	} {
+		require.Contains(t, string(src), snippet)
+	}
+	require.NotContains(t, string(src), "pkg/nbt") // Only used by the field type
+	require.NotContains(t, string(src), "Skipped")
+	require.NotContains(t, string(src), "private")
+	require.NotContains(t, string(src), "_ Packet = (*Entry)(nil)")
+	require.NotContains(t, string(src), "func (p *ServerTest) String()") // Declared by the source
+	require.NotContains(t, string(src), "func (p *Entry) String()")
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	for name, source := range map[string]string{
+		"state":      "//kite:packet Game=ClientGameTestID\ntype ClientTest struct{}",
+		"argument":   "//kite:packet Play\ntype ClientTest struct{}",
+		"prefix":     "//kite:packet Play=ClientPlayTestID\ntype Test struct{}",
+		"directive":  "//kite:message\ntype Test struct{}",
+		"encoding":   "//kite:codec\ntype Test struct {\n\tValue int32 `mc:\"varshort\"`\n}",
+		"default":    "//kite:codec\ntype Test struct {\n\tValue int\n}",
+		"unexpected": "//kite:codec\ntype Test struct {\n\tValue int32 `mc:\"varint,string\"`\n}",
+		"list":       "//kite:codec\ntype Test struct {\n\tValue string `mc:\"list,string\"`\n}",
+		"embedded":   "type Base struct{}\n\n//kite:codec\ntype Test struct {\n\tBase\n}",
+		"struct":     "//kite:codec\ntype Test int32",
+		"syntax":     "//kite:codec\ntype Test struct {",
+	} {
+		_, err := generate(fstest.MapFS{
+			"test.go": {Data: []byte("package packet\n\n" + source + "\n")},
+		}, "codec_gen.go")
+		require.Error(t, err, name)
+	}
+}
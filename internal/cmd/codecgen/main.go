@@ -0,0 +1,39 @@
+// Command codecgen generates Read and Write methods for annotated structs in the packet package, as a
+// faster alternative to buffer.ReadStruct and buffer.WriteStruct. It is run by go generate in pkg/packet.
+//
+// Fields are encoded as described by buffer.ReadStruct, using the same mc struct tags. Structs are
+// annotated with a comment directive:
+//
+//	//kite:packet Config=ClientConfigKeepAliveID Play=ClientPlayKeepAliveID
+//	type ClientKeepAlive struct { ... }
+//
+// A packet directive also generates the Direction and ID methods (from the Client or Server prefix of
+// the type name and the listed states and ID constants) and asserts that the type implements Packet.
+// A //kite:codec directive generates only Read and Write, for structs nested in packets.
+//
+// Every packet type, annotated or with a hand-written Direction method, also gets String and LogValue
+// methods using FormatPacket and PacketLogValue, unless it declares its own.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+	out := flag.String("out", "codec_gen.go", "file to write")
+	flag.Parse()
+
+	src, err := generate(os.DirFS("."), *out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if existing, err := os.ReadFile(*out); err == nil && bytes.Equal(existing, src) {
+		return
+	}
+	if err = os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// states are the protocol states in order, with the names used in the data files and constants.
+var states = []struct{ data, name string }{
+	{"handshake", "Handshake"},
+	{"status", "Status"},
+	{"login", "Login"},
+	{"config", "Config"},
+	{"play", "Play"},
+}
+
+// directions are the packet directions in the order they are generated. Serverbound packets are sent by
+// the client, so their constants are prefixed with Client (and clientbound with Server).
+var directions = []struct{ data, name, prefix string }{
+	{"serverbound", "Serverbound", "Client"},
+	{"clientbound", "Clientbound", "Server"},
+}
+
+type version struct {
+	protocol int
+	name     string // The first release using the protocol, for example 1.20.3
+	releases string // All releases using the protocol, for example 1.20.3 and 1.20.4
+
+	// packets holds the packet names for every state and direction, in wire ID order.
+	packets [][][]string
+}
+
+func (v *version) constName() string {
+	return "Version" + strings.ReplaceAll(v.name, ".", "_")
+}
+
+type packetInfo struct {
+	name string
+	note string
+}
+
+// generate reads the data files from fsys and returns the generated source.
+func generate(fsys fs.FS) ([]byte, error) {
+	versions, notes, err := readData(fsys)
+	if err != nil {
+		return nil, err
+	}
+	latest := versions[len(versions)-1]
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by packetgen from the files in data; DO NOT EDIT.\n\npackage packet\n\n")
+
+	b.WriteString("const (\n")
+	for _, v := range versions {
+		fmt.Fprintf(&b, "%s Version = %d // %s\n", v.constName(), v.protocol, v.releases)
+	}
+	fmt.Fprintf(&b, "\nOldestVersion = %s\nLatestVersion = %s\n)\n\n", versions[0].constName(), latest.constName())
+
+	b.WriteString("var versionNames = map[Version]string{\n")
+	for _, v := range versions {
+		fmt.Fprintf(&b, "%s: %q,\n", v.constName(), v.name)
+	}
+	b.WriteString("}\n\n")
+
+	var names bytes.Buffer
+	for s, state := range states {
+		stateNames := false
+		for d, direction := range directions {
+			packets := collectPackets(versions, notes, s, d)
+			if len(packets) == 0 {
+				continue
+			}
+			if !stateNames {
+				fmt.Fprintf(&names, "%s: {\n", state.name)
+				stateNames = true
+			}
+			fmt.Fprintf(&names, "%s: {\n", direction.name)
+			for _, p := range packets {
+				if p.name != "" {
+					fmt.Fprintf(&names, "%q,\n", p.name)
+				}
+			}
+			names.WriteString("},\n")
+
+			b.WriteString("\n")
+			if s == 0 && d == 0 {
+				b.WriteString("// Packet IDs are named after the side sending the packet and its state. They are the IDs used by\n")
+				b.WriteString("// LatestVersion, see Version.WireID for other versions. Names mostly match mojang, and are noted\n")
+				b.WriteString("// when different.\n")
+			}
+			b.WriteString("const (\n")
+			for i, p := range packets {
+				if p.name == "" {
+					b.WriteString("\n// Packets which no longer exist in LatestVersion.\n")
+					continue
+				}
+				b.WriteString(direction.prefix + state.name + p.name + "ID")
+				if i == 0 {
+					b.WriteString(" = iota")
+				}
+				if p.note != "" {
+					b.WriteString(" // " + p.note)
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString(")\n")
+		}
+		if stateNames {
+			names.WriteString("},\n")
+		}
+	}
+
+	b.WriteString("\n// packetNames holds the names of the packet ID constants of every state and direction, in ID order.\n")
+	b.WriteString("var packetNames = [Play + 1][Serverbound + 1][]string{\n")
+	b.Write(names.Bytes())
+	b.WriteString("}\n")
+
+	b.WriteString("\n// versionPacketIDs lists the packets of every version, in wire ID order.\n")
+	b.WriteString("var versionPacketIDs = map[Version]versionIDs{\n")
+	for _, v := range versions {
+		fmt.Fprintf(&b, "%s: {\n", v.constName())
+		for s, state := range states {
+			if len(v.packets[s][0]) == 0 && len(v.packets[s][1]) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "%s: {\n", state.name)
+			for d, direction := range directions {
+				if len(v.packets[s][d]) == 0 {
+					continue
+				}
+				fmt.Fprintf(&b, "%s: {\n", direction.name)
+				for _, name := range v.packets[s][d] {
+					fmt.Fprintf(&b, "%s%s%sID,\n", direction.prefix, state.name, name)
+				}
+				b.WriteString("},\n")
+			}
+			b.WriteString("},\n")
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// collectPackets returns the packets of a state and direction in the latest version, followed by an empty
+// separator and the packets which only exist in older versions (without a separator if there are no others).
+func collectPackets(versions []*version, notes map[string]string, state, direction int) []packetInfo {
+	var packets []packetInfo
+	seen, removed := map[string]bool{}, false
+	for i := len(versions) - 1; i >= 0; i-- {
+		for _, name := range versions[i].packets[state][direction] {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			note := notes[noteKey(state, direction, name)]
+			if i < len(versions)-1 {
+				note = "Removed in " + versions[i+1].name
+				if !removed && len(packets) > 0 {
+					packets = append(packets, packetInfo{})
+				}
+				removed = true
+			}
+			packets = append(packets, packetInfo{name: name, note: note})
+		}
+	}
+	return packets
+}
+
+func noteKey(state, direction int, name string) string {
+	return fmt.Sprintf("%d/%d/%s", state, direction, name)
+}
+
+// readData reads every version from fsys, sorted by protocol, and the notes of each packet from the
+// newest version containing it.
+func readData(fsys fs.FS) ([]*version, map[string]string, error) {
+	records, err := readCSV(fsys, "versions.csv", []string{"protocol", "name", "releases"})
+	if err != nil {
+		return nil, nil, err
+	}
+	var versions []*version
+	for _, record := range records {
+		protocol, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("versions.csv: invalid protocol %q", record[0])
+		}
+		versions = append(versions, &version{protocol: protocol, name: record[1], releases: record[2]})
+	}
+	if len(versions) == 0 {
+		return nil, nil, fmt.Errorf("versions.csv: no versions")
+	}
+	slices.SortFunc(versions, func(a, b *version) int { return a.protocol - b.protocol })
+
+	notes := map[string]string{}
+	for _, v := range versions {
+		if v.packets, err = readPackets(fsys, v.protocol, notes); err != nil {
+			return nil, nil, err
+		}
+	}
+	return versions, notes, nil
+}
+
+// readPackets reads the packets of a version, adding their notes to notes.
+func readPackets(fsys fs.FS, protocol int, notes map[string]string) ([][][]string, error) {
+	file := fmt.Sprintf("%d.csv", protocol)
+	records, err := readCSV(fsys, file, []string{"state", "direction", "id", "name", "type", "note"})
+	if err != nil {
+		return nil, err
+	}
+
+	packets := make([][][]string, len(states))
+	for s := range packets {
+		packets[s] = make([][]string, len(directions))
+	}
+	for line, record := range records {
+		s := slices.IndexFunc(states, func(state struct{ data, name string }) bool { return state.data == record[0] })
+		d := slices.IndexFunc(directions, func(dir struct{ data, name, prefix string }) bool { return dir.data == record[1] })
+		id, err := strconv.ParseInt(record[2], 0, 32)
+		name := record[3]
+		switch {
+		case s < 0:
+			return nil, fmt.Errorf("%s:%d: invalid state %q", file, line+2, record[0])
+		case d < 0:
+			return nil, fmt.Errorf("%s:%d: invalid direction %q", file, line+2, record[1])
+		case err != nil:
+			return nil, fmt.Errorf("%s:%d: invalid id %q", file, line+2, record[2])
+		case int(id) != len(packets[s][d]):
+			return nil, fmt.Errorf("%s:%d: expected id %#x, IDs must be sequential", file, line+2, len(packets[s][d]))
+		case name == "" || slices.Contains(packets[s][d], name):
+			return nil, fmt.Errorf("%s:%d: missing or duplicate name %q", file, line+2, name)
+		}
+		packets[s][d] = append(packets[s][d], name)
+		if note := record[5]; note != "" {
+			notes[noteKey(s, d, name)] = note
+		}
+	}
+	return packets, nil
+}
+
+// readCSV reads a CSV file with the given header, returning the records after it.
+func readCSV(fsys fs.FS, name string, header []string) ([][]string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = len(header)
+	first, err := r.Read()
+	if err == io.EOF || (err == nil && !slices.Equal(first, header)) {
+		return nil, fmt.Errorf("%s: expected header %s", name, strings.Join(header, ","))
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	src, err := generate(os.DirFS("../../../pkg/packet/data"))
+	require.NoError(t, err)
+	existing, err := os.ReadFile("../../../pkg/packet/ids.go")
+	require.NoError(t, err)
+	require.Equal(t, string(existing), string(src), "ids.go is out of date, run go generate ./pkg/packet")
+}
+
+func TestGenerate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"versions.csv": {Data: []byte("protocol,name,releases\n2,1.1,1.1\n1,1.0,1.0 and 1.0.1\n")},
+		"1.csv": {Data: []byte("state,direction,id,name,type,note\n" +
+			"play,clientbound,0x00,First,,\n" +
+			"play,clientbound,0x01,Old,,\n" +
+			"play,serverbound,0x00,Renamed,,\n")},
+		"2.csv": {Data: []byte("state,direction,id,name,type,note\n" +
+			"play,clientbound,0x00,New,,Mojang is something else\n" +
+			"play,clientbound,0x01,First,,\n")},
+	}
+	src, err := generate(fsys)
+	require.NoError(t, err)
+	require.Contains(t, string(src), "Version1_0 Version = 1 // 1.0 and 1.0.1")
+	require.Contains(t, string(src), "OldestVersion = Version1_0\n\tLatestVersion = Version1_1\n")
+	require.Contains(t, string(src), `const (
+	ServerPlayNewID = iota // Mojang is something else
+	ServerPlayFirstID
+
+	// Packets which no longer exist in LatestVersion.
+	ServerPlayOldID // Removed in 1.1
+)`)
+	require.Contains(t, string(src), `const (
+	ClientPlayRenamedID = iota // Removed in 1.1
+)`)
+	require.Contains(t, string(src), `var packetNames = [Play + 1][Serverbound + 1][]string{
+	Play: {
+		Serverbound: {
+			"Renamed",
+		},
+		Clientbound: {
+			"New",
+			"First",
+			"Old",
+		},
+	},
+}`)
+	require.Contains(t, string(src), `	Version1_0: {
+		Play: {
+			Serverbound: {
+				ClientPlayRenamedID,
+			},
+			Clientbound: {
+				ServerPlayFirstID,
+				ServerPlayOldID,
+			},
+		},
+	},`)
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	versions := &fstest.MapFile{Data: []byte("protocol,name,releases\n1,1.0,1.0\n")}
+	for name, data := range map[string]string{
+		"gap":       "play,clientbound,0x01,First,,\n",
+		"duplicate": "play,clientbound,0x00,First,,\nplay,clientbound,0x01,First,,\n",
+		"state":     "game,clientbound,0x00,First,,\n",
+		"columns":   "play,clientbound,0x00,First\n",
+	} {
+		_, err := generate(fstest.MapFS{
+			"versions.csv": versions,
+			"1.csv":        {Data: []byte("state,direction,id,name,type,note\n" + data)},
+		})
+		require.Error(t, err, name)
+	}
+}
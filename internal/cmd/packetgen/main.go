@@ -0,0 +1,31 @@
+// Command packetgen generates the packet ID constants and per-version ID tables of the packet package
+// from the data files in pkg/packet/data. It is run by go generate in pkg/packet.
+//
+// data/versions.csv lists the supported protocol versions, and data/<protocol>.csv lists the packets
+// of each version with their wire IDs. The ID constants are those of the latest version, followed by
+// packets which only exist in older versions.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+	data := flag.String("data", "data", "directory containing the data files")
+	out := flag.String("out", "ids.go", "file to write")
+	flag.Parse()
+
+	src, err := generate(os.DirFS(*data))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if existing, err := os.ReadFile(*out); err == nil && bytes.Equal(existing, src) {
+		return
+	}
+	if err = os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
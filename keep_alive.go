@@ -0,0 +1,151 @@
+package kite
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/mworzala/kite/pkg/text"
+)
+
+// KeepAlive sends keep-alive packets to a client and validates its responses, for the periods where the
+// proxy rather than a backend server owns the connection (for example while switching servers).
+//
+// It is installed as an inbound interceptor by NewKeepAlive and does nothing until Start is called.
+// While running, or while waiting for a response to a keep-alive it sent, it consumes the matching
+// responses so that they are not forwarded to a backend. Other keep-alive responses are passed on.
+type KeepAlive struct {
+	conn     *Conn
+	interval time.Duration
+	timeout  time.Duration
+
+	// Reason is sent to the client if it does not respond in time. It must be set before calling Start.
+	Reason text.Component
+
+	mu           sync.Mutex
+	stop         chan struct{} // Closed by Stop, nil when not running
+	pendingID    int64
+	pendingSince time.Time // Zero if no response is pending
+	latency      time.Duration
+}
+
+// NewKeepAlive creates a keep-alive manager for a connection to a client, sending a keep-alive every
+// interval and disconnecting the client if it takes longer than timeout to respond. The vanilla server
+// uses 15 seconds for both.
+//
+// Like other interceptors, it must be created before the connection is served.
+func NewKeepAlive(c *Conn, interval, timeout time.Duration) *KeepAlive {
+	k := &KeepAlive{
+		conn:     c,
+		interval: interval,
+		timeout:  timeout,
+		Reason:   DefaultTimeoutReason,
+	}
+	c.AddInboundInterceptor(k.intercept)
+	return k
+}
+
+// Start begins sending keep-alive packets. It must only be called in the config and play states.
+func (k *KeepAlive) Start() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.stop != nil {
+		return
+	}
+	k.stop = make(chan struct{})
+	go k.run(k.stop)
+}
+
+// Stop stops sending keep-alive packets, for example once a backend server owns the connection again.
+// A response to a keep-alive which has already been sent is still consumed.
+func (k *KeepAlive) Stop() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.stop == nil {
+		return
+	}
+	close(k.stop)
+	k.stop = nil
+}
+
+// Latency returns the round trip time of the last answered keep-alive, or zero if none has been answered.
+func (k *KeepAlive) Latency() time.Duration {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.latency
+}
+
+func (k *KeepAlive) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(k.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if !k.tick(now) {
+				// Allow Start to be called again, unless it was already stopped (and maybe restarted).
+				k.mu.Lock()
+				if k.stop == stop {
+					k.stop = nil
+				}
+				k.mu.Unlock()
+				return
+			}
+		}
+	}
+}
+
+// tick sends a keep-alive, or disconnects the client if the previous one has not been answered in time.
+// It returns false once the connection can no longer be used.
+func (k *KeepAlive) tick(now time.Time) bool {
+	k.mu.Lock()
+	if !k.pendingSince.IsZero() {
+		expired := now.Sub(k.pendingSince) >= k.timeout
+		k.mu.Unlock()
+		if expired {
+			_ = k.conn.Disconnect(k.Reason)
+			return false
+		}
+		return true // Like the vanilla server, only one keep-alive is sent at a time
+	}
+	k.pendingID, k.pendingSince = now.UnixMilli(), now
+	id := k.pendingID
+	k.mu.Unlock()
+
+	if err := k.conn.SendPacket(&packet.ServerKeepAlive{KeepAliveID: id}); err != nil {
+		k.mu.Lock()
+		if k.pendingID == id {
+			k.pendingSince = time.Time{}
+		}
+		k.mu.Unlock()
+		return false
+	}
+	return true
+}
+
+func (k *KeepAlive) intercept(c *Conn, pb PacketBuffer, next PacketHandler) error {
+	// Keep-alives only exist in the config and play states. Elsewhere their ID is InvalidState, which is
+	// also the ID of unknown packets.
+	state := c.GetState()
+	if (state != packet.Config && state != packet.Play) || pb.Id < 0 || pb.Id != new(packet.ClientKeepAlive).ID(state) {
+		return next(pb)
+	}
+
+	pkt := new(packet.ClientKeepAlive)
+	if err := pb.Peek(pkt); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	if k.pendingSince.IsZero() || pkt.KeepAliveID != k.pendingID {
+		k.mu.Unlock()
+		return next(pb) // A response to a keep-alive sent by a backend
+	}
+	k.latency = time.Since(k.pendingSince)
+	k.pendingSince = time.Time{}
+	k.mu.Unlock()
+
+	pb.Consume()
+	return nil
+}
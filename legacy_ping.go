@@ -0,0 +1,174 @@
+package kite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// legacyPingHostPrefix is the start of the plugin message sent by 1.6 clients after 0xFE 0x01.
+var legacyPingHostPrefix = append([]byte{0xFA, 0x00, 0x0B}, encodeUTF16("MC|PingHost")...)
+
+// LegacyPingVersion identifies the format of a legacy server list ping, which decides the response format.
+type LegacyPingVersion int
+
+const (
+	// LegacyPingBeta is sent by Beta 1.8 to 1.3 clients as a single 0xFE byte.
+	LegacyPingBeta LegacyPingVersion = iota
+	// LegacyPing1_4 is sent by 1.4 and 1.5 clients as 0xFE 0x01.
+	LegacyPing1_4
+	// LegacyPing1_6 is sent by 1.6 clients as 0xFE 0x01 followed by an MC|PingHost plugin message.
+	LegacyPing1_6
+)
+
+// A LegacyPing is a server list ping from a pre-1.7 client.
+type LegacyPing struct {
+	Version LegacyPingVersion
+
+	// Protocol, Hostname and Port are only set for LegacyPing1_6.
+	Protocol int
+	Hostname string
+	Port     int
+}
+
+// A LegacyPingResponse is the server list information returned to a legacy ping.
+type LegacyPingResponse struct {
+	// Protocol and Version are shown to 1.4+ clients, which display Version if Protocol does not match their own.
+	Protocol int
+	Version  string
+
+	// MOTD may contain legacy § formatting codes, which are removed for Beta clients.
+	MOTD        string
+	Online, Max int
+}
+
+// A LegacyPingHandler answers a legacy server list ping. If it returns a nil response, the connection
+// is closed without a reply.
+type LegacyPingHandler func(c *Conn, ping *LegacyPing) (*LegacyPingResponse, error)
+
+// SetLegacyPingHandler sets the handler used to answer legacy server list pings received in the handshake
+// state. Without a handler, Serve returns ErrLegacyPing when one is received.
+func (c *Conn) SetLegacyPingHandler(handler LegacyPingHandler) {
+	c.legacyPingHandler = handler
+}
+
+// handleLegacyPing answers the legacy ping at the start of data, then closes the connection. If data
+// does not yet contain the full ping, nothing is done so that it can be retried with more data.
+func (c *Conn) handleLegacyPing(data []byte) error {
+	if c.legacyPingHandler == nil {
+		return ErrLegacyPing
+	}
+	ping, ok := parseLegacyPing(data)
+	if !ok {
+		if len(data) > maxPacketSizePreConfig {
+			return fmt.Errorf("%w: legacy ping too large", ErrMalformedFrame)
+		}
+		return nil
+	}
+
+	res, err := c.legacyPingHandler(c, ping)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if res == nil {
+		return nil
+	}
+
+	_ = c.delegate.SetWriteDeadline(time.Now().Add(disconnectTimeout))
+	c.lockWriter()
+	err = c.writeLocked(res.encode(ping.Version), -1)
+	c.wlock.Unlock()
+	if err != nil {
+		return err
+	}
+	return c.Flush()
+}
+
+// parseLegacyPing parses a ping starting with 0xFE. Like the vanilla server, the version is decided by
+// the data which has been received, since older clients wait for a response after the first bytes.
+// ok is false only if a 1.6 ping has been partially received.
+func parseLegacyPing(data []byte) (ping *LegacyPing, ok bool) {
+	if len(data) < 2 || data[1] != 0x01 {
+		return &LegacyPing{Version: LegacyPingBeta}, true
+	}
+
+	// Anything other than a well-formed MC|PingHost message is answered as a 1.4 ping.
+	ping = &LegacyPing{Version: LegacyPing1_4}
+	rest := data[2:]
+	n := min(len(rest), len(legacyPingHostPrefix))
+	if n == 0 || !bytes.Equal(rest[:n], legacyPingHostPrefix[:n]) {
+		return ping, true
+	}
+	rest = rest[n:]
+	if n < len(legacyPingHostPrefix) || len(rest) < 2 {
+		return nil, false
+	}
+	length := int(binary.BigEndian.Uint16(rest))
+	if len(rest) < 2+length {
+		return nil, false
+	}
+
+	// Protocol (byte), hostname (UTF-16 string with a short length prefix) and port (int).
+	payload := rest[2 : 2+length]
+	if len(payload) < 3 {
+		return ping, true
+	}
+	hostLength := int(binary.BigEndian.Uint16(payload[1:]))
+	if len(payload) != 7+hostLength*2 {
+		return ping, true
+	}
+	hostname := payload[3 : 3+hostLength*2]
+	units := make([]uint16, hostLength)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(hostname[i*2:])
+	}
+	return &LegacyPing{
+		Version:  LegacyPing1_6,
+		Protocol: int(payload[0]),
+		Hostname: string(utf16.Decode(units)),
+		Port:     int(int32(binary.BigEndian.Uint32(payload[3+hostLength*2:]))),
+	}, true
+}
+
+// encode returns the kick packet carrying the response in the format expected for the given ping version.
+func (r *LegacyPingResponse) encode(version LegacyPingVersion) []byte {
+	var message string
+	if version == LegacyPingBeta {
+		// § separates the fields, so it cannot appear in the MOTD.
+		message = strings.Join([]string{
+			strings.ReplaceAll(r.MOTD, "§", ""),
+			strconv.Itoa(r.Online),
+			strconv.Itoa(r.Max),
+		}, "§")
+	} else {
+		message = strings.Join([]string{
+			"§1",
+			strconv.Itoa(r.Protocol),
+			r.Version,
+			r.MOTD,
+			strconv.Itoa(r.Online),
+			strconv.Itoa(r.Max),
+		}, "\x00")
+	}
+
+	encoded := encodeUTF16(message)
+	out := make([]byte, 0, 3+len(encoded))
+	out = append(out, 0xFF)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(encoded)/2))
+	return append(out, encoded...)
+}
+
+// encodeUTF16 encodes s as UTF-16BE, the string encoding used by pre-1.7 clients.
+func encodeUTF16(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		out = binary.BigEndian.AppendUint16(out, u)
+	}
+	return out
+}
@@ -0,0 +1,134 @@
+package kite
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyPingHost encodes the ping sent by 1.6 clients.
+func legacyPingHost(protocol byte, hostname string, port int32) []byte {
+	host := encodeUTF16(hostname)
+	data := []byte{0xFE, 0x01}
+	data = append(data, legacyPingHostPrefix...)
+	data = binary.BigEndian.AppendUint16(data, uint16(7+len(host)))
+	data = append(data, protocol)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(host)/2))
+	data = append(data, host...)
+	return binary.BigEndian.AppendUint32(data, uint32(port))
+}
+
+// serveLegacyPing writes each chunk separately to a server connection, returning everything written
+// back before the connection was closed and the result of Serve.
+func serveLegacyPing(t *testing.T, handler LegacyPingHandler, chunks ...[]byte) ([]byte, error) {
+	t.Helper()
+	cc, sc := net.Pipe()
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error { return nil })
+	server.SetLegacyPingHandler(handler)
+	t.Cleanup(func() {
+		_ = cc.Close()
+		server.Close()
+	})
+
+	result := make(chan error, 1)
+	go func() {
+		result <- server.Serve(context.Background())
+		server.Close()
+	}()
+	go func() {
+		for _, chunk := range chunks {
+			if _, err := cc.Write(chunk); err != nil {
+				return
+			}
+		}
+	}()
+	out, _ := io.ReadAll(cc)
+	return out, receive(t, result)
+}
+
+func TestConn_LegacyPing(t *testing.T) {
+	response := &LegacyPingResponse{Protocol: 78, Version: "1.6.4", MOTD: "§aKite", Online: 5, Max: 100}
+	modern := append([]byte{0xFF, 0x00, 0x18}, encodeUTF16("§1\x0078\x001.6.4\x00§aKite\x005\x00100")...)
+
+	tests := []struct {
+		name     string
+		chunks   [][]byte
+		ping     *LegacyPing
+		expected []byte
+	}{
+		{
+			name:     "beta",
+			chunks:   [][]byte{{0xFE}},
+			ping:     &LegacyPing{Version: LegacyPingBeta},
+			expected: append([]byte{0xFF, 0x00, 0x0B}, encodeUTF16("aKite§5§100")...),
+		},
+		{
+			name:     "1.4",
+			chunks:   [][]byte{{0xFE, 0x01}},
+			ping:     &LegacyPing{Version: LegacyPing1_4},
+			expected: modern,
+		},
+		{
+			name:     "1.6",
+			chunks:   [][]byte{legacyPingHost(78, "play.example.com", 25565)},
+			ping:     &LegacyPing{Version: LegacyPing1_6, Protocol: 78, Hostname: "play.example.com", Port: 25565},
+			expected: modern,
+		},
+		{
+			name: "1.6 split",
+			chunks: [][]byte{
+				legacyPingHost(78, "localhost", 25565)[:4],
+				legacyPingHost(78, "localhost", 25565)[4:30],
+				legacyPingHost(78, "localhost", 25565)[30:],
+			},
+			ping:     &LegacyPing{Version: LegacyPing1_6, Protocol: 78, Hostname: "localhost", Port: 25565},
+			expected: modern,
+		},
+		{
+			name:     "1.6 unknown plugin message",
+			chunks:   [][]byte{{0xFE, 0x01, 0xFA, 0x00, 0x01, 0x00, 0x41}},
+			ping:     &LegacyPing{Version: LegacyPing1_4},
+			expected: modern,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *LegacyPing
+			out, err := serveLegacyPing(t, func(c *Conn, ping *LegacyPing) (*LegacyPingResponse, error) {
+				received = ping
+				return response, nil
+			}, tt.chunks...)
+			require.NoError(t, err)
+			require.Equal(t, tt.ping, received)
+			require.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestConn_LegacyPing_Encoding(t *testing.T) {
+	res := &LegacyPingResponse{MOTD: "A", Online: 1, Max: 2}
+	expected := []byte{0xFF, 0x00, 0x05, 0x00, 0x41, 0x00, 0xA7, 0x00, 0x31, 0x00, 0xA7, 0x00, 0x32}
+	require.Equal(t, expected, res.encode(LegacyPingBeta))
+}
+
+func TestConn_LegacyPing_NoResponse(t *testing.T) {
+	out, err := serveLegacyPing(t, func(c *Conn, ping *LegacyPing) (*LegacyPingResponse, error) {
+		return nil, nil
+	}, []byte{0xFE, 0x01})
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func TestConn_LegacyPing_HandlerError(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+	_, err := serveLegacyPing(t, func(c *Conn, ping *LegacyPing) (*LegacyPingResponse, error) {
+		return nil, handlerErr
+	}, []byte{0xFE})
+	require.ErrorIs(t, err, handlerErr)
+}
@@ -1,22 +1,36 @@
 package kite
 
 import (
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/mworzala/kite/pkg/buffer"
 	"github.com/mworzala/kite/pkg/packet"
 )
 
+// maxDumpBytes is the number of payload bytes shown in hex by PacketBuffer.Dump.
+const maxDumpBytes = 1024
+
 // PacketBuffer represents a buffer containing a single packet.
 // Does not hold the connection state, the state can be inferred from the connection which created the buffer.
 //
 // Packet buffers must be fully consumed. If not reading the content, the Consume method should be called.
 // Forwarding a packet buffer to another connection does count as consumption.
+//
+// Received packets whose wire ID is not known for the state and protocol version of the connection, for
+// example from a client on an unsupported version, have packet.InvalidState as their Id. They can be
+// consumed, or forwarded to a connection with the same protocol version.
 type PacketBuffer struct {
-	Id       int            // The protocol ID of the packet
+	Id       int            // The ID of the packet, one of the ID constants in the packet package, or packet.InvalidState if unknown
 	internal *buffer.Buffer // Delegate buffer containing the packet data with configured mark and limit
-	mark     int            // Start location of packet in buffer
+	mark     int            // Start location of packet (ID) in buffer
 	read     bool           // Whether the packet has been read
+	version  packet.Version // Protocol version the packet (including its wire ID) is encoded for
+
+	frame     []byte // The raw frame as received, including the length prefix
+	threshold int    // Compression threshold of the connection which received the frame
 }
 
 // Consume marks the packet as read without using the data
@@ -27,11 +41,116 @@ func (p PacketBuffer) Consume() {
 	p.read = true
 }
 
+// NewPacketBuffer creates a buffer containing a packet with the given ID and payload encoded for
+// packet.LatestVersion.
+func NewPacketBuffer(id int, payload []byte) PacketBuffer {
+	buf := buffer.NewBuffer(5 + len(payload))
+	buf.WriteVarInt(int32(id))
+	_, _ = buf.Write(payload)
+	return wrapPacket(id, buf.Bytes(), packet.LatestVersion)
+}
+
+// EncodePacket encodes pkt for the given state and protocol version into a new buffer, for example to
+// replace a packet in an Interceptor.
+func EncodePacket(state packet.State, version packet.Version, pkt packet.Packet) (PacketBuffer, error) {
+	id := pkt.ID(state)
+	if id < 0 {
+		return PacketBuffer{}, fmt.Errorf("packet %T is not applicable to state %s", pkt, state.String())
+	}
+	wireID, ok := version.WireID(state, pkt.Direction(), id)
+	if !ok {
+		return PacketBuffer{}, fmt.Errorf("packet %T is not available in version %s", pkt, version.String())
+	}
+
+	buf := buffer.NewBuffer(64)
+	buf.WriteVarInt(int32(wireID))
+	if err := packet.Write(buf, pkt, version); err != nil {
+		return PacketBuffer{}, err
+	}
+	return wrapPacket(id, buf.Bytes(), version), nil
+}
+
+// wrapPacket creates a buffer from data containing a wire packet ID and payload encoded for version.
+func wrapPacket(id int, data []byte, version packet.Version) PacketBuffer {
+	internal := buffer.Wrap(data)
+	_, _ = internal.ReadVarInt()
+	return PacketBuffer{Id: id, internal: internal, version: version}
+}
+
+// Version returns the protocol version the packet is encoded for.
+func (p PacketBuffer) Version() packet.Version {
+	return p.version
+}
+
+// payload returns the encoded packet without its ID.
+func (p PacketBuffer) payload() []byte {
+	p.internal.Reset(p.mark)
+	_, _ = buffer.VarInt.Read(p.internal)
+	return p.internal.RemainingSlice()
+}
+
 func (p PacketBuffer) Read(t packet.Packet) error {
 	if p.read {
 		return errors.New("packet already read")
 	}
 
 	p.read = true
-	return t.Read(p.internal)
+	return packet.Read(p.internal, t, p.version)
+}
+
+// Peek decodes the packet into t without consuming it, so that it can still be read, consumed or forwarded.
+func (p PacketBuffer) Peek(t packet.Packet) error {
+	position := p.internal.Mark()
+	defer p.internal.Reset(position)
+
+	p.internal.Reset(p.mark)
+	if _, err := buffer.VarInt.Read(p.internal); err != nil {
+		return err
+	}
+	return packet.Read(p.internal, t, p.version)
+}
+
+// Decode reads the packet into a new instance of the type registered for its ID in registry, or a
+// packet.RawPacket if there is none. Like Peek, it does not consume the buffer, so it can be used by
+// generic tools such as loggers. The state and direction are those of the packet, which for inbound
+// packets are the state and direction of the connection.
+func (p PacketBuffer) Decode(registry *packet.Registry, state packet.State, direction packet.Direction) (packet.Packet, error) {
+	pkt := registry.New(state, direction, p.Id)
+	if err := p.Peek(pkt); err != nil {
+		return nil, err
+	}
+	return pkt, nil
+}
+
+// Dump returns a multi-line description of the packet for debugging: a header with its name, wire ID and
+// size, the packet decoded as by Decode (or the decoding error), and a hex dump of its payload. Like Peek,
+// it does not consume the buffer, and it works for packets which are not registered or fail to decode.
+func (p PacketBuffer) Dump(registry *packet.Registry, state packet.State, direction packet.Direction) string {
+	position := p.internal.Mark()
+	defer p.internal.Reset(position)
+	p.internal.Reset(p.mark)
+	wireID, _ := p.internal.ReadVarInt()
+	data := p.internal.RemainingSlice()
+
+	var b strings.Builder
+	name, ok := packet.PacketName(state, direction, p.Id)
+	if !ok {
+		name = fmt.Sprintf("unknown packet 0x%02x", wireID)
+	}
+	fmt.Fprintf(&b, "%s %s %s (wire ID 0x%02x, version %s, %d bytes)\n",
+		direction.String(), state.String(), name, wireID, p.version.String(), len(data))
+
+	if pkt, err := p.Decode(registry, state, direction); err != nil {
+		fmt.Fprintf(&b, "decoding failed: %v\n", err)
+	} else if stringer, ok := pkt.(fmt.Stringer); ok {
+		b.WriteString(stringer.String() + "\n")
+	} else {
+		b.WriteString(packet.FormatPacket(pkt) + "\n")
+	}
+
+	b.WriteString(hex.Dump(data[:min(len(data), maxDumpBytes)]))
+	if len(data) > maxDumpBytes {
+		fmt.Fprintf(&b, "... %d more bytes\n", len(data)-maxDumpBytes)
+	}
+	return b.String()
 }
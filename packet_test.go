@@ -0,0 +1,59 @@
+package kite
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketBuffer_Decode(t *testing.T) {
+	pkt := &packet.ClientPluginMessage{Channel: "kite:test", Data: []byte{1, 2, 3}}
+	pb, err := EncodePacket(packet.Play, packet.LatestVersion, pkt)
+	require.NoError(t, err)
+
+	decoded, err := pb.Decode(packet.DefaultRegistry, packet.Play, packet.Serverbound)
+	require.NoError(t, err)
+	require.Equal(t, pkt, decoded)
+
+	// The buffer is not consumed.
+	read := new(packet.ClientPluginMessage)
+	require.NoError(t, pb.Read(read))
+	require.Equal(t, pkt, read)
+
+	// Unknown packets are preserved as raw packets.
+	pb = NewPacketBuffer(packet.ClientPlayTeleportConfirmID, []byte{0x05})
+	decoded, err = pb.Decode(packet.DefaultRegistry, packet.Play, packet.Serverbound)
+	require.NoError(t, err)
+	require.Equal(t, packet.NewRawPacket(packet.Play, packet.Serverbound, packet.ClientPlayTeleportConfirmID, []byte{0x05}), decoded)
+}
+
+func TestPacketBuffer_Dump(t *testing.T) {
+	pkt := &packet.ClientPluginMessage{Channel: "kite:test", Data: make([]byte, 1100)}
+	pb, err := EncodePacket(packet.Play, packet.LatestVersion, pkt)
+	require.NoError(t, err)
+	wireID, _ := packet.LatestVersion.WireID(packet.Play, packet.Serverbound, packet.ClientPlayPluginMessageID)
+
+	lines := strings.Split(pb.Dump(packet.DefaultRegistry, packet.Play, packet.Serverbound), "\n")
+	require.Equal(t, fmt.Sprintf("serverbound play PluginMessage (wire ID 0x%02x, version %s, 1110 bytes)", wireID, packet.LatestVersion), lines[0])
+	require.Equal(t, pkt.String(), lines[1])
+	require.Equal(t, "00000000  09 6b 69 74 65 3a 74 65  73 74 00 00 00 00 00 00  |.kite:test......|", lines[2])
+	require.Equal(t, "... 86 more bytes", lines[len(lines)-2])
+
+	// The buffer is not consumed.
+	read := new(packet.ClientPluginMessage)
+	require.NoError(t, pb.Read(read))
+	require.Equal(t, pkt, read)
+
+	// Packets which fail to decode are still dumped.
+	pb = NewPacketBuffer(packet.ClientPlayChatID, []byte{0xFF})
+	dump := pb.Dump(packet.DefaultRegistry, packet.Play, packet.Serverbound)
+	require.Contains(t, dump, "serverbound play Chat")
+	require.Contains(t, dump, "decoding failed: ")
+	require.Contains(t, dump, "00000000  ff ")
+
+	pb = NewPacketBuffer(0x7F, nil)
+	require.Contains(t, pb.Dump(packet.DefaultRegistry, packet.Play, packet.Serverbound), "serverbound play unknown packet 0x7f")
+}
@@ -6,22 +6,38 @@ import (
 	"github.com/valyala/bytebufferpool"
 )
 
+// ErrBufferOverflow is returned when reading more data than remains in a Buffer.
 var ErrBufferOverflow = io.ErrShortBuffer
 
+// A Buffer reads and writes protocol data in memory. Its typed accessors (ReadVarInt, WriteVarInt, ...) work
+// on the underlying slice directly, so unlike the Type implementations they do not allocate, except to
+// return a string or to grow the buffer when writing.
+//
+// A failed read returns ErrBufferOverflow (or a decoding error) and leaves the position unchanged, so a
+// partially received value can be read again once the rest has arrived.
 type Buffer struct {
 	delegate []byte
 	position int
 	limit    int
+	wrapped  bool // Whether delegate may still be the slice passed to Wrap, which must not be written to
 }
 
+// Wrap creates a buffer reading delegate. Writes are appended to a copy, so they never modify delegate.
 func Wrap(delegate []byte) *Buffer {
 	return &Buffer{
-		delegate: delegate,
+		delegate: delegate[:len(delegate):len(delegate)],
 		limit:    len(delegate),
+		wrapped:  true,
 	}
 }
 
-// Read implements the io.Reader interface
+// NewBuffer creates an empty buffer for writing, with room for size bytes before it grows.
+func NewBuffer(size int) *Buffer {
+	return &Buffer{delegate: make([]byte, 0, size)}
+}
+
+// Read implements the io.Reader interface. Unlike most readers it reads all of p or nothing, returning
+// ErrBufferOverflow if fewer bytes remain.
 func (b *Buffer) Read(p []byte) (n int, err error) {
 	if b.position+len(p) > b.limit {
 		return 0, ErrBufferOverflow
@@ -31,6 +47,29 @@ func (b *Buffer) Read(p []byte) (n int, err error) {
 	return
 }
 
+// Write implements the io.Writer interface, appending p to the buffer. Writes extend the limit to the end of
+// the written data.
+func (b *Buffer) Write(p []byte) (n int, err error) {
+	b.delegate = append(b.delegate, p...)
+	b.limit = len(b.delegate)
+	return len(p), nil
+}
+
+// Clear empties the buffer so that it can be reused for writing. Its memory is reused, except for the slice
+// passed to Wrap, which is dropped so that later writes do not overwrite it.
+func (b *Buffer) Clear() {
+	if b.wrapped {
+		b.delegate, b.wrapped = nil, false
+	}
+	b.delegate, b.position, b.limit = b.delegate[:0], 0, 0
+}
+
+// Bytes returns the unread data without consuming it. The slice aliases the buffer, so it is only valid
+// until the next write.
+func (b *Buffer) Bytes() []byte {
+	return b.delegate[b.position:b.limit]
+}
+
 func (b *Buffer) Remaining() int {
 	return b.limit - b.position
 }
@@ -0,0 +1,316 @@
+package buffer
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/bytebufferpool"
+)
+
+// testAccessor checks that a typed accessor of Buffer encodes every value like typ, and that reading
+// truncated data fails without moving the position.
+func testAccessor[T any](t *testing.T, typ Type[T], read func(*Buffer) (T, error), write func(*Buffer, T), values ...T) {
+	t.Helper()
+	for _, value := range values {
+		var expected bytes.Buffer
+		require.NoError(t, typ.Write(&expected, value))
+
+		buf := NewBuffer(0)
+		write(buf, value)
+		require.Equal(t, expected.Bytes(), buf.Bytes(), "%v", value)
+
+		decoded, err := read(buf)
+		require.NoError(t, err, "%v", value)
+		require.Equal(t, value, decoded)
+		require.Zero(t, buf.Remaining())
+
+		truncated := Wrap(expected.Bytes()[:expected.Len()-1])
+		_, err = read(truncated)
+		require.ErrorIs(t, err, ErrBufferOverflow, "%v", value)
+		require.Zero(t, truncated.Mark(), "%v", value)
+	}
+}
+
+func TestBuffer_Accessors(t *testing.T) {
+	testAccessor(t, Byte, (*Buffer).ReadByte, func(b *Buffer, v byte) { _ = b.WriteByte(v) }, 0, 0xFF)
+	testAccessor(t, Bool, (*Buffer).ReadBool, (*Buffer).WriteBool, false, true)
+	testAccessor(t, Short, (*Buffer).ReadShort, (*Buffer).WriteShort, 0, -1, math.MinInt16)
+	testAccessor(t, Uint16, (*Buffer).ReadUint16, (*Buffer).WriteUint16, 25565, math.MaxUint16)
+	testAccessor(t, Int, (*Buffer).ReadInt, (*Buffer).WriteInt, 0, 16909060, math.MinInt32)
+	testAccessor(t, Long, (*Buffer).ReadLong, (*Buffer).WriteLong, 1, -2, math.MaxInt64)
+	testAccessor(t, VarInt, (*Buffer).ReadVarInt, (*Buffer).WriteVarInt, 0, 127, 128, 25565, math.MaxInt32, -1, math.MinInt32)
+	testAccessor(t, VarLong, (*Buffer).ReadVarLong, (*Buffer).WriteVarLong, 0, 128, math.MaxInt64, -1, math.MinInt64)
+	testAccessor(t, Float, (*Buffer).ReadFloat, (*Buffer).WriteFloat, 0, -1.5, math.MaxFloat32)
+	testAccessor(t, Double, (*Buffer).ReadDouble, (*Buffer).WriteDouble, 0, math.Pi, -math.MaxFloat64)
+	testAccessor(t, UUID, (*Buffer).ReadUUID, (*Buffer).WriteUUID, uuid.Nil, uuid.MustParse("069a79f4-44e9-4726-a5be-fca90e38aaf5"))
+	testAccessor(t, Position, (*Buffer).ReadPosition, (*Buffer).WritePosition, BlockPos{}, BlockPos{X: -33554432, Y: -2048, Z: 33554431})
+	testAccessor(t, String, func(b *Buffer) (string, error) { return b.ReadString(32767) }, (*Buffer).WriteString,
+		"a", "kite", "é😀", strings.Repeat("a", 300))
+	testAccessor(t, ByteArray, func(b *Buffer) ([]byte, error) { return b.ReadByteArray(1024) }, (*Buffer).WriteByteArray,
+		[]byte{1}, bytes.Repeat([]byte{2}, 300))
+}
+
+func TestBuffer_Invalid(t *testing.T) {
+	readVarInt := func(b *Buffer) error {
+		_, err := b.ReadVarInt()
+		return err
+	}
+	readVarLong := func(b *Buffer) error {
+		_, err := b.ReadVarLong()
+		return err
+	}
+	readString := func(b *Buffer) error {
+		_, err := b.ReadString(4)
+		return err
+	}
+	readByteArray := func(b *Buffer) error {
+		_, err := b.ReadByteArray(4)
+		return err
+	}
+	tooLong := []byte{0x0D, 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a'}
+	negative := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F}
+	for _, c := range []struct {
+		name string
+		read func(*Buffer) error
+		data []byte
+	}{
+		{"varint", readVarInt, bytes.Repeat([]byte{0xFF}, 5)},
+		{"varlong", readVarLong, bytes.Repeat([]byte{0xFF}, 10)},
+		{"long string", readString, tooLong},
+		{"negative string", readString, negative},
+		{"long byte array", readByteArray, tooLong},
+		{"negative byte array", readByteArray, negative},
+	} {
+		buf := Wrap(c.data)
+		err := c.read(buf)
+		require.Error(t, err, c.name)
+		require.NotErrorIs(t, err, ErrBufferOverflow, c.name)
+		require.Zero(t, buf.Mark(), c.name)
+	}
+
+	// Strings are limited by characters, which can be up to 3 bytes.
+	buf := NewBuffer(0)
+	buf.WriteString("éééé")
+	value, err := buf.ReadString(4)
+	require.NoError(t, err)
+	require.Equal(t, "éééé", value)
+	buf.WriteString("aaaaa")
+	_, err = buf.ReadString(4)
+	require.ErrorContains(t, err, "exceeds maximum")
+}
+
+func TestBuffer_Write(t *testing.T) {
+	// Writing to a wrapped slice does not modify memory beyond it.
+	data := []byte{1, 2, 3, 4}
+	buf := Wrap(data[:2])
+	_ = buf.WriteByte(5)
+	require.Equal(t, []byte{1, 2, 3, 4}, data)
+	require.Equal(t, []byte{1, 2, 5}, buf.Bytes())
+
+	// Types can write to a buffer, and written data is read after unread data.
+	value, err := buf.ReadByte()
+	require.NoError(t, err)
+	require.Equal(t, byte(1), value)
+	require.NoError(t, VarInt.Write(buf, 300))
+	require.Equal(t, []byte{2, 5, 0xAC, 0x02}, buf.Bytes())
+	_, _ = buf.ReadBytes(2)
+	number, err := buf.ReadVarInt()
+	require.NoError(t, err)
+	require.Equal(t, int32(300), number)
+	require.Empty(t, buf.Bytes())
+
+	// Clearing a wrapped buffer does not reuse the wrapped slice.
+	buf = Wrap(data)
+	buf.Clear()
+	_ = buf.WriteByte(9)
+	require.Equal(t, []byte{1, 2, 3, 4}, data)
+	require.Equal(t, []byte{9}, buf.Bytes())
+}
+
+func TestBuffer_Allocs(t *testing.T) {
+	buf := NewBuffer(64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Clear()
+		buf.WriteVarInt(25565)
+		buf.WriteLong(-1)
+		buf.WriteUUID(uuid.Nil)
+		buf.WriteString("kite")
+		_, _ = buf.ReadVarInt()
+		_, _ = buf.ReadLong()
+		_, _ = buf.ReadUUID()
+		_, _ = buf.ReadByteArray(4)
+	})
+	require.Zero(t, allocs)
+}
+
+// benchmarkPacket is the encoding of a common packet, by both Type and Buffer accessors.
+type benchmarkPacket struct {
+	name        string
+	typeWrite   func(w io.Writer) error
+	typeRead    func(r io.Reader) error
+	bufferRead  func(b *Buffer) error
+	bufferWrite func(b *Buffer)
+}
+
+var benchmarkUUIDs = []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+
+var benchmarkPackets = []benchmarkPacket{
+	{
+		name:      "KeepAlive",
+		typeWrite: func(w io.Writer) error { return Long.Write(w, 1700000000000) },
+		typeRead: func(r io.Reader) error {
+			_, err := Long.Read(r)
+			return err
+		},
+		bufferWrite: func(b *Buffer) { b.WriteLong(1700000000000) },
+		bufferRead: func(b *Buffer) error {
+			_, err := b.ReadLong()
+			return err
+		},
+	},
+	{
+		name: "MovePlayerPosRot",
+		typeWrite: func(w io.Writer) error {
+			return Write6(w, Double, 100.5, Double, 64, Double, -200.25, Float, 90, Float, 45, Byte, 1)
+		},
+		typeRead: func(r io.Reader) error {
+			_, _, _, _, _, _, err := Read6(r, Double, Double, Double, Float, Float, Byte)
+			return err
+		},
+		bufferWrite: func(b *Buffer) {
+			b.WriteDouble(100.5)
+			b.WriteDouble(64)
+			b.WriteDouble(-200.25)
+			b.WriteFloat(90)
+			b.WriteFloat(45)
+			_ = b.WriteByte(1)
+		},
+		bufferRead: func(b *Buffer) (err error) {
+			for range 3 {
+				if _, err = b.ReadDouble(); err != nil {
+					return
+				}
+			}
+			for range 2 {
+				if _, err = b.ReadFloat(); err != nil {
+					return
+				}
+			}
+			_, err = b.ReadByte()
+			return
+		},
+	},
+	{
+		name: "PlayerInfoRemove",
+		typeWrite: func(w io.Writer) error {
+			return WriteList(w, benchmarkUUIDs, func(id uuid.UUID) error { return UUID.Write(w, id) })
+		},
+		typeRead: func(r io.Reader) error {
+			_, err := ReadList(r, func() (uuid.UUID, error) { return UUID.Read(r) })
+			return err
+		},
+		bufferWrite: func(b *Buffer) {
+			b.WriteVarInt(int32(len(benchmarkUUIDs)))
+			for _, id := range benchmarkUUIDs {
+				b.WriteUUID(id)
+			}
+		},
+		bufferRead: func(b *Buffer) error {
+			length, err := b.ReadVarInt()
+			for range length {
+				if _, err = b.ReadUUID(); err != nil {
+					return err
+				}
+			}
+			return err
+		},
+	},
+	{
+		name: "SetPlayerTeam",
+		typeWrite: func(w io.Writer) error {
+			return Write5(w, String, "red", Byte, 3, String, "always", String, "never", VarInt, 12)
+		},
+		typeRead: func(r io.Reader) error {
+			_, _, _, _, _, err := Read5(r, String, Byte, String, String, VarInt)
+			return err
+		},
+		bufferWrite: func(b *Buffer) {
+			b.WriteString("red")
+			_ = b.WriteByte(3)
+			b.WriteString("always")
+			b.WriteString("never")
+			b.WriteVarInt(12)
+		},
+		bufferRead: func(b *Buffer) (err error) {
+			if _, err = b.ReadString(16); err != nil {
+				return
+			}
+			if _, err = b.ReadByte(); err != nil {
+				return
+			}
+			for range 2 {
+				if _, err = b.ReadString(40); err != nil {
+					return
+				}
+			}
+			_, err = b.ReadVarInt()
+			return
+		},
+	},
+}
+
+func BenchmarkBuffer(b *testing.B) {
+	for _, pkt := range benchmarkPackets {
+		var encoded bytebufferpool.ByteBuffer
+		require.NoError(b, pkt.typeWrite(&encoded))
+		data := encoded.B
+
+		b.Run(pkt.name+"/Type/Write", func(b *testing.B) {
+			var buf bytebufferpool.ByteBuffer
+			b.ReportAllocs()
+			for range b.N {
+				buf.Reset()
+				_ = pkt.typeWrite(&buf)
+			}
+		})
+		b.Run(pkt.name+"/Type/Read", func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				_ = pkt.typeRead(Wrap(data))
+			}
+		})
+		b.Run(pkt.name+"/Buffer/Write", func(b *testing.B) {
+			buf := NewBuffer(len(data))
+			b.ReportAllocs()
+			for range b.N {
+				buf.Clear()
+				pkt.bufferWrite(buf)
+			}
+		})
+		b.Run(pkt.name+"/Buffer/Read", func(b *testing.B) {
+			buf := Wrap(data)
+			b.ReportAllocs()
+			for range b.N {
+				buf.Reset(0)
+				_ = pkt.bufferRead(buf)
+			}
+		})
+	}
+}
+
+func TestBenchmarkPackets(t *testing.T) {
+	for _, pkt := range benchmarkPackets {
+		buf := NewBuffer(0)
+		pkt.bufferWrite(buf)
+		var expected bytes.Buffer
+		require.NoError(t, pkt.typeWrite(&expected))
+		require.Equal(t, expected.Bytes(), buf.Bytes(), pkt.name)
+		require.NoError(t, pkt.bufferRead(buf), pkt.name)
+		require.Zero(t, buf.Remaining(), pkt.name)
+	}
+}
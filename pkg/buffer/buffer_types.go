@@ -0,0 +1,223 @@
+package buffer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// The typed accessors of Buffer, matching the Type of the same name.
+
+// next returns the next n bytes and advances past them.
+func (b *Buffer) next(n int) ([]byte, error) {
+	if n < 0 || n > b.Remaining() {
+		return nil, ErrBufferOverflow
+	}
+	p := b.delegate[b.position : b.position+n]
+	b.position += n
+	return p, nil
+}
+
+// ReadBytes reads n bytes. The result aliases the buffer, so it must be copied to be kept after the buffer
+// is reused.
+func (b *Buffer) ReadBytes(n int) ([]byte, error) {
+	return b.next(n)
+}
+
+func (b *Buffer) ReadByte() (byte, error) {
+	p, err := b.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return p[0], nil
+}
+func (b *Buffer) WriteByte(v byte) error {
+	b.delegate = append(b.delegate, v)
+	b.limit = len(b.delegate)
+	return nil
+}
+
+func (b *Buffer) ReadBool() (bool, error) {
+	v, err := b.ReadByte()
+	return v != 0, err
+}
+func (b *Buffer) WriteBool(v bool) {
+	if v {
+		_ = b.WriteByte(1)
+	} else {
+		_ = b.WriteByte(0)
+	}
+}
+
+func (b *Buffer) ReadShort() (int16, error) {
+	v, err := b.ReadUint16()
+	return int16(v), err
+}
+func (b *Buffer) WriteShort(v int16) {
+	b.WriteUint16(uint16(v))
+}
+
+func (b *Buffer) ReadUint16() (uint16, error) {
+	p, err := b.next(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(p), nil
+}
+func (b *Buffer) WriteUint16(v uint16) {
+	b.delegate = binary.BigEndian.AppendUint16(b.delegate, v)
+	b.limit = len(b.delegate)
+}
+
+func (b *Buffer) ReadInt() (int32, error) {
+	p, err := b.next(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(p)), nil
+}
+func (b *Buffer) WriteInt(v int32) {
+	b.delegate = binary.BigEndian.AppendUint32(b.delegate, uint32(v))
+	b.limit = len(b.delegate)
+}
+
+func (b *Buffer) ReadLong() (int64, error) {
+	p, err := b.next(8)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(p)), nil
+}
+func (b *Buffer) WriteLong(v int64) {
+	b.delegate = binary.BigEndian.AppendUint64(b.delegate, uint64(v))
+	b.limit = len(b.delegate)
+}
+
+func (b *Buffer) ReadVarInt() (int32, error) {
+	v, err := b.readVarNumber(5)
+	return int32(v), err
+}
+func (b *Buffer) WriteVarInt(v int32) {
+	b.delegate = binary.AppendUvarint(b.delegate, uint64(uint32(v)))
+	b.limit = len(b.delegate)
+}
+
+func (b *Buffer) ReadVarLong() (int64, error) {
+	v, err := b.readVarNumber(10)
+	return int64(v), err
+}
+func (b *Buffer) WriteVarLong(v int64) {
+	b.delegate = binary.AppendUvarint(b.delegate, uint64(v))
+	b.limit = len(b.delegate)
+}
+
+// readVarNumber reads a VarInt or VarLong of at most maxBytes bytes.
+func (b *Buffer) readVarNumber(maxBytes int) (uint64, error) {
+	var value uint64
+	for i := range maxBytes {
+		if b.position+i >= b.limit {
+			return 0, ErrBufferOverflow
+		}
+		current := b.delegate[b.position+i]
+		value |= uint64(current&0x7F) << (7 * i)
+		if current&varIntContinueBit == 0 {
+			b.position += i + 1
+			return value, nil
+		}
+	}
+	if maxBytes == 5 {
+		return 0, fmt.Errorf("VarInt is too big")
+	}
+	return 0, fmt.Errorf("VarLong is too big")
+}
+
+func (b *Buffer) ReadFloat() (float32, error) {
+	v, err := b.ReadInt()
+	return math.Float32frombits(uint32(v)), err
+}
+func (b *Buffer) WriteFloat(v float32) {
+	b.WriteInt(int32(math.Float32bits(v)))
+}
+
+func (b *Buffer) ReadDouble() (float64, error) {
+	v, err := b.ReadLong()
+	return math.Float64frombits(uint64(v)), err
+}
+func (b *Buffer) WriteDouble(v float64) {
+	b.WriteLong(int64(math.Float64bits(v)))
+}
+
+func (b *Buffer) ReadUUID() (v uuid.UUID, err error) {
+	p, err := b.next(16)
+	if err != nil {
+		return v, err
+	}
+	return uuid.UUID(p), nil
+}
+func (b *Buffer) WriteUUID(v uuid.UUID) {
+	_, _ = b.Write(v[:])
+}
+
+func (b *Buffer) ReadPosition() (BlockPos, error) {
+	v, err := b.ReadLong()
+	if err != nil {
+		return BlockPos{}, err
+	}
+	return BlockPos{X: int32(v >> 38), Y: int32(v << 52 >> 52), Z: int32(v << 26 >> 38)}, nil
+}
+func (b *Buffer) WritePosition(v BlockPos) {
+	b.WriteLong(int64(v.X&0x3FFFFFF)<<38 | int64(v.Z&0x3FFFFFF)<<12 | int64(v.Y&0xFFF))
+}
+
+// ReadString reads a string of at most maxLength characters, like LimitedString.
+func (b *Buffer) ReadString(maxLength int) (string, error) {
+	mark := b.position
+	length, err := b.ReadVarInt()
+	if err != nil {
+		return "", err
+	}
+	// A character is at most 3 bytes of UTF-8 (4 for a surrogate pair, which is 2 characters).
+	if length < 0 || int(length) > maxLength*3 {
+		b.position = mark
+		return "", fmt.Errorf("string length %d is invalid or exceeds maximum %d", length, maxLength*3)
+	}
+	p, err := b.next(int(length))
+	if err == nil {
+		err = checkStringLength(p, maxLength)
+	}
+	if err != nil {
+		b.position = mark
+		return "", err
+	}
+	return string(p), nil
+}
+func (b *Buffer) WriteString(v string) {
+	b.WriteVarInt(int32(len(v)))
+	b.delegate = append(b.delegate, v...)
+	b.limit = len(b.delegate)
+}
+
+// ReadByteArray reads a length prefixed byte array of at most maxLength bytes. Like ReadBytes, the result
+// aliases the buffer.
+func (b *Buffer) ReadByteArray(maxLength int) ([]byte, error) {
+	mark := b.position
+	length, err := b.ReadVarInt()
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 || int(length) > maxLength {
+		b.position = mark
+		return nil, fmt.Errorf("byte array length %d is invalid or exceeds maximum %d", length, maxLength)
+	}
+	p, err := b.next(int(length))
+	if err != nil {
+		b.position = mark
+	}
+	return p, err
+}
+func (b *Buffer) WriteByteArray(v []byte) {
+	b.WriteVarInt(int32(len(v)))
+	_, _ = b.Write(v)
+}
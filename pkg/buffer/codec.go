@@ -0,0 +1,513 @@
+package buffer
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/nbt"
+	"github.com/mworzala/kite/pkg/text"
+)
+
+// ReadStruct reads the exported fields of the struct pointed to by v, in order, using the encoding given
+// by their mc struct tag (or the default for their Go type if untagged). The tag is a comma separated list
+// of modifiers followed by the encoding of the value:
+//
+//	opt        A bool prefixed optional value. Pointers and slices are absent when nil, other values when zero.
+//	list       A VarInt length prefixed list.
+//
+//	bool, byte, short, uint16, int, varint, long, varlong, float, double, string, identifier, bytearray,
+//	rawbytes, uuid, position, text, textjson, nbt (network NBT, as an nbt.Tag or raw bytes), enum (a VarInt
+//	checked by the Validate method of the field type), struct (a nested struct, or a type implementing Read
+//	and Write).
+//
+// For example `mc:"list,opt,string"`. Fields tagged `mc:"-"` are skipped. Untagged pointers are optional,
+// slices are lists (except []byte which is a byte array), int32 is a VarInt and integer types with a
+// Validate method are enums.
+//
+// The encoding of each struct type is computed once and cached.
+func ReadStruct(r io.Reader, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ReadStruct: expected a pointer to a struct, got %T", v)
+	}
+	plan, err := planFor(rv.Type().Elem())
+	if err != nil {
+		return err
+	}
+	return plan.read(r, rv.Elem())
+}
+
+// WriteStruct writes the struct (or pointer to a struct) v as described by ReadStruct.
+func WriteStruct(w io.Writer, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("WriteStruct: expected a struct, got %T", v)
+	}
+	plan, err := planFor(rv.Type())
+	if err != nil {
+		return err
+	}
+	return plan.write(w, rv)
+}
+
+// A codec reads or writes a single value. read is always given an addressable value.
+type codec struct {
+	read  func(r io.Reader, v reflect.Value) error
+	write func(w io.Writer, v reflect.Value) error
+}
+
+type fieldPlan struct {
+	name  string
+	index int
+	codec
+}
+
+type structPlan struct {
+	typ    reflect.Type
+	fields []fieldPlan
+}
+
+func (p *structPlan) read(r io.Reader, v reflect.Value) error {
+	for i := range p.fields {
+		f := &p.fields[i]
+		if err := f.read(r, v.Field(f.index)); err != nil {
+			return fmt.Errorf("%s.%s: %w", p.typ.Name(), f.name, err)
+		}
+	}
+	return nil
+}
+
+func (p *structPlan) write(w io.Writer, v reflect.Value) error {
+	for i := range p.fields {
+		f := &p.fields[i]
+		if err := f.write(w, v.Field(f.index)); err != nil {
+			return fmt.Errorf("%s.%s: %w", p.typ.Name(), f.name, err)
+		}
+	}
+	return nil
+}
+
+var plans sync.Map // reflect.Type -> *structPlan
+
+func planFor(t reflect.Type) (*structPlan, error) {
+	if plan, ok := plans.Load(t); ok {
+		return plan.(*structPlan), nil
+	}
+	plan := &structPlan{typ: t}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup("mc")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		var spec []string
+		if tagged {
+			spec = strings.Split(tag, ",")
+		}
+		c, err := codecFor(field.Type, spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		plan.fields = append(plan.fields, fieldPlan{name: field.Name, index: i, codec: c})
+	}
+	actual, _ := plans.LoadOrStore(t, plan)
+	return actual.(*structPlan), nil
+}
+
+var (
+	uuidGoType      = reflect.TypeOf(uuid.UUID{})
+	blockPosGoType  = reflect.TypeOf(BlockPos{})
+	componentGoType = reflect.TypeOf((*text.Component)(nil)).Elem()
+	tagGoType       = reflect.TypeOf((*nbt.Tag)(nil)).Elem()
+	validatorGoType = reflect.TypeOf((*interface{ Validate() bool })(nil)).Elem()
+	structGoType    = reflect.TypeOf((*interface {
+		Read(r io.Reader) error
+		Write(w io.Writer) error
+	})(nil)).Elem()
+)
+
+// codecFor returns the codec for values of type t, encoded as spec (or the default for t if spec is empty).
+func codecFor(t reflect.Type, spec []string) (codec, error) {
+	if len(spec) == 0 {
+		spec = defaultSpec(t)
+		if spec == nil {
+			return codec{}, fmt.Errorf("no default encoding for %s, add an mc tag", t)
+		}
+	}
+	switch spec[0] {
+	case "opt":
+		return optCodec(t, spec[1:])
+	case "list":
+		if t.Kind() != reflect.Slice {
+			return codec{}, fmt.Errorf("list encoding requires a slice, not %s", t)
+		}
+		return listCodec(t, spec[1:])
+	}
+	if len(spec) > 1 {
+		return codec{}, fmt.Errorf("unexpected %q after %q", strings.Join(spec[1:], ","), spec[0])
+	}
+	return baseCodec(t, spec[0])
+}
+
+func defaultSpec(t reflect.Type) []string {
+	switch {
+	case t == uuidGoType:
+		return []string{"uuid"}
+	case t == blockPosGoType:
+		return []string{"position"}
+	case t == componentGoType:
+		return []string{"text"}
+	case t == tagGoType:
+		return []string{"nbt"}
+	case reflect.PointerTo(t).Implements(structGoType):
+		return []string{"struct"}
+	case isInteger(t.Kind()) && reflect.PointerTo(t).Implements(validatorGoType):
+		return []string{"enum"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return []string{"bool"}
+	case reflect.Uint8, reflect.Int8:
+		return []string{"byte"}
+	case reflect.Int16:
+		return []string{"short"}
+	case reflect.Uint16:
+		return []string{"uint16"}
+	case reflect.Int32:
+		return []string{"varint"}
+	case reflect.Int64:
+		return []string{"long"}
+	case reflect.Float32:
+		return []string{"float"}
+	case reflect.Float64:
+		return []string{"double"}
+	case reflect.String:
+		return []string{"string"}
+	case reflect.Struct:
+		return []string{"struct"}
+	case reflect.Pointer:
+		return []string{"opt"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return []string{"bytearray"}
+		}
+		return []string{"list"}
+	}
+	return nil
+}
+
+func optCodec(t reflect.Type, spec []string) (codec, error) {
+	if t.Kind() == reflect.Pointer {
+		elem, err := codecFor(t.Elem(), spec)
+		if err != nil {
+			return codec{}, err
+		}
+		return codec{
+			read: func(r io.Reader, v reflect.Value) error {
+				present, err := Bool.Read(r)
+				if err != nil || !present {
+					return err
+				}
+				v.Set(reflect.New(t.Elem()))
+				return elem.read(r, v.Elem())
+			},
+			write: func(w io.Writer, v reflect.Value) error {
+				if err := Bool.Write(w, !v.IsNil()); err != nil || v.IsNil() {
+					return err
+				}
+				return elem.write(w, v.Elem())
+			},
+		}, nil
+	}
+
+	value, err := codecFor(t, spec)
+	if err != nil {
+		return codec{}, err
+	}
+	absent := reflect.Value.IsZero
+	if t.Kind() == reflect.Slice {
+		absent = reflect.Value.IsNil
+	}
+	return codec{
+		read: func(r io.Reader, v reflect.Value) error {
+			present, err := Bool.Read(r)
+			if err != nil || !present {
+				return err
+			}
+			return value.read(r, v)
+		},
+		write: func(w io.Writer, v reflect.Value) error {
+			isAbsent := absent(v)
+			if err := Bool.Write(w, !isAbsent); err != nil || isAbsent {
+				return err
+			}
+			return value.write(w, v)
+		},
+	}, nil
+}
+
+func listCodec(t reflect.Type, spec []string) (codec, error) {
+	elem, err := codecFor(t.Elem(), spec)
+	if err != nil {
+		return codec{}, err
+	}
+	return codec{
+		read: func(r io.Reader, v reflect.Value) error {
+			length, err := ReadListLength(r, MaxLength)
+			if err != nil {
+				return err
+			}
+			n := Prealloc(r, length)
+			list := reflect.MakeSlice(t, n, n)
+			for i := range length {
+				if i == list.Len() {
+					// More elements than preallocated, grow by doubling up to the length.
+					grow := min(length-i, max(i, 1))
+					list = reflect.AppendSlice(list, reflect.MakeSlice(t, grow, grow))
+				}
+				if err = elem.read(r, list.Index(i)); err != nil {
+					return err
+				}
+			}
+			v.Set(list)
+			return nil
+		},
+		write: func(w io.Writer, v reflect.Value) error {
+			if err := VarInt.Write(w, int32(v.Len())); err != nil {
+				return err
+			}
+			for i := range v.Len() {
+				if err := elem.write(w, v.Index(i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+func baseCodec(t reflect.Type, name string) (codec, error) {
+	kind := t.Kind()
+	switch name {
+	case "bool":
+		if kind == reflect.Bool {
+			return codec{
+				read: func(r io.Reader, v reflect.Value) error {
+					value, err := Bool.Read(r)
+					v.SetBool(value)
+					return err
+				},
+				write: func(w io.Writer, v reflect.Value) error {
+					return Bool.Write(w, v.Bool())
+				},
+			}, nil
+		}
+	case "byte":
+		return integerCodec(t, Byte)
+	case "short":
+		return integerCodec(t, Short)
+	case "uint16":
+		return integerCodec(t, Uint16)
+	case "int":
+		return integerCodec(t, Int)
+	case "varint":
+		return integerCodec(t, VarInt)
+	case "long":
+		return integerCodec(t, Long)
+	case "varlong":
+		return integerCodec(t, VarLong)
+	case "enum":
+		if isInteger(kind) && reflect.PointerTo(t).Implements(validatorGoType) {
+			c, _ := integerCodec(t, VarInt)
+			read := c.read
+			c.read = func(r io.Reader, v reflect.Value) error {
+				if err := read(r, v); err != nil {
+					return err
+				}
+				if !v.Addr().Interface().(interface{ Validate() bool }).Validate() {
+					return fmt.Errorf("invalid enum value for %s: %v", t, v)
+				}
+				return nil
+			}
+			return c, nil
+		}
+	case "float":
+		if kind == reflect.Float32 {
+			return codec{
+				read: func(r io.Reader, v reflect.Value) error {
+					value, err := Float.Read(r)
+					v.SetFloat(float64(value))
+					return err
+				},
+				write: func(w io.Writer, v reflect.Value) error {
+					return Float.Write(w, float32(v.Float()))
+				},
+			}, nil
+		}
+	case "double":
+		if kind == reflect.Float64 {
+			return codec{
+				read: func(r io.Reader, v reflect.Value) error {
+					value, err := Double.Read(r)
+					v.SetFloat(value)
+					return err
+				},
+				write: func(w io.Writer, v reflect.Value) error {
+					return Double.Write(w, v.Float())
+				},
+			}, nil
+		}
+	case "string":
+		return stringCodec(t, String)
+	case "identifier":
+		return stringCodec(t, Identifier)
+	case "bytearray":
+		return bytesCodec(t, ByteArray)
+	case "rawbytes":
+		return bytesCodec(t, RawBytes)
+	case "nbt":
+		if t == tagGoType {
+			return typedCodec(t, NBT)
+		}
+		return bytesCodec(t, RawNBT)
+	case "uuid":
+		return typedCodec(t, UUID)
+	case "position":
+		return typedCodec(t, Position)
+	case "text":
+		return typedCodec(t, TextComponent)
+	case "textjson":
+		return typedCodec(t, TextComponentJSON)
+	case "struct":
+		if reflect.PointerTo(t).Implements(structGoType) {
+			return codec{
+				read: func(r io.Reader, v reflect.Value) error {
+					return v.Addr().Interface().(interface{ Read(io.Reader) error }).Read(r)
+				},
+				write: func(w io.Writer, v reflect.Value) error {
+					if v.CanAddr() {
+						return v.Addr().Interface().(interface{ Write(io.Writer) error }).Write(w)
+					}
+					ptr := reflect.New(t)
+					ptr.Elem().Set(v)
+					return ptr.Interface().(interface{ Write(io.Writer) error }).Write(w)
+				},
+			}, nil
+		}
+		if kind == reflect.Struct {
+			// Nested plans are looked up when used, so that recursive types do not recurse here.
+			return codec{
+				read: func(r io.Reader, v reflect.Value) error {
+					plan, err := planFor(t)
+					if err != nil {
+						return err
+					}
+					return plan.read(r, v)
+				},
+				write: func(w io.Writer, v reflect.Value) error {
+					plan, err := planFor(t)
+					if err != nil {
+						return err
+					}
+					return plan.write(w, v)
+				},
+			}, nil
+		}
+	default:
+		return codec{}, fmt.Errorf("unknown encoding %q", name)
+	}
+	return codec{}, fmt.Errorf("%s cannot be encoded as %s", t, name)
+}
+
+func isInteger(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Uint64
+}
+
+// integerCodec encodes any integer type with an integer Type, converting (and possibly truncating) it. For
+// example an int8 field encoded as a byte is signed.
+func integerCodec[T byte | int16 | uint16 | int32 | int64](t reflect.Type, typ Type[T]) (codec, error) {
+	switch {
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		return codec{
+			read: func(r io.Reader, v reflect.Value) error {
+				value, err := typ.Read(r)
+				v.SetInt(int64(value))
+				return err
+			},
+			write: func(w io.Writer, v reflect.Value) error {
+				return typ.Write(w, T(v.Int()))
+			},
+		}, nil
+	case t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uint64:
+		return codec{
+			read: func(r io.Reader, v reflect.Value) error {
+				value, err := typ.Read(r)
+				v.SetUint(uint64(value))
+				return err
+			},
+			write: func(w io.Writer, v reflect.Value) error {
+				return typ.Write(w, T(v.Uint()))
+			},
+		}, nil
+	}
+	return codec{}, fmt.Errorf("%s is not an integer", t)
+}
+
+func stringCodec(t reflect.Type, typ Type[string]) (codec, error) {
+	if t.Kind() != reflect.String {
+		return codec{}, fmt.Errorf("%s is not a string", t)
+	}
+	return codec{
+		read: func(r io.Reader, v reflect.Value) error {
+			value, err := typ.Read(r)
+			v.SetString(value)
+			return err
+		},
+		write: func(w io.Writer, v reflect.Value) error {
+			return typ.Write(w, v.String())
+		},
+	}, nil
+}
+
+func bytesCodec(t reflect.Type, typ Type[[]byte]) (codec, error) {
+	if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uint8 {
+		return codec{}, fmt.Errorf("%s is not a byte slice", t)
+	}
+	return codec{
+		read: func(r io.Reader, v reflect.Value) error {
+			value, err := typ.Read(r)
+			v.SetBytes(value)
+			return err
+		},
+		write: func(w io.Writer, v reflect.Value) error {
+			return typ.Write(w, v.Bytes())
+		},
+	}, nil
+}
+
+// typedCodec encodes values of exactly type T.
+func typedCodec[T any](t reflect.Type, typ Type[T]) (codec, error) {
+	if expected := reflect.TypeOf((*T)(nil)).Elem(); t != expected {
+		return codec{}, fmt.Errorf("%s cannot be encoded as %s", t, expected)
+	}
+	return codec{
+		read: func(r io.Reader, v reflect.Value) (err error) {
+			*v.Addr().Interface().(*T), err = typ.Read(r)
+			return
+		},
+		write: func(w io.Writer, v reflect.Value) error {
+			if v.CanAddr() {
+				return typ.Write(w, *v.Addr().Interface().(*T))
+			}
+			value, _ := v.Interface().(T) // Nil interfaces are not T
+			return typ.Write(w, value)
+		},
+	}, nil
+}
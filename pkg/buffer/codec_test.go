@@ -0,0 +1,193 @@
+package buffer
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/nbt"
+	"github.com/mworzala/kite/pkg/text"
+	"github.com/stretchr/testify/require"
+)
+
+type testMode int
+
+func (m testMode) Validate() bool { return m >= 0 && m <= 2 }
+
+type testProperty struct {
+	Name      string
+	Signature string `mc:"opt"`
+}
+
+type testReadWriter struct {
+	Value string
+}
+
+func (rw *testReadWriter) Read(r io.Reader) (err error) {
+	rw.Value, err = String.Read(r)
+	return
+}
+func (rw *testReadWriter) Write(w io.Writer) error {
+	return String.Write(w, rw.Value)
+}
+
+type testStruct struct {
+	ID         int32 `mc:"int"`
+	Count      int32
+	Time       int64
+	Health     float32
+	Flags      byte
+	Delta      int8
+	Port       uint16
+	Enabled    bool
+	Name       string
+	UUID       uuid.UUID
+	Position   BlockPos
+	Legacy     text.Component `mc:"textjson"`
+	Mode       testMode
+	Data       []byte
+	Properties []testProperty
+	Entries    []int32  `mc:"list,int"`
+	Aliases    []string `mc:"list,opt,string"`
+	Parent     *testProperty
+	Custom     testReadWriter
+	Skipped    string `mc:"-"`
+	unexported string
+	Offset     int16
+	Speed      float64
+	Seed       int64  `mc:"varlong"`
+	Dimension  string `mc:"identifier"`
+	BlockData  nbt.Tag
+	Rest       []byte `mc:"rawbytes"`
+}
+
+func TestStruct(t *testing.T) {
+	value := testStruct{
+		ID: 1, Count: 300, Time: -5, Health: 0.5, Flags: 0xF0, Delta: -1, Port: 25565, Enabled: true,
+		Name: "kite", UUID: uuid.New(), Position: BlockPos{X: -1, Y: 64, Z: 1},
+		Legacy: &text.Text{Text: "legacy"}, Mode: 2, Data: []byte{1, 2},
+		Properties: []testProperty{{Name: "a", Signature: "sig"}, {Name: "b"}},
+		Entries:    []int32{7, 8}, Aliases: []string{"x", "", "y"},
+		Parent: &testProperty{Name: "parent"}, Custom: testReadWriter{"custom"},
+		Offset: -2, Speed: 0.25, Seed: 1 << 40, Dimension: "minecraft:overworld",
+		BlockData: nbt.Compound{"a": nbt.List{nbt.Int(1)}},
+		Rest:      []byte{9, 9, 9},
+	}
+
+	var expected bytes.Buffer
+	require.NoError(t, Write10(&expected, Int, value.ID, VarInt, value.Count, Long, value.Time, Float, value.Health,
+		Byte, value.Flags, Byte, 0xFF, Uint16, value.Port, Bool, value.Enabled, String, value.Name, UUID, value.UUID))
+	require.NoError(t, Write4(&expected, Position, value.Position, TextComponentJSON, value.Legacy, VarInt, 2, ByteArray, value.Data))
+	require.NoError(t, WriteList(&expected, value.Properties, func(p testProperty) error {
+		return Write2(&expected, String, p.Name, Opt(String), p.Signature)
+	}))
+	require.NoError(t, Write6(&expected, List(Int), value.Entries, List(Opt(String)), value.Aliases,
+		Bool, true, String, "parent", Bool, false, String, "custom"))
+	require.NoError(t, Write4(&expected, Short, value.Offset, Double, value.Speed, VarLong, value.Seed,
+		Identifier, value.Dimension))
+	require.NoError(t, NBT.Write(&expected, value.BlockData))
+	require.NoError(t, RawBytes.Write(&expected, value.Rest))
+
+	var actual bytes.Buffer
+	require.NoError(t, WriteStruct(&actual, &value))
+	require.Equal(t, expected.Bytes(), actual.Bytes())
+
+	var read testStruct
+	require.NoError(t, ReadStruct(Wrap(actual.Bytes()), &read))
+	require.Equal(t, value, read)
+
+	// Writing a value rather than a pointer is the same.
+	actual.Reset()
+	require.NoError(t, WriteStruct(&actual, value))
+	require.Equal(t, expected.Bytes(), actual.Bytes())
+}
+
+func TestStruct_Optional(t *testing.T) {
+	type optional struct {
+		Text  text.Component `mc:"opt,text"`
+		Bytes []byte         `mc:"opt"`
+		Count int32          `mc:"opt,varint"`
+		Next  *optional
+	}
+	value := optional{Bytes: []byte{}, Next: &optional{Count: 3}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteStruct(&buf, &value))
+	require.Equal(t, []byte{0, 1, 0, 0, 1, 0, 0, 1, 3, 0}, buf.Bytes())
+
+	var read optional
+	require.NoError(t, ReadStruct(Wrap(buf.Bytes()), &read))
+	require.Equal(t, value, read)
+
+	value = optional{Text: &text.Text{Text: "text"}}
+	buf.Reset()
+	require.NoError(t, WriteStruct(&buf, &value))
+	read = optional{}
+	require.NoError(t, ReadStruct(Wrap(buf.Bytes()), &read))
+	require.Equal(t, value, read)
+}
+
+func TestStruct_LongList(t *testing.T) {
+	type list struct {
+		Values []int32
+	}
+	value := list{Values: make([]int32, 3*maxPrealloc+1)}
+	for i := range value.Values {
+		value.Values[i] = int32(i)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteStruct(&buf, &value))
+
+	// Lists longer than preallocated are read from both a Buffer and another reader.
+	for _, r := range []io.Reader{Wrap(buf.Bytes()), bytes.NewReader(buf.Bytes())} {
+		var read list
+		require.NoError(t, ReadStruct(r, &read))
+		require.Equal(t, value, read)
+	}
+}
+
+func TestStruct_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	require.ErrorContains(t, WriteStruct(&buf, &struct{ Value int }{}), "no default encoding for int")
+	require.ErrorContains(t, WriteStruct(&buf, &struct {
+		Value string `mc:"varint"`
+	}{}), "string is not an integer")
+	require.ErrorContains(t, WriteStruct(&buf, &struct {
+		Value string `mc:"list,string"`
+	}{}), "list encoding requires a slice")
+	require.ErrorContains(t, WriteStruct(&buf, &struct {
+		Value int32 `mc:"varshort"`
+	}{}), `unknown encoding "varshort"`)
+	require.ErrorContains(t, WriteStruct(&buf, &struct {
+		Value int32 `mc:"varint,string"`
+	}{}), "unexpected")
+	require.Error(t, WriteStruct(&buf, 1))
+	require.Error(t, ReadStruct(Wrap(nil), testStruct{}))
+
+	// Invalid enum values and truncated input are reported with the field.
+	var mode struct{ Mode testMode }
+	require.ErrorContains(t, ReadStruct(Wrap([]byte{3}), &mode), "Mode: invalid enum value")
+	var property testProperty
+	require.ErrorContains(t, ReadStruct(Wrap([]byte{1, 'a', 1}), &property), "testProperty.Signature")
+}
+
+func BenchmarkStruct(b *testing.B) {
+	value := testProperty{Name: "textures", Signature: "signature"}
+	var buf bytes.Buffer
+	b.Run("Write", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			buf.Reset()
+			_ = WriteStruct(&buf, &value)
+		}
+	})
+	b.Run("Read", func(b *testing.B) {
+		data := buf.Bytes()
+		b.ReportAllocs()
+		for range b.N {
+			_ = ReadStruct(Wrap(data), &value)
+		}
+	})
+}
@@ -1,9 +1,12 @@
 package buffer
 
 import (
+	"fmt"
 	"io"
+	"math"
 
 	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/nbt"
 	"github.com/mworzala/kite/pkg/text"
 	"golang.org/x/exp/constraints"
 )
@@ -13,23 +16,101 @@ type Type[T any] interface {
 	Write(w io.Writer, v T) error
 }
 
+// Length limits matching the game. Byte arrays and lists are only limited by the data available, unless read
+// with LimitedByteArray or LimitedList.
+const (
+	MaxLength           = math.MaxInt32 // The largest length prefix
+	MaxStringLength     = 32767         // Characters in a String
+	MaxTextJSONLength   = 262144        // Characters in a TextComponentJSON
+	MaxIdentifierLength = 32767         // Characters in an Identifier
+)
+
 var (
-	Byte      Type[byte]      = byteType{}
-	Bool      Type[bool]      = boolType{}
-	Uint16    Type[uint16]    = uShortType{}
-	VarInt    Type[int32]     = varIntType{}
-	Long      Type[int64]     = longType{}
-	UUID      Type[uuid.UUID] = uuidType{}
-	String    Type[string]    = stringType{}
-	ByteArray Type[[]byte]    = byteArrayType{}
-	RawBytes  Type[[]byte]    = rawBytesType{}
+	Byte       Type[byte]      = byteType{}
+	SignedByte Type[int8]      = signedByteType{}
+	Bool       Type[bool]      = boolType{}
+	Short      Type[int16]     = shortType{}
+	Uint16     Type[uint16]    = uShortType{}
+	Int        Type[int32]     = intType{}
+	VarInt     Type[int32]     = varIntType{}
+	Long       Type[int64]     = longType{}
+	VarLong    Type[int64]     = varLongType{}
+	Float      Type[float32]   = floatType{}
+	Double     Type[float64]   = doubleType{}
+	UUID       Type[uuid.UUID] = uuidType{}
+	String     Type[string]    = limitedStringType{MaxStringLength}
+	ByteArray  Type[[]byte]    = byteArrayType{MaxLength}
+	RawBytes   Type[[]byte]    = rawBytesType{}
+	Position   Type[BlockPos]  = positionType{}
+
+	// Angle is a rotation in degrees, encoded as a byte in steps of 1/256 of a full turn. Angles are read
+	// in the range [0, 360).
+	Angle Type[float32] = angleType{}
+
+	// BitSet is a VarInt length prefixed list of longs, each holding 64 bits starting from the lowest.
+	BitSet Type[Bits] = bitSetType{}
+
+	// Identifier is a namespaced key such as minecraft:stone, where the namespace is optional and defaults to
+	// minecraft. Identifiers with characters not allowed by the game are rejected when read or written.
+	Identifier Type[string] = identifierType{}
+
+	// NBT is a single network format NBT tag. An end tag (meaning no value) is nil.
+	NBT Type[nbt.Tag] = nbtType{}
+	// RawNBT is a single network format NBT tag, kept as its encoded bytes (including the tag type). It is
+	// skipped over rather than decoded when read, so is cheaper than NBT for forwarding.
+	RawNBT Type[[]byte] = rawNBTType{}
 
 	TextComponent     Type[text.Component] = textComponentType{}
 	TextComponentJSON Type[text.Component] = textComponentJSONType{}
 )
 
+// A BlockPos is the position of a block, encoded by Position as a single long.
+type BlockPos struct {
+	X, Y, Z int32
+}
+
+// Bits is a set of bits, where bit i is stored in Bits[i/64] at position i%64.
+type Bits []uint64
+
+// Get returns whether bit i is set.
+func (b Bits) Get(i int) bool {
+	return i/64 < len(b) && b[i/64]&(1<<(i%64)) != 0
+}
+
+// Set sets or clears bit i, growing the set if needed.
+func (b *Bits) Set(i int, value bool) {
+	for i/64 >= len(*b) {
+		if !value {
+			return
+		}
+		*b = append(*b, 0)
+	}
+	if value {
+		(*b)[i/64] |= 1 << (i % 64)
+	} else {
+		(*b)[i/64] &^= 1 << (i % 64)
+	}
+}
+
 // Complex types
 
+// FixedBitSet is a set of size bits encoded as ceil(size/8) bytes, without a length prefix. Writing a set
+// with bits at or past size is an error.
+func FixedBitSet(size int) Type[Bits] {
+	return fixedBitSetType{size}
+}
+
+// LimitedString is a String of at most maxLength characters (counted as UTF-16 code units, like the game).
+// Longer strings are rejected when read or written.
+func LimitedString(maxLength int) Type[string] {
+	return limitedStringType{maxLength}
+}
+
+// LimitedByteArray is a ByteArray of at most maxLength bytes.
+func LimitedByteArray(maxLength int) Type[[]byte] {
+	return byteArrayType{maxLength}
+}
+
 func Opt[T comparable](t Type[T]) Type[T] {
 	return optType[T]{t}
 }
@@ -51,23 +132,83 @@ func Struct[T interface {
 }
 
 func List[T any](t Type[T]) Type[[]T] {
-	return listType[T]{t}
+	return listType[T]{t, MaxLength}
+}
+
+// LimitedList is a List of at most maxLength elements.
+func LimitedList[T any](t Type[T], maxLength int) Type[[]T] {
+	return listType[T]{t, maxLength}
 }
 
 func ReadList[T any](r io.Reader, f func() (T, error)) (result []T, err error) {
-	length, err := VarInt.Read(r)
+	length, err := ReadListLength(r, MaxLength)
 	if err != nil {
 		return
 	}
-	result = make([]T, length)
-	for i := range result {
-		if result[i], err = f(); err != nil {
-			return
+	result = make([]T, 0, Prealloc(r, length))
+	for range length {
+		value, err := f()
+		if err != nil {
+			return nil, err
 		}
+		result = append(result, value)
 	}
 	return
 }
 
+// ReadLength reads a VarInt length prefix, rejecting negative lengths and lengths over maxLength.
+func ReadLength(r io.Reader, maxLength int) (int, error) {
+	length, err := VarInt.Read(r)
+	if err != nil {
+		return 0, err
+	}
+	if length < 0 || int(length) > maxLength {
+		return 0, fmt.Errorf("length %d is invalid or exceeds maximum %d", length, maxLength)
+	}
+	return int(length), nil
+}
+
+// ReadListLength reads the length prefix of a list, like ReadLength. If the number of unread bytes of r
+// is known (see Prealloc), a length greater than it is rejected, so a list cannot have more elements than
+// bytes. Element types encoded in zero bytes are limited too, which bounds the work done for a large
+// length prefix followed by little data.
+func ReadListLength(r io.Reader, maxLength int) (int, error) {
+	length, err := ReadLength(r, maxLength)
+	if err != nil {
+		return 0, err
+	}
+	if n, ok := unread(r); ok && length > n {
+		return 0, fmt.Errorf("list of %d elements exceeds the %d bytes remaining", length, n)
+	}
+	return length, nil
+}
+
+// maxPrealloc is the most list elements allocated before they are read from a reader whose number of
+// unread bytes is not known.
+const maxPrealloc = 1024
+
+// Prealloc returns the capacity to allocate for a list of length elements before reading them from r, so
+// that a large length prefix cannot allocate much more memory than the data it is followed by. For a
+// Buffer, or a reader with a Len method reporting its unread bytes such as bytes.Reader, the capacity is
+// limited by them. For other readers it is limited to a fixed number of elements.
+func Prealloc(r io.Reader, length int) int {
+	if n, ok := unread(r); ok {
+		return min(length, n)
+	}
+	return min(length, maxPrealloc)
+}
+
+// unread returns the number of bytes which can still be read from r, if it is known.
+func unread(r io.Reader) (int, bool) {
+	switch r := r.(type) {
+	case *Buffer:
+		return r.Remaining(), true
+	case interface{ Len() int }:
+		return r.Len(), true
+	}
+	return 0, false
+}
+
 func WriteList[T any](w io.Writer, list []T, f func(t T) error) (err error) {
 	if err = VarInt.Write(w, int32(len(list))); err != nil {
 		return err
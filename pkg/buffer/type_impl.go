@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/bits"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/nbt"
 	"github.com/mworzala/kite/pkg/text"
 )
 
@@ -28,6 +32,16 @@ func (byteType) Write(w io.Writer, v byte) error {
 	return binary.Write(w, binary.BigEndian, v)
 }
 
+type signedByteType struct{}
+
+func (signedByteType) Read(r io.Reader) (int8, error) {
+	value, err := Byte.Read(r)
+	return int8(value), err
+}
+func (signedByteType) Write(w io.Writer, v int8) error {
+	return Byte.Write(w, byte(v))
+}
+
 type boolType struct{}
 
 func (boolType) Read(r io.Reader) (bool, error) {
@@ -42,6 +56,17 @@ func (boolType) Write(w io.Writer, v bool) error {
 	return binary.Write(w, binary.BigEndian, byte(0))
 }
 
+type shortType struct{}
+
+func (shortType) Read(r io.Reader) (int16, error) {
+	var value int16
+	err := binary.Read(r, binary.BigEndian, &value)
+	return value, err
+}
+func (shortType) Write(w io.Writer, v int16) error {
+	return binary.Write(w, binary.BigEndian, v)
+}
+
 type uShortType struct{}
 
 func (uShortType) Read(r io.Reader) (uint16, error) {
@@ -53,6 +78,17 @@ func (uShortType) Write(w io.Writer, v uint16) error {
 	return binary.Write(w, binary.BigEndian, v)
 }
 
+type intType struct{}
+
+func (intType) Read(r io.Reader) (int32, error) {
+	var value int32
+	err := binary.Read(r, binary.BigEndian, &value)
+	return value, err
+}
+func (intType) Write(w io.Writer, v int32) error {
+	return binary.Write(w, binary.BigEndian, v)
+}
+
 type varIntType struct{}
 
 func (varIntType) Read(r io.Reader) (int32, error) {
@@ -106,6 +142,70 @@ func (longType) Write(w io.Writer, v int64) error {
 	return binary.Write(w, binary.BigEndian, v)
 }
 
+type varLongType struct{}
+
+func (varLongType) Read(r io.Reader) (int64, error) {
+	var value uint64
+	var position uint
+
+	for {
+		currentByte, err := Byte.Read(r)
+		if err != nil {
+			return 0, err
+		}
+
+		value |= uint64(currentByte&byte(varIntSegmentBits)) << position
+
+		if currentByte&varIntContinueBit == 0 {
+			break
+		}
+
+		position += 7
+
+		if position >= 64 {
+			return 0, fmt.Errorf("VarLong is too big")
+		}
+	}
+
+	return int64(value), nil
+}
+func (varLongType) Write(w io.Writer, v int64) error {
+	value := uint64(v)
+	for {
+		if (value & ^uint64(varIntSegmentBits)) == 0 {
+			return Byte.Write(w, byte(value))
+		}
+
+		if err := Byte.Write(w, byte(value&uint64(varIntSegmentBits))|varIntContinueBit); err != nil {
+			return err
+		}
+
+		value >>= 7
+	}
+}
+
+type floatType struct{}
+
+func (floatType) Read(r io.Reader) (float32, error) {
+	var value float32
+	err := binary.Read(r, binary.BigEndian, &value)
+	return value, err
+}
+func (floatType) Write(w io.Writer, v float32) error {
+	return binary.Write(w, binary.BigEndian, v)
+}
+
+type doubleType struct{}
+
+func (doubleType) Read(r io.Reader) (float64, error) {
+	var value float64
+	err := binary.Read(r, binary.BigEndian, &value)
+	return value, err
+}
+func (doubleType) Write(w io.Writer, v float64) error {
+	return binary.Write(w, binary.BigEndian, v)
+}
+
 type uuidType struct{}
 
 func (uuidType) Read(r io.Reader) (_ uuid.UUID, err error) {
@@ -132,52 +232,21 @@ func (uuidType) Write(w io.Writer, v uuid.UUID) error {
 	return binary.Write(w, binary.BigEndian, leastSigBits)
 }
 
-type stringType struct{}
-
-func (stringType) Read(r io.Reader) (string, error) {
-	length, err := VarInt.Read(r)
-	if err != nil {
-		return "", err
-	}
-	remainder := r.(*Buffer).Remaining()
-	if length > int32(remainder) {
-		return "", fmt.Errorf("string length %d exceeds maximum %d", length, remainder)
-	}
-	value := make([]byte, length)
-	_, err = r.Read(value)
-	if err != nil {
-		return "", err
-	}
-	return string(value), nil
+type byteArrayType struct {
+	maxLength int
 }
-func (stringType) Write(w io.Writer, v string) error {
-	if err := VarInt.Write(w, int32(len(v))); err != nil {
-		return err
-	}
-	if _, err := w.Write([]byte(v)); err != nil {
-		return err
-	}
-	return nil
-}
-
-type byteArrayType struct{}
 
-func (byteArrayType) Read(r io.Reader) ([]byte, error) {
-	length, err := VarInt.Read(r)
+func (t byteArrayType) Read(r io.Reader) ([]byte, error) {
+	length, err := ReadLength(r, t.maxLength)
 	if err != nil {
 		return nil, err
 	}
-	bytes := make([]byte, length)
-	n, err := r.Read(bytes)
-	if err != nil && (length < 0 || !errors.Is(err, io.EOF)) {
-		return nil, err
-	}
-	if length >= 0 && n != int(length) {
-		return nil, fmt.Errorf("expected %d bytes but read %d", length, n)
-	}
-	return bytes, err
+	return readBytes(r, length)
 }
-func (byteArrayType) Write(w io.Writer, v []byte) error {
+func (t byteArrayType) Write(w io.Writer, v []byte) error {
+	if len(v) > t.maxLength {
+		return fmt.Errorf("byte array of %d bytes exceeds maximum %d", len(v), t.maxLength)
+	}
 	if err := VarInt.Write(w, int32(len(v))); err != nil {
 		return err
 	}
@@ -210,6 +279,229 @@ func (rawBytesType) Write(w io.Writer, v []byte) error {
 	return nil
 }
 
+type nbtType struct{}
+
+func (nbtType) Read(r io.Reader) (nbt.Tag, error) {
+	return nbt.ReadNetwork(r)
+}
+func (nbtType) Write(w io.Writer, v nbt.Tag) error {
+	return nbt.WriteNetwork(w, v)
+}
+
+type rawNBTType struct{}
+
+func (rawNBTType) Read(r io.Reader) ([]byte, error) {
+	if b, ok := r.(*Buffer); ok {
+		start := b.position
+		if err := nbt.SkipNetwork(b); err != nil {
+			return nil, err
+		}
+		return bytes.Clone(b.delegate[start:b.position]), nil
+	}
+	var data bytes.Buffer
+	if err := nbt.SkipNetwork(io.TeeReader(r, &data)); err != nil {
+		return nil, err
+	}
+	return data.Bytes(), nil
+}
+func (rawNBTType) Write(w io.Writer, v []byte) error {
+	if len(v) == 0 {
+		return fmt.Errorf("empty NBT")
+	}
+	return RawBytes.Write(w, v)
+}
+
+// positionType packs a position as 26 bits of X, 26 bits of Z and 12 bits of Y.
+type positionType struct{}
+
+func (positionType) Read(r io.Reader) (BlockPos, error) {
+	value, err := Long.Read(r)
+	if err != nil {
+		return BlockPos{}, err
+	}
+	return BlockPos{
+		X: int32(value >> 38),
+		Y: int32(value << 52 >> 52),
+		Z: int32(value << 26 >> 38),
+	}, nil
+}
+func (positionType) Write(w io.Writer, v BlockPos) error {
+	return Long.Write(w, int64(v.X&0x3FFFFFF)<<38|int64(v.Z&0x3FFFFFF)<<12|int64(v.Y&0xFFF))
+}
+
+type angleType struct{}
+
+func (angleType) Read(r io.Reader) (float32, error) {
+	value, err := Byte.Read(r)
+	return float32(value) * 360 / 256, err
+}
+func (angleType) Write(w io.Writer, v float32) error {
+	return Byte.Write(w, byte(int32(math.Floor(float64(v*256/360)))))
+}
+
+type bitSetType struct{}
+
+func (bitSetType) Read(r io.Reader) (Bits, error) {
+	words, err := ReadList(r, func() (int64, error) { return Long.Read(r) })
+	if err != nil {
+		return nil, err
+	}
+	set := make(Bits, len(words))
+	for i, word := range words {
+		set[i] = uint64(word)
+	}
+	return set, nil
+}
+func (bitSetType) Write(w io.Writer, v Bits) error {
+	return WriteList(w, v, func(word uint64) error { return Long.Write(w, int64(word)) })
+}
+
+// fixedBitSetType stores bit i in byte i/8 at position i%8.
+type fixedBitSetType struct {
+	size int
+}
+
+func (t fixedBitSetType) Read(r io.Reader) (Bits, error) {
+	data := make([]byte, (t.size+7)/8)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	if t.size%8 != 0 && data[len(data)-1]>>(t.size%8) != 0 {
+		return nil, fmt.Errorf("bit set has more than %d bits", t.size)
+	}
+	set := make(Bits, (t.size+63)/64)
+	for i, b := range data {
+		set[i/8] |= uint64(b) << (i % 8 * 8)
+	}
+	return set, nil
+}
+func (t fixedBitSetType) Write(w io.Writer, v Bits) error {
+	for i, word := range v {
+		if word != 0 && 64*i+bits.Len64(word) > t.size {
+			return fmt.Errorf("bit set has more than %d bits", t.size)
+		}
+	}
+	data := make([]byte, (t.size+7)/8)
+	for i := range data {
+		if i/8 < len(v) {
+			data[i] = byte(v[i/8] >> (i % 8 * 8))
+		}
+	}
+	return RawBytes.Write(w, data)
+}
+
+type limitedStringType struct {
+	maxLength int
+}
+
+func (t limitedStringType) Read(r io.Reader) (string, error) {
+	// A character is at most 3 bytes of UTF-8 (4 for a surrogate pair, which is 2 characters).
+	length, err := ReadLength(r, t.maxLength*3)
+	if err != nil {
+		return "", fmt.Errorf("string: %w", err)
+	}
+	value, err := readBytes(r, length)
+	if err != nil {
+		return "", err
+	}
+	if err = checkStringLength(value, t.maxLength); err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+func (t limitedStringType) Write(w io.Writer, v string) error {
+	if err := checkStringLength(v, t.maxLength); err != nil {
+		return err
+	}
+	if err := VarInt.Write(w, int32(len(v))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, v)
+	return err
+}
+
+// readBytes reads n bytes without allocating much more than the data received: from a Buffer, n is checked
+// against the remaining bytes first, and other readers are read in growing chunks.
+func readBytes(r io.Reader, n int) ([]byte, error) {
+	if b, ok := r.(*Buffer); ok {
+		data, err := b.next(n)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.Clone(data), nil
+	}
+	data := []byte{}
+	for len(data) < n {
+		start := len(data)
+		chunk := min(n-start, max(start, 8*maxPrealloc))
+		data = slices.Grow(data, chunk)[:start+chunk]
+		if _, err := io.ReadFull(r, data[start:]); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// checkStringLength returns an error if v is longer than maxLength UTF-16 characters, as the game counts them.
+func checkStringLength[T string | []byte](v T, maxLength int) error {
+	if len(v) <= maxLength {
+		return nil
+	}
+	length := 0
+	for _, c := range string(v) {
+		if c >= 0x10000 {
+			length += 2 // A surrogate pair
+		} else {
+			length++
+		}
+	}
+	if length > maxLength {
+		return fmt.Errorf("string of %d characters exceeds maximum %d", length, maxLength)
+	}
+	return nil
+}
+
+type identifierType struct{}
+
+var identifierString = LimitedString(MaxIdentifierLength)
+
+func (identifierType) Read(r io.Reader) (string, error) {
+	value, err := identifierString.Read(r)
+	if err != nil {
+		return "", err
+	}
+	if !validIdentifier(value) {
+		return "", fmt.Errorf("invalid identifier %q", value)
+	}
+	return value, nil
+}
+func (identifierType) Write(w io.Writer, v string) error {
+	if !validIdentifier(v) {
+		return fmt.Errorf("invalid identifier %q", v)
+	}
+	return identifierString.Write(w, v)
+}
+
+// validIdentifier returns whether s is a valid namespace:path (or path) identifier. Namespaces may contain
+// a-z, 0-9, _, - and ., and paths may also contain /.
+func validIdentifier(s string) bool {
+	namespace, path, ok := strings.Cut(s, ":")
+	if !ok {
+		namespace, path = "", s
+	}
+	for _, c := range namespace {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.') {
+			return false
+		}
+	}
+	for _, c := range path {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.' || c == '/') {
+			return false
+		}
+	}
+	return true
+}
+
 type textComponentType struct{}
 
 func (textComponentType) Read(r io.Reader) (text.Component, error) {
@@ -222,20 +514,22 @@ func (textComponentType) Write(w io.Writer, v text.Component) error {
 type textComponentJSONType struct{}
 
 func (textComponentJSONType) Read(r io.Reader) (text.Component, error) {
-	raw, err := String.Read(r)
+	raw, err := textJSONString.Read(r)
 	if err != nil {
 		return nil, err
 	}
-	return text.UnmarshalJSON(bytes.NewReader([]byte(raw)))
+	return text.UnmarshalJSON(strings.NewReader(raw))
 }
 func (textComponentJSONType) Write(w io.Writer, v text.Component) error {
 	raw, err := text.MarshalJSON(v)
 	if err != nil {
 		return err
 	}
-	return String.Write(w, string(raw))
+	return textJSONString.Write(w, string(raw))
 }
 
+var textJSONString = LimitedString(MaxTextJSONLength)
+
 // Enum
 
 func (e Enum[T]) Read(r io.Reader) (value T, err error) {
@@ -296,23 +590,29 @@ func (jsonType[T]) Write(w io.Writer, v T) error {
 }
 
 type listType[T any] struct {
-	t Type[T]
+	t         Type[T]
+	maxLength int
 }
 
 func (l listType[T]) Read(r io.Reader) (t []T, err error) {
-	length, err := VarInt.Read(r)
+	length, err := ReadListLength(r, l.maxLength)
 	if err != nil {
 		return
 	}
-	t = make([]T, length)
-	for i := range t {
-		if t[i], err = l.t.Read(r); err != nil {
-			return
+	t = make([]T, 0, Prealloc(r, length))
+	for range length {
+		value, err := l.t.Read(r)
+		if err != nil {
+			return nil, err
 		}
+		t = append(t, value)
 	}
 	return
 }
 func (l listType[T]) Write(w io.Writer, v []T) error {
+	if len(v) > l.maxLength {
+		return fmt.Errorf("list of %d elements exceeds maximum %d", len(v), l.maxLength)
+	}
 	if err := VarInt.Write(w, int32(len(v))); err != nil {
 		return err
 	}
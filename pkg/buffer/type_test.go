@@ -0,0 +1,411 @@
+package buffer
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/nbt"
+	"github.com/stretchr/testify/require"
+)
+
+type typeCase[T any] struct {
+	value T
+	data  []byte
+}
+
+// testType checks that every value is written as its data and read back from it.
+func testType[T any](t *testing.T, typ Type[T], cases ...typeCase[T]) {
+	t.Helper()
+	for _, c := range cases {
+		var buf bytes.Buffer
+		require.NoError(t, typ.Write(&buf, c.value), "%v", c.value)
+		require.Equal(t, c.data, buf.Bytes(), "%v", c.value)
+
+		r := Wrap(c.data)
+		value, err := typ.Read(r)
+		require.NoError(t, err, "%v", c.value)
+		require.Equal(t, c.value, value)
+		require.Zero(t, r.Remaining(), "%v", c.value)
+
+		// Truncated input is an error.
+		if len(c.data) > 0 {
+			_, err = typ.Read(Wrap(c.data[:len(c.data)-1]))
+			require.Error(t, err, "%v", c.value)
+		}
+	}
+}
+
+func TestIntegers(t *testing.T) {
+	testType(t, Byte, typeCase[byte]{0, []byte{0x00}}, typeCase[byte]{0xFF, []byte{0xFF}})
+	testType(t, SignedByte,
+		typeCase[int8]{0, []byte{0x00}},
+		typeCase[int8]{127, []byte{0x7F}},
+		typeCase[int8]{-1, []byte{0xFF}},
+		typeCase[int8]{-128, []byte{0x80}},
+	)
+	testType(t, Bool, typeCase[bool]{false, []byte{0x00}}, typeCase[bool]{true, []byte{0x01}})
+	testType(t, Short,
+		typeCase[int16]{0, []byte{0x00, 0x00}},
+		typeCase[int16]{258, []byte{0x01, 0x02}},
+		typeCase[int16]{-1, []byte{0xFF, 0xFF}},
+		typeCase[int16]{math.MinInt16, []byte{0x80, 0x00}},
+	)
+	testType(t, Uint16,
+		typeCase[uint16]{25565, []byte{0x63, 0xDD}},
+		typeCase[uint16]{math.MaxUint16, []byte{0xFF, 0xFF}},
+	)
+	testType(t, Int,
+		typeCase[int32]{0, []byte{0x00, 0x00, 0x00, 0x00}},
+		typeCase[int32]{16909060, []byte{0x01, 0x02, 0x03, 0x04}},
+		typeCase[int32]{-1, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		typeCase[int32]{math.MinInt32, []byte{0x80, 0x00, 0x00, 0x00}},
+	)
+	testType(t, Long,
+		typeCase[int64]{1, []byte{0, 0, 0, 0, 0, 0, 0, 0x01}},
+		typeCase[int64]{-2, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE}},
+		typeCase[int64]{math.MaxInt64, []byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+	)
+}
+
+func TestVarInt(t *testing.T) {
+	testType(t, VarInt,
+		typeCase[int32]{0, []byte{0x00}},
+		typeCase[int32]{1, []byte{0x01}},
+		typeCase[int32]{2, []byte{0x02}},
+		typeCase[int32]{127, []byte{0x7F}},
+		typeCase[int32]{128, []byte{0x80, 0x01}},
+		typeCase[int32]{255, []byte{0xFF, 0x01}},
+		typeCase[int32]{25565, []byte{0xDD, 0xC7, 0x01}},
+		typeCase[int32]{2097151, []byte{0xFF, 0xFF, 0x7F}},
+		typeCase[int32]{math.MaxInt32, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x07}},
+		typeCase[int32]{-1, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F}},
+		typeCase[int32]{math.MinInt32, []byte{0x80, 0x80, 0x80, 0x80, 0x08}},
+	)
+	_, err := VarInt.Read(Wrap([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}))
+	require.ErrorContains(t, err, "too big")
+}
+
+func TestVarLong(t *testing.T) {
+	testType(t, VarLong,
+		typeCase[int64]{0, []byte{0x00}},
+		typeCase[int64]{1, []byte{0x01}},
+		typeCase[int64]{2, []byte{0x02}},
+		typeCase[int64]{127, []byte{0x7F}},
+		typeCase[int64]{128, []byte{0x80, 0x01}},
+		typeCase[int64]{255, []byte{0xFF, 0x01}},
+		typeCase[int64]{math.MaxInt32, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x07}},
+		typeCase[int64]{math.MaxInt64, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F}},
+		typeCase[int64]{-1, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}},
+		typeCase[int64]{math.MinInt32, []byte{0x80, 0x80, 0x80, 0x80, 0xF8, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}},
+		typeCase[int64]{math.MinInt64, []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01}},
+	)
+	_, err := VarLong.Read(Wrap(bytes.Repeat([]byte{0xFF}, 11)))
+	require.ErrorContains(t, err, "too big")
+}
+
+func TestFloats(t *testing.T) {
+	testType(t, Float,
+		typeCase[float32]{0, []byte{0x00, 0x00, 0x00, 0x00}},
+		typeCase[float32]{1, []byte{0x3F, 0x80, 0x00, 0x00}},
+		typeCase[float32]{-2.5, []byte{0xC0, 0x20, 0x00, 0x00}},
+		typeCase[float32]{float32(math.Inf(1)), []byte{0x7F, 0x80, 0x00, 0x00}},
+	)
+	testType(t, Double,
+		typeCase[float64]{0, []byte{0, 0, 0, 0, 0, 0, 0, 0}},
+		typeCase[float64]{1, []byte{0x3F, 0xF0, 0, 0, 0, 0, 0, 0}},
+		typeCase[float64]{-2.5, []byte{0xC0, 0x04, 0, 0, 0, 0, 0, 0}},
+		typeCase[float64]{0.1, []byte{0x3F, 0xB9, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9A}},
+	)
+}
+
+func TestAngle(t *testing.T) {
+	testType(t, Angle,
+		typeCase[float32]{0, []byte{0x00}},
+		typeCase[float32]{90, []byte{0x40}},
+		typeCase[float32]{180, []byte{0x80}},
+		typeCase[float32]{1.40625, []byte{0x01}},
+		typeCase[float32]{358.59375, []byte{0xFF}},
+	)
+
+	// Angles are rounded down to a step and wrap around.
+	for value, expected := range map[float32]byte{1: 0x00, 2: 0x01, -90: 0xC0, 360: 0x00, 450: 0x40} {
+		var buf bytes.Buffer
+		require.NoError(t, Angle.Write(&buf, value))
+		require.Equal(t, []byte{expected}, buf.Bytes(), "%v", value)
+	}
+}
+
+func TestUUID(t *testing.T) {
+	testType(t, UUID,
+		typeCase[uuid.UUID]{uuid.Nil, make([]byte, 16)},
+		typeCase[uuid.UUID]{
+			uuid.MustParse("069a79f4-44e9-4726-a5be-fca90e38aaf5"),
+			[]byte{0x06, 0x9A, 0x79, 0xF4, 0x44, 0xE9, 0x47, 0x26, 0xA5, 0xBE, 0xFC, 0xA9, 0x0E, 0x38, 0xAA, 0xF5},
+		},
+	)
+}
+
+func TestPosition(t *testing.T) {
+	testType(t, Position,
+		typeCase[BlockPos]{BlockPos{}, []byte{0, 0, 0, 0, 0, 0, 0, 0}},
+		typeCase[BlockPos]{BlockPos{X: 1, Y: 2, Z: 3}, []byte{0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x30, 0x02}},
+		typeCase[BlockPos]{BlockPos{X: -1, Y: -1, Z: -1}, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+		// The example from the protocol documentation.
+		typeCase[BlockPos]{
+			BlockPos{X: 18357644, Y: 831, Z: -20882616},
+			[]byte{0x46, 0x07, 0x63, 0x2C, 0x15, 0xB4, 0x83, 0x3F},
+		},
+		typeCase[BlockPos]{
+			BlockPos{X: -33554432, Y: -2048, Z: 33554431},
+			[]byte{0x80, 0x00, 0x00, 0x1F, 0xFF, 0xFF, 0xF8, 0x00},
+		},
+	)
+}
+
+func TestStrings(t *testing.T) {
+	testType(t, String,
+		typeCase[string]{"", []byte{0x00}},
+		typeCase[string]{"kite", []byte{0x04, 'k', 'i', 't', 'e'}},
+		typeCase[string]{"é", []byte{0x02, 0xC3, 0xA9}},
+	)
+	testType(t, ByteArray,
+		typeCase[[]byte]{[]byte{}, []byte{0x00}},
+		typeCase[[]byte]{[]byte{1, 2, 3}, []byte{0x03, 1, 2, 3}},
+	)
+	long := strings.Repeat("a", 200)
+	testType(t, String, typeCase[string]{long, append([]byte{0xC8, 0x01}, long...)})
+}
+
+func TestLimitedString(t *testing.T) {
+	typ := LimitedString(4)
+	testType(t, typ,
+		typeCase[string]{"", []byte{0x00}},
+		typeCase[string]{"kite", []byte{0x04, 'k', 'i', 't', 'e'}},
+		// Multibyte characters count once, and characters outside the BMP twice.
+		typeCase[string]{"éééé", []byte{0x08, 0xC3, 0xA9, 0xC3, 0xA9, 0xC3, 0xA9, 0xC3, 0xA9}},
+		typeCase[string]{"😀😀", []byte{0x08, 0xF0, 0x9F, 0x98, 0x80, 0xF0, 0x9F, 0x98, 0x80}},
+	)
+
+	var buf bytes.Buffer
+	require.ErrorContains(t, typ.Write(&buf, "kites"), "exceeds maximum 4")
+	require.ErrorContains(t, typ.Write(&buf, "😀😀😀"), "exceeds maximum 4")
+	require.Empty(t, buf.Bytes())
+
+	_, err := typ.Read(Wrap([]byte{0x05, 'k', 'i', 't', 'e', 's'}))
+	require.ErrorContains(t, err, "exceeds maximum 4")
+	// The byte length is checked before reading the string.
+	_, err = typ.Read(Wrap([]byte{0x0D}))
+	require.ErrorContains(t, err, "exceeds maximum 12")
+	_, err = typ.Read(Wrap([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F}))
+	require.ErrorContains(t, err, "invalid")
+}
+
+func TestIdentifier(t *testing.T) {
+	testType(t, Identifier,
+		typeCase[string]{"stone", []byte{0x05, 's', 't', 'o', 'n', 'e'}},
+		typeCase[string]{"minecraft:stone", append([]byte{0x0F}, "minecraft:stone"...)},
+		typeCase[string]{"my_pack-1.0:block/a_b", append([]byte{0x15}, "my_pack-1.0:block/a_b"...)},
+	)
+
+	for _, invalid := range []string{"Stone", "minecraft:Stone", "a:b:c", "a/b:c", "minecraft:stone block", "é"} {
+		var buf bytes.Buffer
+		require.ErrorContains(t, Identifier.Write(&buf, invalid), "invalid identifier", invalid)
+
+		require.NoError(t, String.Write(&buf, invalid))
+		_, err := Identifier.Read(Wrap(buf.Bytes()))
+		require.ErrorContains(t, err, "invalid identifier", invalid)
+	}
+}
+
+func TestBitSet(t *testing.T) {
+	testType(t, BitSet,
+		typeCase[Bits]{Bits{}, []byte{0x00}},
+		typeCase[Bits]{Bits{1}, []byte{0x01, 0, 0, 0, 0, 0, 0, 0, 0x01}},
+		typeCase[Bits]{
+			Bits{1 << 63, 0x0102},
+			[]byte{0x02, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x02},
+		},
+	)
+}
+
+func TestFixedBitSet(t *testing.T) {
+	testType(t, FixedBitSet(0), typeCase[Bits]{Bits{}, nil})
+	testType(t, FixedBitSet(3),
+		typeCase[Bits]{Bits{0}, []byte{0x00}},
+		typeCase[Bits]{Bits{0b101}, []byte{0x05}},
+	)
+	testType(t, FixedBitSet(20),
+		typeCase[Bits]{Bits{0x080201}, []byte{0x01, 0x02, 0x08}},
+	)
+	testType(t, FixedBitSet(72),
+		typeCase[Bits]{Bits{0x0807060504030201, 0x09}, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+	)
+
+	// Shorter sets are padded, and bits past the size are an error.
+	var buf bytes.Buffer
+	require.NoError(t, FixedBitSet(20).Write(&buf, nil))
+	require.Equal(t, []byte{0, 0, 0}, buf.Bytes())
+	require.ErrorContains(t, FixedBitSet(3).Write(&buf, Bits{0b1000}), "more than 3 bits")
+	require.ErrorContains(t, FixedBitSet(64).Write(&buf, Bits{0, 1}), "more than 64 bits")
+	_, err := FixedBitSet(3).Read(bytes.NewReader([]byte{0b1000}))
+	require.ErrorContains(t, err, "more than 3 bits")
+	_, err = FixedBitSet(20).Read(bytes.NewReader([]byte{0, 0, 0x10}))
+	require.ErrorContains(t, err, "more than 20 bits")
+}
+
+func TestBits(t *testing.T) {
+	var bits Bits
+	require.False(t, bits.Get(0))
+	bits.Set(3, false)
+	require.Nil(t, bits)
+
+	bits.Set(3, true)
+	bits.Set(64, true)
+	require.Equal(t, Bits{0b1000, 1}, bits)
+	require.True(t, bits.Get(3))
+	require.True(t, bits.Get(64))
+	require.False(t, bits.Get(4))
+	require.False(t, bits.Get(1000))
+
+	bits.Set(3, false)
+	require.Equal(t, Bits{0, 1}, bits)
+}
+
+func TestNBT(t *testing.T) {
+	compound := []byte{0x0a, 0x01, 0x00, 0x01, 'a', 0x01, 0x00}
+	testType(t, NBT,
+		typeCase[nbt.Tag]{nil, []byte{0x00}},
+		typeCase[nbt.Tag]{nbt.String("hi"), []byte{0x08, 0x00, 0x02, 'h', 'i'}},
+		typeCase[nbt.Tag]{nbt.Compound{"a": nbt.Byte(1)}, compound},
+	)
+	testType(t, RawNBT,
+		typeCase[[]byte]{[]byte{0x00}, []byte{0x00}},
+		typeCase[[]byte]{compound, compound},
+	)
+
+	// Raw NBT is copied from the buffer, and can be read from any reader.
+	r := Wrap(append(bytes.Clone(compound), 0xFF))
+	raw, err := RawNBT.Read(r)
+	require.NoError(t, err)
+	require.Equal(t, compound, raw)
+	require.Equal(t, 1, r.Remaining())
+	raw[0] = 0
+	require.Equal(t, byte(0x0a), r.delegate[0])
+
+	raw, err = RawNBT.Read(bytes.NewReader(compound))
+	require.NoError(t, err)
+	require.Equal(t, compound, raw)
+
+	var buf bytes.Buffer
+	require.Error(t, RawNBT.Write(&buf, nil))
+}
+
+func TestLengthLimits(t *testing.T) {
+	// Negative lengths are rejected by every variable-length type.
+	negative := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F}
+	for _, name := range []string{"string", "identifier", "byte array", "bit set", "list", "nested list", "json", "read list"} {
+		require.ErrorContains(t, fuzzTypes[name](Wrap(negative)), "invalid", name)
+	}
+
+	// Lengths over the limit are rejected before reading the data.
+	_, err := LimitedByteArray(2).Read(Wrap([]byte{0x03, 1, 2, 3}))
+	require.ErrorContains(t, err, "exceeds maximum 2")
+	_, err = LimitedList(VarInt, 2).Read(Wrap([]byte{0x03, 1, 2, 3}))
+	require.ErrorContains(t, err, "exceeds maximum 2")
+	require.Error(t, LimitedByteArray(2).Write(io.Discard, []byte{1, 2, 3}))
+	require.Error(t, LimitedList(VarInt, 2).Write(io.Discard, []int32{1, 2, 3}))
+	require.Error(t, String.Write(io.Discard, strings.Repeat("a", MaxStringLength+1)))
+
+	// A length prefix larger than the data fails without allocating it, from a Buffer or any other reader.
+	huge := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x07, 1, 2, 3}
+	for name, read := range fuzzTypes {
+		for _, r := range []func() io.Reader{
+			func() io.Reader { return Wrap(huge) },
+			func() io.Reader { return bytes.NewReader(huge) },
+		} {
+			require.LessOrEqual(t, allocatedBytes(func() { _ = read(r()) }), maxAllocation(len(huge)), name)
+		}
+	}
+
+	// Lists have no more elements than bytes remaining, even if their elements are encoded in zero bytes.
+	for _, r := range []io.Reader{Wrap(huge), bytes.NewReader(huge)} {
+		require.ErrorContains(t, fuzzTypes["empty struct list"](r), "exceeds the 3 bytes remaining")
+	}
+	var empty struct{ Values []struct{} }
+	require.NoError(t, ReadStruct(Wrap([]byte{0x02, 1, 2}), &empty))
+	require.Len(t, empty.Values, 2)
+}
+
+// fuzzTypes reads a value with each variable-length type.
+var fuzzTypes = map[string]func(r io.Reader) error{
+	"string":      readType(String),
+	"identifier":  readType(Identifier),
+	"byte array":  readType(ByteArray),
+	"bit set":     readType(BitSet),
+	"list":        readType(List(VarInt)),
+	"string list": readType(List(String)),
+	"uuid list":   readType(List(UUID)),
+	"nested list": readType(List(List(Long))),
+	"optional":    readType(Opt(String)),
+	"json":        readType(JSON[map[string]any]()),
+	"nbt":         readType(NBT),
+	"raw nbt":     readType(RawNBT),
+	"struct": func(r io.Reader) error {
+		var value testStruct
+		return ReadStruct(r, &value)
+	},
+	"empty struct list": func(r io.Reader) error {
+		var value struct{ Values []struct{} }
+		return ReadStruct(r, &value)
+	},
+	"read list": func(r io.Reader) error {
+		_, err := ReadList(r, func() (string, error) { return String.Read(r) })
+		return err
+	},
+}
+
+func readType[T any](typ Type[T]) func(r io.Reader) error {
+	return func(r io.Reader) error {
+		_, err := typ.Read(r)
+		return err
+	}
+}
+
+// maxAllocation is the most a read of size bytes may allocate: a constant for the fixed preallocation of
+// readers other than a Buffer, plus a multiple of the size for the values read.
+func maxAllocation(size int) uint64 {
+	return 128<<10 + 64*uint64(size)
+}
+
+func allocatedBytes(f func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc
+}
+
+func FuzzTypes(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x07, 1, 2, 3})
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F})
+	f.Add([]byte{0x02, 0x02, 0x01, 0x02, 0xFF, 0xFF, 0x7F})
+	f.Add([]byte{0x09, 0x09, 0x00, 0x00, 0x10, 0x00, 0x09, 0x00, 0x00, 0x10, 0x00})
+	f.Add([]byte{0x0a, 0x01, 0x00, 0x01, 'a', 0x01, 0x00})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for name, read := range fuzzTypes {
+			for _, r := range []io.Reader{Wrap(data), bytes.NewReader(data)} {
+				allocated := allocatedBytes(func() { _ = read(r) })
+				if allocated > maxAllocation(len(data)) {
+					t.Fatalf("%s: reading %d bytes allocated %d", name, len(data), allocated)
+				}
+			}
+		}
+	})
+}
@@ -40,9 +40,8 @@ func (gp *GameProfile) Write(w io.Writer) (err error) {
 	if err != nil {
 		return err
 	}
-	err = buffer.WriteList(w, gp.Properties, func(pp ProfileProperty) error {
+	return buffer.WriteList(w, gp.Properties, func(pp ProfileProperty) error {
 		return buffer.Write3(w, buffer.String, pp.Name, buffer.String,
 			pp.Value, buffer.Opt(buffer.String), pp.Signature)
 	})
-	return nil
 }
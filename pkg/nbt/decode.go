@@ -0,0 +1,303 @@
+package nbt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// maxPrealloc is the most elements allocated for an array before its contents are read, so that a large
+// length prefix cannot allocate much more memory than the data it is followed by. Lists can be nested, each
+// allocating before reading its first element, so they preallocate much less.
+const (
+	maxPrealloc     = 1024
+	maxListPrealloc = 16
+)
+
+// sizes are the sizes of fixed size payloads, and of the elements of arrays.
+var sizes = [...]int{
+	TagByte: 1, TagShort: 2, TagInt: 4, TagLong: 8, TagFloat: 4, TagDouble: 8,
+	TagByteArray: 1, TagIntArray: 4, TagLongArray: 8,
+}
+
+// ReadNetwork reads a network format tag, which is its type followed by its payload. An end tag (used by the
+// game when there is no value) is read as nil.
+func ReadNetwork(r io.Reader) (Tag, error) {
+	d := decoder{r: r}
+	t, err := d.readType()
+	if err != nil || t == TagEnd {
+		return nil, err
+	}
+	return d.readPayload(t, 0)
+}
+
+// ReadFile reads a file format tag, which is its type, name and payload. File format data is usually
+// compressed, which must be handled by the caller.
+func ReadFile(r io.Reader) (name string, tag Tag, err error) {
+	d := decoder{r: r}
+	t, err := d.readType()
+	if err != nil {
+		return "", nil, err
+	}
+	if t == TagEnd {
+		return "", nil, fmt.Errorf("%w: root is an end tag", ErrInvalid)
+	}
+	if name, err = d.readString(); err != nil {
+		return "", nil, err
+	}
+	tag, err = d.readPayload(t, 0)
+	return name, tag, err
+}
+
+// SkipNetwork reads past a network format tag without decoding it.
+func SkipNetwork(r io.Reader) error {
+	d := decoder{r: r, skip: true}
+	t, err := d.readType()
+	if err != nil || t == TagEnd {
+		return err
+	}
+	_, err = d.readPayload(t, 0)
+	return err
+}
+
+// SkipFile reads past a file format tag without decoding it.
+func SkipFile(r io.Reader) error {
+	d := decoder{r: r, skip: true}
+	t, err := d.readType()
+	if err != nil {
+		return err
+	}
+	if t == TagEnd {
+		return fmt.Errorf("%w: root is an end tag", ErrInvalid)
+	}
+	if err = d.skipString(); err != nil {
+		return err
+	}
+	_, err = d.readPayload(t, 0)
+	return err
+}
+
+type decoder struct {
+	r       io.Reader
+	scratch [8]byte
+
+	// skip discards payloads instead of decoding them, readPayload then returns a nil tag.
+	skip bool
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	_, err := io.ReadFull(d.r, d.scratch[:n])
+	return d.scratch[:n], err
+}
+
+func (d *decoder) readType() (TagType, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	if t := TagType(b[0]); t.Validate() {
+		return t, nil
+	}
+	return 0, fmt.Errorf("%w: unknown tag type %d", ErrInvalid, b[0])
+}
+
+// readLength reads an int length prefix, rejecting negative lengths.
+func (d *decoder) readLength() (int, error) {
+	b, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+	length := int32(binary.BigEndian.Uint32(b))
+	if length < 0 {
+		return 0, fmt.Errorf("%w: negative length %d", ErrInvalid, length)
+	}
+	return int(length), nil
+}
+
+func (d *decoder) readString() (string, error) {
+	b, err := d.read(2)
+	if err != nil {
+		return "", err
+	}
+	data, err := d.readBytes(int(binary.BigEndian.Uint16(b)))
+	if err != nil {
+		return "", err
+	}
+	return decodeMUTF8(data)
+}
+
+func (d *decoder) skipString() error {
+	b, err := d.read(2)
+	if err != nil {
+		return err
+	}
+	return d.discard(int64(binary.BigEndian.Uint16(b)))
+}
+
+// readBytes reads n bytes, growing the result as they are read rather than trusting n up front.
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	var data []byte
+	for len(data) < n {
+		start := len(data)
+		chunk := min(n-start, max(start, maxPrealloc*8))
+		data = slices.Grow(data, chunk)[:start+chunk]
+		if _, err := io.ReadFull(d.r, data[start:]); err != nil {
+			return nil, err
+		}
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return data, nil
+}
+
+func (d *decoder) discard(n int64) error {
+	copied, err := io.CopyN(io.Discard, d.r, n)
+	if err == io.EOF && copied < n {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func (d *decoder) readPayload(t TagType, depth int) (Tag, error) {
+	switch t {
+	case TagByte, TagShort, TagInt, TagLong, TagFloat, TagDouble:
+		b, err := d.read(sizes[t])
+		if err != nil || d.skip {
+			return nil, err
+		}
+		return decodeFixed(t, b), nil
+
+	case TagByteArray, TagIntArray, TagLongArray:
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		if d.skip {
+			return nil, d.discard(int64(length) * int64(sizes[t]))
+		}
+		switch t {
+		case TagByteArray:
+			data, err := d.readBytes(length)
+			return ByteArray(data), err
+		case TagIntArray:
+			values := make(IntArray, 0, min(length, maxPrealloc))
+			for range length {
+				b, err := d.read(4)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, int32(binary.BigEndian.Uint32(b)))
+			}
+			return values, nil
+		default:
+			values := make(LongArray, 0, min(length, maxPrealloc))
+			for range length {
+				b, err := d.read(8)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, int64(binary.BigEndian.Uint64(b)))
+			}
+			return values, nil
+		}
+
+	case TagString:
+		if d.skip {
+			return nil, d.skipString()
+		}
+		value, err := d.readString()
+		return String(value), err
+
+	case TagList:
+		if depth >= MaxDepth {
+			return nil, ErrTooDeep
+		}
+		elemType, err := d.readType()
+		if err != nil {
+			return nil, err
+		}
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		if elemType == TagEnd && length > 0 {
+			return nil, fmt.Errorf("%w: list of %d end tags", ErrInvalid, length)
+		}
+		var list List
+		if !d.skip {
+			list = make(List, 0, min(length, maxListPrealloc))
+		}
+		for range length {
+			elem, err := d.readPayload(elemType, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if !d.skip {
+				list = append(list, elem)
+			}
+		}
+		if d.skip {
+			return nil, nil
+		}
+		return list, nil
+
+	case TagCompound:
+		if depth >= MaxDepth {
+			return nil, ErrTooDeep
+		}
+		var compound Compound
+		if !d.skip {
+			compound = Compound{}
+		}
+		for {
+			t, err := d.readType()
+			if err != nil {
+				return nil, err
+			}
+			if t == TagEnd {
+				break
+			}
+			if d.skip {
+				if err = d.skipString(); err != nil {
+					return nil, err
+				}
+				if _, err = d.readPayload(t, depth+1); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			name, err := d.readString()
+			if err != nil {
+				return nil, err
+			}
+			if compound[name], err = d.readPayload(t, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		if d.skip {
+			return nil, nil
+		}
+		return compound, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected %s", ErrInvalid, t)
+}
+
+func decodeFixed(t TagType, b []byte) Tag {
+	switch t {
+	case TagByte:
+		return Byte(b[0])
+	case TagShort:
+		return Short(binary.BigEndian.Uint16(b))
+	case TagInt:
+		return Int(binary.BigEndian.Uint32(b))
+	case TagLong:
+		return Long(binary.BigEndian.Uint64(b))
+	case TagFloat:
+		return Float(math.Float32frombits(binary.BigEndian.Uint32(b)))
+	default:
+		return Double(math.Float64frombits(binary.BigEndian.Uint64(b)))
+	}
+}
@@ -0,0 +1,182 @@
+package nbt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// WriteNetwork writes a network format tag, which is its type followed by its payload. A nil tag is written
+// as an end tag.
+func WriteNetwork(w io.Writer, tag Tag) error {
+	e := encoder{w: w}
+	if tag == nil {
+		return e.writeType(TagEnd)
+	}
+	if err := e.writeType(tag.Type()); err != nil {
+		return err
+	}
+	return e.writePayload(tag, 0)
+}
+
+// WriteFile writes a file format tag, which is its type, name and payload.
+func WriteFile(w io.Writer, name string, tag Tag) error {
+	if tag == nil {
+		return fmt.Errorf("%w: root is nil", ErrInvalid)
+	}
+	e := encoder{w: w}
+	if err := e.writeType(tag.Type()); err != nil {
+		return err
+	}
+	if err := e.writeString(name); err != nil {
+		return err
+	}
+	return e.writePayload(tag, 0)
+}
+
+type encoder struct {
+	w       io.Writer
+	scratch [8]byte
+}
+
+func (e *encoder) write(b []byte) error {
+	_, err := e.w.Write(b)
+	return err
+}
+
+func (e *encoder) writeType(t TagType) error {
+	e.scratch[0] = byte(t)
+	return e.write(e.scratch[:1])
+}
+
+func (e *encoder) writeUint16(v uint16) error {
+	return e.write(binary.BigEndian.AppendUint16(e.scratch[:0], v))
+}
+
+func (e *encoder) writeUint32(v uint32) error {
+	return e.write(binary.BigEndian.AppendUint32(e.scratch[:0], v))
+}
+
+func (e *encoder) writeUint64(v uint64) error {
+	return e.write(binary.BigEndian.AppendUint64(e.scratch[:0], v))
+}
+
+func (e *encoder) writeLength(n int) error {
+	if n > math.MaxInt32 {
+		return fmt.Errorf("%w: length %d is too long", ErrInvalid, n)
+	}
+	return e.writeUint32(uint32(n))
+}
+
+func (e *encoder) writeString(s string) error {
+	data := encodeMUTF8(s)
+	if len(data) > math.MaxUint16 {
+		return fmt.Errorf("%w: string of %d bytes is too long", ErrInvalid, len(data))
+	}
+	if err := e.writeUint16(uint16(len(data))); err != nil {
+		return err
+	}
+	return e.write(data)
+}
+
+func (e *encoder) writePayload(tag Tag, depth int) error {
+	switch tag := tag.(type) {
+	case Byte:
+		e.scratch[0] = byte(tag)
+		return e.write(e.scratch[:1])
+	case Short:
+		return e.writeUint16(uint16(tag))
+	case Int:
+		return e.writeUint32(uint32(tag))
+	case Long:
+		return e.writeUint64(uint64(tag))
+	case Float:
+		return e.writeUint32(math.Float32bits(float32(tag)))
+	case Double:
+		return e.writeUint64(math.Float64bits(float64(tag)))
+	case ByteArray:
+		if err := e.writeLength(len(tag)); err != nil {
+			return err
+		}
+		return e.write(tag)
+	case String:
+		return e.writeString(string(tag))
+	case IntArray:
+		if err := e.writeLength(len(tag)); err != nil {
+			return err
+		}
+		for _, v := range tag {
+			if err := e.writeUint32(uint32(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case LongArray:
+		if err := e.writeLength(len(tag)); err != nil {
+			return err
+		}
+		for _, v := range tag {
+			if err := e.writeUint64(uint64(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case List:
+		if depth >= MaxDepth {
+			return ErrTooDeep
+		}
+		elemType := TagEnd
+		for i, elem := range tag {
+			if elem == nil {
+				return fmt.Errorf("%w: list element %d is nil", ErrInvalid, i)
+			}
+			if i == 0 {
+				elemType = elem.Type()
+			} else if elem.Type() != elemType {
+				return fmt.Errorf("%w: list of %s contains %s", ErrInvalid, elemType, elem.Type())
+			}
+		}
+		if err := e.writeType(elemType); err != nil {
+			return err
+		}
+		if err := e.writeLength(len(tag)); err != nil {
+			return err
+		}
+		for _, elem := range tag {
+			if err := e.writePayload(elem, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case Compound:
+		if depth >= MaxDepth {
+			return ErrTooDeep
+		}
+		names := make([]string, 0, len(tag))
+		for name := range tag {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			value := tag[name]
+			if value == nil {
+				return fmt.Errorf("%w: compound value %q is nil", ErrInvalid, name)
+			}
+			if err := e.writeType(value.Type()); err != nil {
+				return err
+			}
+			if err := e.writeString(name); err != nil {
+				return err
+			}
+			if err := e.writePayload(value, depth+1); err != nil {
+				return err
+			}
+		}
+		return e.writeType(TagEnd)
+	}
+	return fmt.Errorf("%w: unknown tag %T", ErrInvalid, tag)
+}
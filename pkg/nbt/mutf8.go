@@ -0,0 +1,88 @@
+package nbt
+
+import (
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// NBT strings are Java's modified UTF-8: NUL is encoded as two bytes, and characters outside the BMP are
+// encoded as a surrogate pair of three byte sequences rather than one four byte sequence.
+
+func encodeMUTF8(s string) []byte {
+	if isPlainASCII(s) {
+		return []byte(s)
+	}
+	data := make([]byte, 0, len(s)+len(s)/2)
+	for _, r := range s {
+		switch {
+		case r != 0 && r < 0x80:
+			data = append(data, byte(r))
+		case r < 0x800:
+			data = append(data, 0xC0|byte(r>>6), 0x80|byte(r&0x3F))
+		case r < 0x10000:
+			data = appendMUTF8Unit(data, r)
+		default:
+			high, low := utf16.EncodeRune(r)
+			data = appendMUTF8Unit(appendMUTF8Unit(data, high), low)
+		}
+	}
+	return data
+}
+
+func appendMUTF8Unit(data []byte, r rune) []byte {
+	return append(data, 0xE0|byte(r>>12), 0x80|byte(r>>6&0x3F), 0x80|byte(r&0x3F))
+}
+
+func decodeMUTF8(data []byte) (string, error) {
+	if isPlainASCII(data) {
+		return string(data), nil
+	}
+	result := make([]byte, 0, len(data))
+	var high rune // A pending high surrogate
+	for i := 0; i < len(data); {
+		var r rune
+		switch b := data[i]; {
+		case b < 0x80:
+			r = rune(b)
+			i++
+		case b&0xE0 == 0xC0 && i+1 < len(data) && data[i+1]&0xC0 == 0x80:
+			r = rune(b&0x1F)<<6 | rune(data[i+1]&0x3F)
+			i += 2
+		case b&0xF0 == 0xE0 && i+2 < len(data) && data[i+1]&0xC0 == 0x80 && data[i+2]&0xC0 == 0x80:
+			r = rune(b&0x0F)<<12 | rune(data[i+1]&0x3F)<<6 | rune(data[i+2]&0x3F)
+			i += 3
+		default:
+			return "", fmt.Errorf("%w: malformed string at byte %d", ErrInvalid, i)
+		}
+
+		if high != 0 {
+			if pair := utf16.DecodeRune(high, r); pair != utf8.RuneError {
+				result = utf8.AppendRune(result, pair)
+				high = 0
+				continue
+			}
+			result = utf8.AppendRune(result, utf8.RuneError)
+			high = 0
+		}
+		if utf16.IsSurrogate(r) && r < 0xDC00 {
+			high = r
+			continue
+		}
+		result = utf8.AppendRune(result, r) // Lone low surrogates become RuneError
+	}
+	if high != 0 {
+		result = utf8.AppendRune(result, utf8.RuneError)
+	}
+	return string(result), nil
+}
+
+// isPlainASCII returns whether s is ASCII without NUL, which is encoded the same in both formats.
+func isPlainASCII[T string | []byte](s T) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0 || s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+// Package nbt implements the Named Binary Tag format used by Minecraft, both the file format (where the root
+// tag has a name) and the network format used since 1.20.2 (where it does not).
+//
+// Tags are represented by the types implementing Tag, for example a Compound of Strings and Lists. Values
+// can also be skipped without decoding them, which is cheaper when they are only forwarded.
+//
+// See https://minecraft.wiki/w/NBT_format for the specification.
+package nbt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxDepth is the maximum nesting of compounds and lists, matching the game.
+const MaxDepth = 512
+
+var (
+	// ErrInvalid is returned when reading malformed NBT, or writing a value which cannot be encoded.
+	ErrInvalid = errors.New("invalid nbt")
+	// ErrTooDeep is returned when a value is nested more than MaxDepth times.
+	ErrTooDeep = errors.New("nbt is nested too deeply")
+)
+
+// TagType is the type ID of a tag, written before its payload.
+type TagType byte
+
+const (
+	TagEnd TagType = iota
+	TagByte
+	TagShort
+	TagInt
+	TagLong
+	TagFloat
+	TagDouble
+	TagByteArray
+	TagString
+	TagList
+	TagCompound
+	TagIntArray
+	TagLongArray
+)
+
+var tagTypeNames = [...]string{
+	"TAG_End", "TAG_Byte", "TAG_Short", "TAG_Int", "TAG_Long", "TAG_Float", "TAG_Double",
+	"TAG_Byte_Array", "TAG_String", "TAG_List", "TAG_Compound", "TAG_Int_Array", "TAG_Long_Array",
+}
+
+func (t TagType) Validate() bool {
+	return t <= TagLongArray
+}
+
+func (t TagType) String() string {
+	if !t.Validate() {
+		return fmt.Sprintf("TagType(%d)", t)
+	}
+	return tagTypeNames[t]
+}
+
+// A Tag is an NBT value: a Byte, Short, Int, Long, Float, Double, ByteArray, String, List, Compound, IntArray
+// or LongArray.
+type Tag interface {
+	Type() TagType
+}
+
+type (
+	Byte      int8
+	Short     int16
+	Int       int32
+	Long      int64
+	Float     float32
+	Double    float64
+	ByteArray []byte
+	String    string
+
+	// A List is a list of tags which all have the same type. Empty lists are written with the type TagEnd,
+	// like the game does.
+	List []Tag
+
+	// A Compound is a set of named tags. Names are written in sorted order, so encoding is deterministic.
+	Compound map[string]Tag
+
+	IntArray  []int32
+	LongArray []int64
+)
+
+func (Byte) Type() TagType      { return TagByte }
+func (Short) Type() TagType     { return TagShort }
+func (Int) Type() TagType       { return TagInt }
+func (Long) Type() TagType      { return TagLong }
+func (Float) Type() TagType     { return TagFloat }
+func (Double) Type() TagType    { return TagDouble }
+func (ByteArray) Type() TagType { return TagByteArray }
+func (String) Type() TagType    { return TagString }
+func (List) Type() TagType      { return TagList }
+func (Compound) Type() TagType  { return TagCompound }
+func (IntArray) Type() TagType  { return TagIntArray }
+func (LongArray) Type() TagType { return TagLongArray }
+
+// Bool returns the Byte used by the game to store a boolean.
+func Bool(b bool) Byte {
+	if b {
+		return 1
+	}
+	return 0
+}
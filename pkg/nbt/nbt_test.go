@@ -0,0 +1,227 @@
+package nbt
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// helloWorld is the hello_world.nbt example from the original specification.
+var helloWorld = []byte{
+	0x0a, 0x00, 0x0b, 'h', 'e', 'l', 'l', 'o', ' ', 'w', 'o', 'r', 'l', 'd',
+	0x08, 0x00, 0x04, 'n', 'a', 'm', 'e', 0x00, 0x09, 'B', 'a', 'n', 'a', 'n', 'r', 'a', 'm', 'a',
+	0x00,
+}
+
+var everyType = Compound{
+	"byte":      Byte(-1),
+	"short":     Short(math.MinInt16),
+	"int":       Int(1 << 20),
+	"long":      Long(-1 << 40),
+	"float":     Float(0.5),
+	"double":    Double(-0.25),
+	"byteArray": ByteArray{1, 2, 3},
+	"string":    String("kite"),
+	"list":      List{Int(1), Int(2)},
+	"emptyList": List{},
+	"nested":    List{Compound{"a": Byte(1)}, Compound{}},
+	"compound":  Compound{"name": String("é\x00😀")},
+	"intArray":  IntArray{-1, 0, 1},
+	"longArray": LongArray{math.MaxInt64},
+}
+
+func TestReadFile(t *testing.T) {
+	name, tag, err := ReadFile(bytes.NewReader(helloWorld))
+	require.NoError(t, err)
+	require.Equal(t, "hello world", name)
+	require.Equal(t, Compound{"name": String("Bananrama")}, tag)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteFile(&buf, name, tag))
+	require.Equal(t, helloWorld, buf.Bytes())
+
+	r := bytes.NewReader(helloWorld)
+	require.NoError(t, SkipFile(r))
+	require.Zero(t, r.Len())
+}
+
+func TestNetwork(t *testing.T) {
+	for _, tc := range []struct {
+		tag  Tag
+		data []byte
+	}{
+		{nil, []byte{0x00}},
+		{Byte(1), []byte{0x01, 0x01}},
+		{Short(-2), []byte{0x02, 0xFF, 0xFE}},
+		{Int(258), []byte{0x03, 0x00, 0x00, 0x01, 0x02}},
+		{Long(1), []byte{0x04, 0, 0, 0, 0, 0, 0, 0, 0x01}},
+		{Float(1), []byte{0x05, 0x3F, 0x80, 0x00, 0x00}},
+		{Double(1), []byte{0x06, 0x3F, 0xF0, 0, 0, 0, 0, 0, 0}},
+		{ByteArray{1, 2}, []byte{0x07, 0x00, 0x00, 0x00, 0x02, 0x01, 0x02}},
+		{String("hi"), []byte{0x08, 0x00, 0x02, 'h', 'i'}},
+		{List{}, []byte{0x09, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{List{Short(1)}, []byte{0x09, 0x02, 0x00, 0x00, 0x00, 0x01, 0x00, 0x01}},
+		{Compound{}, []byte{0x0a, 0x00}},
+		// Names are written in sorted order.
+		{Compound{"b": Byte(2), "a": Byte(1)}, []byte{0x0a, 0x01, 0x00, 0x01, 'a', 0x01, 0x01, 0x00, 0x01, 'b', 0x02, 0x00}},
+		{IntArray{-1}, []byte{0x0b, 0x00, 0x00, 0x00, 0x01, 0xFF, 0xFF, 0xFF, 0xFF}},
+		{LongArray{2}, []byte{0x0c, 0x00, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0, 0, 0x02}},
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteNetwork(&buf, tc.tag))
+		require.Equal(t, tc.data, buf.Bytes(), "%#v", tc.tag)
+
+		r := bytes.NewReader(tc.data)
+		tag, err := ReadNetwork(r)
+		require.NoError(t, err)
+		require.Equal(t, tc.tag, tag)
+		require.Zero(t, r.Len())
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteNetwork(&buf, everyType))
+	network := bytes.Clone(buf.Bytes())
+	tag, err := ReadNetwork(bytes.NewReader(network))
+	require.NoError(t, err)
+	require.Equal(t, everyType, tag)
+
+	buf.Reset()
+	require.NoError(t, WriteFile(&buf, "root", everyType))
+	name, tag, err := ReadFile(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, "root", name)
+	require.Equal(t, everyType, tag)
+
+	// Every encoding of a value is the same.
+	buf.Reset()
+	require.NoError(t, WriteNetwork(&buf, tag))
+	require.Equal(t, network, buf.Bytes())
+}
+
+func TestSkip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteNetwork(&buf, everyType))
+	buf.WriteString("rest")
+	r := bytes.NewReader(buf.Bytes())
+	require.NoError(t, SkipNetwork(r))
+	rest, _ := io.ReadAll(r)
+	require.Equal(t, "rest", string(rest))
+
+	r = bytes.NewReader([]byte{0x00, 0x01})
+	require.NoError(t, SkipNetwork(r))
+	require.Equal(t, 1, r.Len())
+}
+
+func TestMUTF8(t *testing.T) {
+	for s, encoded := range map[string][]byte{
+		"":     {},
+		"kite": []byte("kite"),
+		"\x00": {0xC0, 0x80},
+		"é":    {0xC3, 0xA9},
+		"€":    {0xE2, 0x82, 0xAC},
+		"😀":    {0xED, 0xA0, 0xBD, 0xED, 0xB8, 0x80},
+	} {
+		require.Equal(t, encoded, encodeMUTF8(s), s)
+		decoded, err := decodeMUTF8(encoded)
+		require.NoError(t, err)
+		require.Equal(t, s, decoded)
+	}
+
+	// Unpaired surrogates are replaced.
+	decoded, err := decodeMUTF8([]byte{0xED, 0xA0, 0xBD, 'a', 0xED, 0xB8, 0x80})
+	require.NoError(t, err)
+	require.Equal(t, "�a�", decoded)
+
+	for _, invalid := range [][]byte{{0x80}, {0xC3}, {0xE2, 0x82}, {0xF0, 0x9F, 0x98, 0x80}} {
+		_, err = decodeMUTF8(invalid)
+		require.ErrorIs(t, err, ErrInvalid, "%x", invalid)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"type":          {0x0d},
+		"negative":      {0x07, 0xFF, 0xFF, 0xFF, 0xFF},
+		"list of end":   {0x09, 0x00, 0x00, 0x00, 0x00, 0x01},
+		"element type":  {0x09, 0x0d, 0x00, 0x00, 0x00, 0x00},
+		"compound type": {0x0a, 0x0d},
+		"string":        {0x08, 0x00, 0x01, 0x80},
+	} {
+		_, err := ReadNetwork(bytes.NewReader(data))
+		require.ErrorIs(t, err, ErrInvalid, name)
+		if name != "string" {
+			require.ErrorIs(t, SkipNetwork(bytes.NewReader(data)), ErrInvalid, name)
+		}
+	}
+
+	_, _, err := ReadFile(bytes.NewReader([]byte{0x00}))
+	require.ErrorIs(t, err, ErrInvalid)
+	require.ErrorIs(t, SkipFile(bytes.NewReader([]byte{0x00})), ErrInvalid)
+}
+
+func TestRead_Truncated(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteNetwork(&buf, everyType))
+	data := buf.Bytes()
+	for i := range len(data) {
+		_, err := ReadNetwork(bytes.NewReader(data[:i]))
+		require.Error(t, err, i)
+		require.Error(t, SkipNetwork(bytes.NewReader(data[:i])), i)
+	}
+
+	// Large lengths fail without allocating the whole length.
+	for _, prefix := range []byte{0x07, 0x0b, 0x0c} {
+		allocs := testing.AllocsPerRun(10, func() {
+			_, err := ReadNetwork(bytes.NewReader([]byte{prefix, 0x7F, 0xFF, 0xFF, 0xFF, 1, 2, 3}))
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+		require.Less(t, allocs, float64(10))
+	}
+}
+
+func TestDepth(t *testing.T) {
+	var tag Tag = Compound{}
+	for range MaxDepth - 1 {
+		tag = List{tag}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteNetwork(&buf, tag))
+	_, err := ReadNetwork(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	require.ErrorIs(t, WriteNetwork(io.Discard, List{tag}), ErrTooDeep)
+	data := append([]byte{0x09, 0x09, 0x00, 0x00, 0x00, 0x01}, buf.Bytes()[1:]...)
+	_, err = ReadNetwork(bytes.NewReader(data))
+	require.ErrorIs(t, err, ErrTooDeep)
+	require.ErrorIs(t, SkipNetwork(bytes.NewReader(data)), ErrTooDeep)
+}
+
+func TestWrite_Invalid(t *testing.T) {
+	for name, tag := range map[string]Tag{
+		"mixed list":   List{Int(1), Long(2)},
+		"nil element":  List{nil},
+		"nil value":    Compound{"a": nil},
+		"long string":  String(strings.Repeat("a", math.MaxUint16+1)),
+		"long name":    Compound{strings.Repeat("é", math.MaxUint16/2+1): Byte(0)},
+		"nested mixed": Compound{"a": List{String("a"), Byte(1)}},
+	} {
+		require.ErrorIs(t, WriteNetwork(io.Discard, tag), ErrInvalid, name)
+	}
+	require.ErrorIs(t, WriteFile(io.Discard, "", nil), ErrInvalid)
+}
+
+func TestTagType(t *testing.T) {
+	require.Equal(t, "TAG_Compound", TagCompound.String())
+	require.Equal(t, "TagType(13)", TagType(13).String())
+	for tag, expected := range map[Tag]TagType{Byte(0): TagByte, String(""): TagString, Double(0): TagDouble} {
+		require.Equal(t, expected, tag.Type())
+	}
+	require.Equal(t, Byte(1), Bool(true))
+	require.Equal(t, Byte(0), Bool(false))
+}
@@ -0,0 +1,379 @@
+// Code generated by codecgen; DO NOT EDIT.
+
+package packet
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/buffer"
+)
+
+func (p *ClientKeepAlive) Direction() Direction { return Serverbound }
+func (p *ClientKeepAlive) ID(state State) int {
+	return stateId2(state, Config, Play, ClientConfigKeepAliveID, ClientPlayKeepAliveID)
+}
+func (p *ClientKeepAlive) Read(r io.Reader) (err error) {
+	if p.KeepAliveID, err = buffer.Long.Read(r); err != nil {
+		return fmt.Errorf("ClientKeepAlive.KeepAliveID: %w", err)
+	}
+	return nil
+}
+func (p *ClientKeepAlive) Write(w io.Writer) (err error) {
+	if err = buffer.Long.Write(w, p.KeepAliveID); err != nil {
+		return fmt.Errorf("ClientKeepAlive.KeepAliveID: %w", err)
+	}
+	return nil
+}
+
+func (p *ClientPluginMessage) Direction() Direction { return Serverbound }
+func (p *ClientPluginMessage) ID(state State) int {
+	return stateId2(state, Config, Play, ClientConfigPluginMessageID, ClientPlayPluginMessageID)
+}
+func (p *ClientPluginMessage) Read(r io.Reader) (err error) {
+	if p.Channel, err = buffer.String.Read(r); err != nil {
+		return fmt.Errorf("ClientPluginMessage.Channel: %w", err)
+	}
+	if p.Data, err = buffer.RawBytes.Read(r); err != nil {
+		return fmt.Errorf("ClientPluginMessage.Data: %w", err)
+	}
+	return nil
+}
+func (p *ClientPluginMessage) Write(w io.Writer) (err error) {
+	if err = buffer.String.Write(w, p.Channel); err != nil {
+		return fmt.Errorf("ClientPluginMessage.Channel: %w", err)
+	}
+	if err = buffer.RawBytes.Write(w, p.Data); err != nil {
+		return fmt.Errorf("ClientPluginMessage.Data: %w", err)
+	}
+	return nil
+}
+
+func (p *ClientPong) Direction() Direction { return Serverbound }
+func (p *ClientPong) ID(state State) int {
+	return stateId2(state, Config, Play, ClientConfigPongID, ClientPlayPongID)
+}
+func (p *ClientPong) Read(r io.Reader) (err error) {
+	if p.PingID, err = buffer.Int.Read(r); err != nil {
+		return fmt.Errorf("ClientPong.PingID: %w", err)
+	}
+	return nil
+}
+func (p *ClientPong) Write(w io.Writer) (err error) {
+	if err = buffer.Int.Write(w, p.PingID); err != nil {
+		return fmt.Errorf("ClientPong.PingID: %w", err)
+	}
+	return nil
+}
+
+func (p *ServerResourcePackPop) Direction() Direction { return Clientbound }
+func (p *ServerResourcePackPop) ID(state State) int {
+	return stateId2(state, Config, Play, ServerConfigRemoveResourcePackID, ServerPlayResourcePackPopID)
+}
+func (p *ServerResourcePackPop) Read(r io.Reader) (err error) {
+	{
+		var present0 bool
+		if present0, err = buffer.Bool.Read(r); err != nil {
+			return fmt.Errorf("ServerResourcePackPop.Id: %w", err)
+		}
+		if present0 {
+			if p.Id, err = buffer.UUID.Read(r); err != nil {
+				return fmt.Errorf("ServerResourcePackPop.Id: %w", err)
+			}
+		}
+	}
+	return nil
+}
+func (p *ServerResourcePackPop) Write(w io.Writer) (err error) {
+	if err = buffer.Bool.Write(w, p.Id != (uuid.UUID{})); err != nil {
+		return fmt.Errorf("ServerResourcePackPop.Id: %w", err)
+	}
+	if p.Id != (uuid.UUID{}) {
+		if err = buffer.UUID.Write(w, p.Id); err != nil {
+			return fmt.Errorf("ServerResourcePackPop.Id: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *ServerKeepAlive) Direction() Direction { return Clientbound }
+func (p *ServerKeepAlive) ID(state State) int {
+	return stateId2(state, Config, Play, ServerConfigKeepAliveID, ServerPlayKeepAliveID)
+}
+func (p *ServerKeepAlive) Read(r io.Reader) (err error) {
+	if p.KeepAliveID, err = buffer.Long.Read(r); err != nil {
+		return fmt.Errorf("ServerKeepAlive.KeepAliveID: %w", err)
+	}
+	return nil
+}
+func (p *ServerKeepAlive) Write(w io.Writer) (err error) {
+	if err = buffer.Long.Write(w, p.KeepAliveID); err != nil {
+		return fmt.Errorf("ServerKeepAlive.KeepAliveID: %w", err)
+	}
+	return nil
+}
+
+func (p *ServerPluginMessage) Direction() Direction { return Clientbound }
+func (p *ServerPluginMessage) ID(state State) int {
+	return stateId2(state, Config, Play, ServerConfigPluginMessageID, ServerPlayPluginMessageID)
+}
+func (p *ServerPluginMessage) Read(r io.Reader) (err error) {
+	if p.Channel, err = buffer.String.Read(r); err != nil {
+		return fmt.Errorf("ServerPluginMessage.Channel: %w", err)
+	}
+	if p.Data, err = buffer.RawBytes.Read(r); err != nil {
+		return fmt.Errorf("ServerPluginMessage.Data: %w", err)
+	}
+	return nil
+}
+func (p *ServerPluginMessage) Write(w io.Writer) (err error) {
+	if err = buffer.String.Write(w, p.Channel); err != nil {
+		return fmt.Errorf("ServerPluginMessage.Channel: %w", err)
+	}
+	if err = buffer.RawBytes.Write(w, p.Data); err != nil {
+		return fmt.Errorf("ServerPluginMessage.Data: %w", err)
+	}
+	return nil
+}
+
+func (p *ServerPing) Direction() Direction { return Clientbound }
+func (p *ServerPing) ID(state State) int {
+	return stateId2(state, Config, Play, ServerConfigPingID, ServerPlayPingID)
+}
+func (p *ServerPing) Read(r io.Reader) (err error) {
+	if p.PingID, err = buffer.Int.Read(r); err != nil {
+		return fmt.Errorf("ServerPing.PingID: %w", err)
+	}
+	return nil
+}
+func (p *ServerPing) Write(w io.Writer) (err error) {
+	if err = buffer.Int.Write(w, p.PingID); err != nil {
+		return fmt.Errorf("ServerPing.PingID: %w", err)
+	}
+	return nil
+}
+
+func (p *ServerPlayPlayerInfoRemove) Direction() Direction { return Clientbound }
+func (p *ServerPlayPlayerInfoRemove) ID(state State) int {
+	return stateId1(state, Play, ServerPlayPlayerInfoRemoveID)
+}
+func (p *ServerPlayPlayerInfoRemove) Read(r io.Reader) (err error) {
+	{
+		var n0 int
+		if n0, err = buffer.ReadListLength(r, buffer.MaxLength); err != nil {
+			return fmt.Errorf("ServerPlayPlayerInfoRemove.UUIDs: %w", err)
+		}
+		p.UUIDs = make([]uuid.UUID, 0, buffer.Prealloc(r, n0))
+		for range n0 {
+			var e0 uuid.UUID
+			if e0, err = buffer.UUID.Read(r); err != nil {
+				return fmt.Errorf("ServerPlayPlayerInfoRemove.UUIDs: %w", err)
+			}
+			p.UUIDs = append(p.UUIDs, e0)
+		}
+	}
+	return nil
+}
+func (p *ServerPlayPlayerInfoRemove) Write(w io.Writer) (err error) {
+	if err = buffer.VarInt.Write(w, int32(len(p.UUIDs))); err != nil {
+		return fmt.Errorf("ServerPlayPlayerInfoRemove.UUIDs: %w", err)
+	}
+	for _, v0 := range p.UUIDs {
+		if err = buffer.UUID.Write(w, v0); err != nil {
+			return fmt.Errorf("ServerPlayPlayerInfoRemove.UUIDs: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *ServerPlayRemoveEntities) Direction() Direction { return Clientbound }
+func (p *ServerPlayRemoveEntities) ID(state State) int {
+	return stateId1(state, Play, ServerPlayRemoveEntitiesID)
+}
+func (p *ServerPlayRemoveEntities) Read(r io.Reader) (err error) {
+	{
+		var n0 int
+		if n0, err = buffer.ReadListLength(r, buffer.MaxLength); err != nil {
+			return fmt.Errorf("ServerPlayRemoveEntities.EntityIDs: %w", err)
+		}
+		p.EntityIDs = make([]int32, 0, buffer.Prealloc(r, n0))
+		for range n0 {
+			var e0 int32
+			if e0, err = buffer.VarInt.Read(r); err != nil {
+				return fmt.Errorf("ServerPlayRemoveEntities.EntityIDs: %w", err)
+			}
+			p.EntityIDs = append(p.EntityIDs, e0)
+		}
+	}
+	return nil
+}
+func (p *ServerPlayRemoveEntities) Write(w io.Writer) (err error) {
+	if err = buffer.VarInt.Write(w, int32(len(p.EntityIDs))); err != nil {
+		return fmt.Errorf("ServerPlayRemoveEntities.EntityIDs: %w", err)
+	}
+	for _, v0 := range p.EntityIDs {
+		if err = buffer.VarInt.Write(w, v0); err != nil {
+			return fmt.Errorf("ServerPlayRemoveEntities.EntityIDs: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *ServerPlayBlockEntityData) Direction() Direction { return Clientbound }
+func (p *ServerPlayBlockEntityData) ID(state State) int {
+	return stateId1(state, Play, ServerPlayBlockEntityDataID)
+}
+func (p *ServerPlayBlockEntityData) Read(r io.Reader) (err error) {
+	if p.Location, err = buffer.Position.Read(r); err != nil {
+		return fmt.Errorf("ServerPlayBlockEntityData.Location: %w", err)
+	}
+	if p.Type, err = buffer.VarInt.Read(r); err != nil {
+		return fmt.Errorf("ServerPlayBlockEntityData.Type: %w", err)
+	}
+	if p.Data, err = buffer.NBT.Read(r); err != nil {
+		return fmt.Errorf("ServerPlayBlockEntityData.Data: %w", err)
+	}
+	return nil
+}
+func (p *ServerPlayBlockEntityData) Write(w io.Writer) (err error) {
+	if err = buffer.Position.Write(w, p.Location); err != nil {
+		return fmt.Errorf("ServerPlayBlockEntityData.Location: %w", err)
+	}
+	if err = buffer.VarInt.Write(w, p.Type); err != nil {
+		return fmt.Errorf("ServerPlayBlockEntityData.Type: %w", err)
+	}
+	if err = buffer.NBT.Write(w, p.Data); err != nil {
+		return fmt.Errorf("ServerPlayBlockEntityData.Data: %w", err)
+	}
+	return nil
+}
+
+func (p *ClientResourcePackStatus) String() string              { return FormatPacket(p) }
+func (p *ClientResourcePackStatus) LogValue() slog.Value        { return PacketLogValue(p) }
+func (p *ClientInformation) String() string                     { return FormatPacket(p) }
+func (p *ClientInformation) LogValue() slog.Value               { return PacketLogValue(p) }
+func (p *ClientCookieResponse) String() string                  { return FormatPacket(p) }
+func (p *ClientCookieResponse) LogValue() slog.Value            { return PacketLogValue(p) }
+func (p *ClientKeepAlive) String() string                       { return FormatPacket(p) }
+func (p *ClientKeepAlive) LogValue() slog.Value                 { return PacketLogValue(p) }
+func (p *ClientPluginMessage) String() string                   { return FormatPacket(p) }
+func (p *ClientPluginMessage) LogValue() slog.Value             { return PacketLogValue(p) }
+func (p *ClientPong) String() string                            { return FormatPacket(p) }
+func (p *ClientPong) LogValue() slog.Value                      { return PacketLogValue(p) }
+func (p *ServerResourcePackPush) String() string                { return FormatPacket(p) }
+func (p *ServerResourcePackPush) LogValue() slog.Value          { return PacketLogValue(p) }
+func (p *ServerResourcePackPop) String() string                 { return FormatPacket(p) }
+func (p *ServerResourcePackPop) LogValue() slog.Value           { return PacketLogValue(p) }
+func (p *ServerKeepAlive) String() string                       { return FormatPacket(p) }
+func (p *ServerKeepAlive) LogValue() slog.Value                 { return PacketLogValue(p) }
+func (p *ServerPluginMessage) String() string                   { return FormatPacket(p) }
+func (p *ServerPluginMessage) LogValue() slog.Value             { return PacketLogValue(p) }
+func (p *ServerDisconnect) String() string                      { return FormatPacket(p) }
+func (p *ServerDisconnect) LogValue() slog.Value                { return PacketLogValue(p) }
+func (p *ServerCookieRequest) String() string                   { return FormatPacket(p) }
+func (p *ServerCookieRequest) LogValue() slog.Value             { return PacketLogValue(p) }
+func (p *ServerPing) String() string                            { return FormatPacket(p) }
+func (p *ServerPing) LogValue() slog.Value                      { return PacketLogValue(p) }
+func (p *ServerStoreCookie) String() string                     { return FormatPacket(p) }
+func (p *ServerStoreCookie) LogValue() slog.Value               { return PacketLogValue(p) }
+func (p *ServerTransfer) String() string                        { return FormatPacket(p) }
+func (p *ServerTransfer) LogValue() slog.Value                  { return PacketLogValue(p) }
+func (p *ServerUpdateTags) String() string                      { return FormatPacket(p) }
+func (p *ServerUpdateTags) LogValue() slog.Value                { return PacketLogValue(p) }
+func (p *ServerCustomReportDetails) String() string             { return FormatPacket(p) }
+func (p *ServerCustomReportDetails) LogValue() slog.Value       { return PacketLogValue(p) }
+func (p *ServerLinks) String() string                           { return FormatPacket(p) }
+func (p *ServerLinks) LogValue() slog.Value                     { return PacketLogValue(p) }
+func (p *ClientConfigFinishConfiguration) String() string       { return FormatPacket(p) }
+func (p *ClientConfigFinishConfiguration) LogValue() slog.Value { return PacketLogValue(p) }
+func (p *ClientConfigKnownPacks) String() string                { return FormatPacket(p) }
+func (p *ClientConfigKnownPacks) LogValue() slog.Value          { return PacketLogValue(p) }
+func (p *ServerConfigFinishConfiguration) String() string       { return FormatPacket(p) }
+func (p *ServerConfigFinishConfiguration) LogValue() slog.Value { return PacketLogValue(p) }
+func (p *ServerConfigResetChat) String() string                 { return FormatPacket(p) }
+func (p *ServerConfigResetChat) LogValue() slog.Value           { return PacketLogValue(p) }
+func (p *ServerConfigRegistryData) String() string              { return FormatPacket(p) }
+func (p *ServerConfigRegistryData) LogValue() slog.Value        { return PacketLogValue(p) }
+func (p *ServerConfigFeatureFlags) String() string              { return FormatPacket(p) }
+func (p *ServerConfigFeatureFlags) LogValue() slog.Value        { return PacketLogValue(p) }
+func (p *ServerConfigKnownPacks) String() string                { return FormatPacket(p) }
+func (p *ServerConfigKnownPacks) LogValue() slog.Value          { return PacketLogValue(p) }
+func (p *ClientHandshake) String() string                       { return FormatPacket(p) }
+func (p *ClientHandshake) LogValue() slog.Value                 { return PacketLogValue(p) }
+func (p *ClientLoginStart) String() string                      { return FormatPacket(p) }
+func (p *ClientLoginStart) LogValue() slog.Value                { return PacketLogValue(p) }
+func (p *ClientEncryptionResponse) String() string              { return FormatPacket(p) }
+func (p *ClientEncryptionResponse) LogValue() slog.Value        { return PacketLogValue(p) }
+func (p *ClientLoginPluginResponse) String() string             { return FormatPacket(p) }
+func (p *ClientLoginPluginResponse) LogValue() slog.Value       { return PacketLogValue(p) }
+func (p *ClientLoginAcknowledged) String() string               { return FormatPacket(p) }
+func (p *ClientLoginAcknowledged) LogValue() slog.Value         { return PacketLogValue(p) }
+func (p *ServerLoginDisconnect) String() string                 { return FormatPacket(p) }
+func (p *ServerLoginDisconnect) LogValue() slog.Value           { return PacketLogValue(p) }
+func (p *ServerEncryptionRequest) String() string               { return FormatPacket(p) }
+func (p *ServerEncryptionRequest) LogValue() slog.Value         { return PacketLogValue(p) }
+func (p *ServerLoginSuccess) String() string                    { return FormatPacket(p) }
+func (p *ServerLoginSuccess) LogValue() slog.Value              { return PacketLogValue(p) }
+func (p *ServerLoginSetCompression) String() string             { return FormatPacket(p) }
+func (p *ServerLoginSetCompression) LogValue() slog.Value       { return PacketLogValue(p) }
+func (p *ServerLoginPluginRequest) String() string              { return FormatPacket(p) }
+func (p *ServerLoginPluginRequest) LogValue() slog.Value        { return PacketLogValue(p) }
+func (p *ClientPlayChat) String() string                        { return FormatPacket(p) }
+func (p *ClientPlayChat) LogValue() slog.Value                  { return PacketLogValue(p) }
+func (p *ClientConfigurationAck) String() string                { return FormatPacket(p) }
+func (p *ClientConfigurationAck) LogValue() slog.Value          { return PacketLogValue(p) }
+func (p *ServerStartConfiguration) String() string              { return FormatPacket(p) }
+func (p *ServerStartConfiguration) LogValue() slog.Value        { return PacketLogValue(p) }
+func (p *ServerPlayLogin) String() string                       { return FormatPacket(p) }
+func (p *ServerPlayLogin) LogValue() slog.Value                 { return PacketLogValue(p) }
+func (p *ServerPlayRespawn) String() string                     { return FormatPacket(p) }
+func (p *ServerPlayRespawn) LogValue() slog.Value               { return PacketLogValue(p) }
+func (p *ServerPlayPlayerInfoUpdate) String() string            { return FormatPacket(p) }
+func (p *ServerPlayPlayerInfoUpdate) LogValue() slog.Value      { return PacketLogValue(p) }
+func (p *ServerPlayPlayerInfoRemove) String() string            { return FormatPacket(p) }
+func (p *ServerPlayPlayerInfoRemove) LogValue() slog.Value      { return PacketLogValue(p) }
+func (p *ServerPlayBossBar) String() string                     { return FormatPacket(p) }
+func (p *ServerPlayBossBar) LogValue() slog.Value               { return PacketLogValue(p) }
+func (p *ServerPlaySetPlayerTeam) String() string               { return FormatPacket(p) }
+func (p *ServerPlaySetPlayerTeam) LogValue() slog.Value         { return PacketLogValue(p) }
+func (p *ServerPlaySetObjective) String() string                { return FormatPacket(p) }
+func (p *ServerPlaySetObjective) LogValue() slog.Value          { return PacketLogValue(p) }
+func (p *ServerPlaySetDisplayObjective) String() string         { return FormatPacket(p) }
+func (p *ServerPlaySetDisplayObjective) LogValue() slog.Value   { return PacketLogValue(p) }
+func (p *ServerPlayRemoveScore) String() string                 { return FormatPacket(p) }
+func (p *ServerPlayRemoveScore) LogValue() slog.Value           { return PacketLogValue(p) }
+func (p *ServerPlayRemoveEntities) String() string              { return FormatPacket(p) }
+func (p *ServerPlayRemoveEntities) LogValue() slog.Value        { return PacketLogValue(p) }
+func (p *ServerPlayBlockEntityData) String() string             { return FormatPacket(p) }
+func (p *ServerPlayBlockEntityData) LogValue() slog.Value       { return PacketLogValue(p) }
+func (p *ServerPlayContainerSetContent) String() string         { return FormatPacket(p) }
+func (p *ServerPlayContainerSetContent) LogValue() slog.Value   { return PacketLogValue(p) }
+func (p *ServerPlayContainerSetSlot) String() string            { return FormatPacket(p) }
+func (p *ServerPlayContainerSetSlot) LogValue() slog.Value      { return PacketLogValue(p) }
+func (p *ServerPlaySetEquipment) String() string                { return FormatPacket(p) }
+func (p *ServerPlaySetEquipment) LogValue() slog.Value          { return PacketLogValue(p) }
+func (p *ClientPlaySetCreativeModeSlot) String() string         { return FormatPacket(p) }
+func (p *ClientPlaySetCreativeModeSlot) LogValue() slog.Value   { return PacketLogValue(p) }
+func (p *ClientStatusRequest) String() string                   { return FormatPacket(p) }
+func (p *ClientStatusRequest) LogValue() slog.Value             { return PacketLogValue(p) }
+func (p *ClientStatusPingRequest) String() string               { return FormatPacket(p) }
+func (p *ClientStatusPingRequest) LogValue() slog.Value         { return PacketLogValue(p) }
+func (p *ServerStatusResponse) String() string                  { return FormatPacket(p) }
+func (p *ServerStatusResponse) LogValue() slog.Value            { return PacketLogValue(p) }
+func (p *ServerStatusPingResponse) String() string              { return FormatPacket(p) }
+func (p *ServerStatusPingResponse) LogValue() slog.Value        { return PacketLogValue(p) }
+
+var (
+	_ Packet = (*ClientKeepAlive)(nil)
+	_ Packet = (*ClientPluginMessage)(nil)
+	_ Packet = (*ClientPong)(nil)
+	_ Packet = (*ServerResourcePackPop)(nil)
+	_ Packet = (*ServerKeepAlive)(nil)
+	_ Packet = (*ServerPluginMessage)(nil)
+	_ Packet = (*ServerPing)(nil)
+	_ Packet = (*ServerPlayPlayerInfoRemove)(nil)
+	_ Packet = (*ServerPlayRemoveEntities)(nil)
+	_ Packet = (*ServerPlayBlockEntityData)(nil)
+)
@@ -9,7 +9,7 @@ import (
 )
 
 type ClientResourcePackStatus struct {
-	UUID   uuid.UUID
+	UUID   uuid.UUID // Since 1.20.3
 	Status ResourcePackStatus
 }
 
@@ -18,32 +18,115 @@ func (p *ClientResourcePackStatus) ID(state State) int {
 	return stateId2(state, Config, Play, ClientConfigResourcePackResponseID, ClientPlayResourcePackStatusID)
 }
 func (p *ClientResourcePackStatus) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ClientResourcePackStatus) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ClientResourcePackStatus) ReadVersion(r io.Reader, v Version) (err error) {
+	if v < Version1_20_3 {
+		p.Status, err = buffer.Enum[ResourcePackStatus]{}.Read(r)
+		return
+	}
 	p.UUID, p.Status, err = buffer.Read2(r, buffer.UUID, buffer.Enum[ResourcePackStatus]{})
 	return
 }
-func (p *ClientResourcePackStatus) Write(w io.Writer) (err error) {
+func (p *ClientResourcePackStatus) WriteVersion(w io.Writer, v Version) (err error) {
+	if v < Version1_20_3 {
+		return buffer.Enum[ResourcePackStatus]{}.Write(w, p.Status)
+	}
 	return buffer.Write2(w, buffer.UUID, p.UUID, buffer.Enum[ResourcePackStatus]{}, p.Status)
 }
 
-type ClientPluginMessage struct {
-	Channel string
-	Data    []byte
+type ClientInformation struct {
+	Locale              string
+	ViewDistance        byte
+	ChatMode            ChatMode
+	ChatColors          bool
+	DisplayedSkinParts  byte // Bit mask
+	MainHand            MainHand
+	EnableTextFiltering bool
+	AllowServerListings bool
+	ParticleStatus      ParticleStatus // Since 1.21.2
 }
 
-func (p *ClientPluginMessage) Direction() Direction { return Serverbound }
-func (p *ClientPluginMessage) ID(state State) int {
-	return stateId2(state, Config, Play, ClientConfigPluginMessageID, ClientPlayPluginMessageID)
+func (p *ClientInformation) Direction() Direction { return Serverbound }
+func (p *ClientInformation) ID(state State) int {
+	return stateId2(state, Config, Play, ClientConfigClientInformationID, ClientPlayClientSettingsID)
 }
-func (p *ClientPluginMessage) Read(r io.Reader) (err error) {
-	p.Channel, p.Data, err = buffer.Read2(r, buffer.String, buffer.RawBytes)
+func (p *ClientInformation) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ClientInformation) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ClientInformation) ReadVersion(r io.Reader, v Version) (err error) {
+	p.Locale, p.ViewDistance, p.ChatMode, p.ChatColors, p.DisplayedSkinParts, p.MainHand,
+		p.EnableTextFiltering, p.AllowServerListings, err = buffer.Read8(r,
+		buffer.String, buffer.Byte, buffer.Enum[ChatMode]{}, buffer.Bool, buffer.Byte,
+		buffer.Enum[MainHand]{}, buffer.Bool, buffer.Bool)
+	if err != nil || v < Version1_21_2 {
+		return
+	}
+	p.ParticleStatus, err = buffer.Enum[ParticleStatus]{}.Read(r)
 	return
 }
-func (p *ClientPluginMessage) Write(w io.Writer) (err error) {
-	return buffer.Write2(w, buffer.String, p.Channel, buffer.RawBytes, p.Data)
+func (p *ClientInformation) WriteVersion(w io.Writer, v Version) (err error) {
+	err = buffer.Write8(w, buffer.String, p.Locale, buffer.Byte, p.ViewDistance,
+		buffer.Enum[ChatMode]{}, p.ChatMode, buffer.Bool, p.ChatColors, buffer.Byte, p.DisplayedSkinParts,
+		buffer.Enum[MainHand]{}, p.MainHand, buffer.Bool, p.EnableTextFiltering, buffer.Bool, p.AllowServerListings)
+	if err != nil || v < Version1_21_2 {
+		return
+	}
+	return buffer.Enum[ParticleStatus]{}.Write(w, p.ParticleStatus)
+}
+
+type ClientCookieResponse struct {
+	Key     string
+	Payload []byte // nil if the cookie is not stored
+}
+
+func (p *ClientCookieResponse) Direction() Direction { return Serverbound }
+func (p *ClientCookieResponse) ID(state State) int {
+	return stateId3(state, Login, Config, Play,
+		ClientLoginCookieResponseID, ClientConfigCookieResponseID, ClientPlayCookieResponseID)
+}
+func (p *ClientCookieResponse) Read(r io.Reader) (err error) {
+	if p.Key, err = buffer.String.Read(r); err != nil {
+		return
+	}
+	var present bool
+	if present, err = buffer.Bool.Read(r); err != nil || !present {
+		return
+	}
+	p.Payload, err = buffer.ByteArray.Read(r)
+	return
+}
+func (p *ClientCookieResponse) Write(w io.Writer) (err error) {
+	if err = buffer.Write2(w, buffer.String, p.Key, buffer.Bool, p.Payload != nil); err != nil || p.Payload == nil {
+		return
+	}
+	return buffer.ByteArray.Write(w, p.Payload)
+}
+
+//kite:packet Config=ClientConfigKeepAliveID Play=ClientPlayKeepAliveID
+type ClientKeepAlive struct {
+	KeepAliveID int64
+}
+
+//kite:packet Config=ClientConfigPluginMessageID Play=ClientPlayPluginMessageID
+type ClientPluginMessage struct {
+	Channel string
+	Data    []byte `mc:"rawbytes"`
+}
+
+//kite:packet Config=ClientConfigPongID Play=ClientPlayPongID
+type ClientPong struct {
+	PingID int32 `mc:"int"`
 }
 
 type ServerResourcePackPush struct {
-	Id     string
+	Id     string // Since 1.20.3
 	Url    string
 	Hash   string
 	Forced bool
@@ -55,48 +138,46 @@ func (p *ServerResourcePackPush) ID(state State) int {
 	return stateId2(state, Config, Play, ServerConfigAddResourcePackID, ServerPlayResourcePackPushID)
 }
 func (p *ServerResourcePackPush) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerResourcePackPush) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerResourcePackPush) ReadVersion(r io.Reader, v Version) (err error) {
+	if v < Version1_20_3 {
+		p.Url, p.Hash, p.Forced, p.Prompt, err = buffer.Read4(r,
+			buffer.String, buffer.String, buffer.Bool, buffer.Opt(buffer.TextComponentJSON))
+		return
+	}
 	p.Id, p.Url, p.Hash, p.Forced, p.Prompt, err = buffer.Read5(r,
 		buffer.String, buffer.String, buffer.String,
 		buffer.Bool, buffer.Opt(buffer.TextComponent))
 	return
 }
-func (p *ServerResourcePackPush) Write(w io.Writer) (err error) {
+func (p *ServerResourcePackPush) WriteVersion(w io.Writer, v Version) (err error) {
+	if v < Version1_20_3 {
+		return buffer.Write4(w, buffer.String, p.Url, buffer.String, p.Hash,
+			buffer.Bool, p.Forced, buffer.Opt(buffer.TextComponentJSON), p.Prompt)
+	}
 	return buffer.Write5(w,
 		buffer.String, p.Id, buffer.String, p.Url, buffer.String, p.Hash,
 		buffer.Bool, p.Forced, buffer.Opt(buffer.TextComponent), p.Prompt)
 }
 
+//kite:packet Config=ServerConfigRemoveResourcePackID Play=ServerPlayResourcePackPopID
 type ServerResourcePackPop struct {
-	Id uuid.UUID // Optional
+	Id uuid.UUID `mc:"opt"` // Every pack is removed if not set
 }
 
-func (p *ServerResourcePackPop) Direction() Direction { return Clientbound }
-func (p *ServerResourcePackPop) ID(state State) int {
-	return stateId2(state, Config, Play, ServerConfigRemoveResourcePackID, ServerPlayResourcePackPopID)
-}
-func (p *ServerResourcePackPop) Read(r io.Reader) (err error) {
-	p.Id, err = buffer.Opt(buffer.UUID).Read(r)
-	return
-}
-func (p *ServerResourcePackPop) Write(w io.Writer) (err error) {
-	return buffer.Opt(buffer.UUID).Write(w, p.Id)
+//kite:packet Config=ServerConfigKeepAliveID Play=ServerPlayKeepAliveID
+type ServerKeepAlive struct {
+	KeepAliveID int64
 }
 
+//kite:packet Config=ServerConfigPluginMessageID Play=ServerPlayPluginMessageID
 type ServerPluginMessage struct {
 	Channel string
-	Data    []byte
-}
-
-func (p *ServerPluginMessage) Direction() Direction { return Clientbound }
-func (p *ServerPluginMessage) ID(state State) int {
-	return stateId2(state, Config, Play, ServerConfigPluginMessageID, ServerPlayPluginMessageID)
-}
-func (p *ServerPluginMessage) Read(r io.Reader) (err error) {
-	p.Channel, p.Data, err = buffer.Read2(r, buffer.String, buffer.RawBytes)
-	return
-}
-func (p *ServerPluginMessage) Write(w io.Writer) (err error) {
-	return buffer.Write2(w, buffer.String, p.Channel, buffer.RawBytes, p.Data)
+	Data    []byte `mc:"rawbytes"`
 }
 
 type ServerDisconnect struct {
@@ -108,19 +189,188 @@ func (p *ServerDisconnect) ID(state State) int {
 	return stateId2(state, Config, Play, ServerConfigDisconnectID, ServerPlayDisconnectID)
 }
 func (p *ServerDisconnect) Read(r io.Reader) (err error) {
-	p.Reason, err = buffer.TextComponent.Read(r)
-	return
+	return p.ReadVersion(r, LatestVersion)
 }
 func (p *ServerDisconnect) Write(w io.Writer) (err error) {
-	return buffer.TextComponent.Write(w, p.Reason)
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerDisconnect) ReadVersion(r io.Reader, v Version) (err error) {
+	p.Reason, err = textComponent(v).Read(r)
+	return
+}
+func (p *ServerDisconnect) WriteVersion(w io.Writer, v Version) (err error) {
+	return textComponent(v).Write(w, p.Reason)
+}
+
+type ServerCookieRequest struct {
+	Key string
+}
+
+func (p *ServerCookieRequest) Direction() Direction { return Clientbound }
+func (p *ServerCookieRequest) ID(state State) int {
+	return stateId3(state, Login, Config, Play,
+		ServerLoginCookieRequestID, ServerConfigCookieRequestID, ServerPlayCookieRequestID)
+}
+func (p *ServerCookieRequest) Read(r io.Reader) (err error) {
+	p.Key, err = buffer.String.Read(r)
+	return
+}
+func (p *ServerCookieRequest) Write(w io.Writer) (err error) {
+	return buffer.String.Write(w, p.Key)
+}
+
+//kite:packet Config=ServerConfigPingID Play=ServerPlayPingID
+type ServerPing struct {
+	PingID int32 `mc:"int"`
+}
+
+type ServerStoreCookie struct {
+	Key     string
+	Payload []byte
+}
+
+func (p *ServerStoreCookie) Direction() Direction { return Clientbound }
+func (p *ServerStoreCookie) ID(state State) int {
+	return stateId2(state, Config, Play, ServerConfigStoreCookieID, ServerPlayStoreCookieID)
+}
+func (p *ServerStoreCookie) Read(r io.Reader) (err error) {
+	return buffer.ReadStruct(r, p)
+}
+func (p *ServerStoreCookie) Write(w io.Writer) (err error) {
+	return buffer.WriteStruct(w, p)
+}
+
+type ServerTransfer struct {
+	Host string
+	Port int32
+}
+
+func (p *ServerTransfer) Direction() Direction { return Clientbound }
+func (p *ServerTransfer) ID(state State) int {
+	return stateId2(state, Config, Play, ServerConfigTransferID, ServerPlayTransferID)
+}
+func (p *ServerTransfer) Read(r io.Reader) (err error) {
+	return buffer.ReadStruct(r, p)
+}
+func (p *ServerTransfer) Write(w io.Writer) (err error) {
+	return buffer.WriteStruct(w, p)
+}
+
+type ServerUpdateTags struct {
+	Registries []TagRegistry
+}
+
+// A TagRegistry holds the tags of a single registry, such as minecraft:block.
+type TagRegistry struct {
+	Registry string
+	Tags     []Tag
+}
+
+// A Tag is a named set of registry entries, referenced by their numeric IDs.
+type Tag struct {
+	Name    string
+	Entries []int32
+}
+
+func (p *ServerUpdateTags) Direction() Direction { return Clientbound }
+func (p *ServerUpdateTags) ID(state State) int {
+	return stateId2(state, Config, Play, ServerConfigUpdateTagsID, ServerPlayUpdateTagsID)
+}
+func (p *ServerUpdateTags) Read(r io.Reader) (err error) {
+	return buffer.ReadStruct(r, p)
+}
+func (p *ServerUpdateTags) Write(w io.Writer) (err error) {
+	return buffer.WriteStruct(w, p)
+}
+
+type ServerCustomReportDetails struct {
+	Details []ReportDetail
+}
+
+// A ReportDetail is included by the client in crash reports and bug reports.
+type ReportDetail struct {
+	Title       string
+	Description string
+}
+
+func (p *ServerCustomReportDetails) Direction() Direction { return Clientbound }
+func (p *ServerCustomReportDetails) ID(state State) int {
+	return stateId2(state, Config, Play, ServerConfigCustomReportDetailsID, ServerPlayCustomReportDetailsID)
+}
+func (p *ServerCustomReportDetails) Read(r io.Reader) (err error) {
+	return buffer.ReadStruct(r, p)
+}
+func (p *ServerCustomReportDetails) Write(w io.Writer) (err error) {
+	return buffer.WriteStruct(w, p)
+}
+
+type ServerLinks struct {
+	Links []ServerLink
+}
+
+// A ServerLink is shown in the pause menu of the client.
+type ServerLink struct {
+	BuiltinLabel ServerLinkLabel
+	Label        text.Component // Replaces BuiltinLabel if set
+	URL          string
+}
+
+func (p *ServerLinks) Direction() Direction { return Clientbound }
+func (p *ServerLinks) ID(state State) int {
+	return stateId2(state, Config, Play, ServerConfigServerLinksID, ServerPlayServerLinksID)
+}
+func (p *ServerLinks) Read(r io.Reader) (err error) {
+	p.Links, err = buffer.ReadList(r, func() (link ServerLink, err error) {
+		var builtin bool
+		if builtin, err = buffer.Bool.Read(r); err != nil {
+			return
+		}
+		if builtin {
+			link.BuiltinLabel, err = buffer.Enum[ServerLinkLabel]{}.Read(r)
+		} else {
+			link.Label, err = buffer.TextComponent.Read(r)
+		}
+		if err != nil {
+			return
+		}
+		link.URL, err = buffer.String.Read(r)
+		return
+	})
+	return
+}
+func (p *ServerLinks) Write(w io.Writer) (err error) {
+	return buffer.WriteList(w, p.Links, func(link ServerLink) (err error) {
+		if link.Label == nil {
+			err = buffer.Write2(w, buffer.Bool, true, buffer.Enum[ServerLinkLabel]{}, link.BuiltinLabel)
+		} else {
+			err = buffer.Write2(w, buffer.Bool, false, buffer.TextComponent, link.Label)
+		}
+		if err != nil {
+			return
+		}
+		return buffer.String.Write(w, link.URL)
+	})
+}
+
+// textComponent returns the type used for text components in version v, which are sent as NBT since 1.20.3.
+func textComponent(v Version) buffer.Type[text.Component] {
+	if v < Version1_20_3 {
+		return buffer.TextComponentJSON
+	}
+	return buffer.TextComponent
 }
 
 var (
-	_ Packet = (*ClientResourcePackStatus)(nil)
-	_ Packet = (*ClientPluginMessage)(nil)
+	_ VersionedPacket = (*ClientInformation)(nil)
+	_ Packet          = (*ClientCookieResponse)(nil)
+	_ VersionedPacket = (*ClientResourcePackStatus)(nil)
 
-	_ Packet = (*ServerResourcePackPush)(nil)
-	_ Packet = (*ServerResourcePackPop)(nil)
-	_ Packet = (*ServerPluginMessage)(nil)
-	_ Packet = (*ServerDisconnect)(nil)
+	_ VersionedPacket = (*ServerResourcePackPush)(nil)
+	_ VersionedPacket = (*ServerDisconnect)(nil)
+	_ Packet          = (*ServerCookieRequest)(nil)
+	_ Packet          = (*ServerStoreCookie)(nil)
+	_ Packet          = (*ServerTransfer)(nil)
+	_ Packet          = (*ServerUpdateTags)(nil)
+	_ Packet          = (*ServerCustomReportDetails)(nil)
+	_ Packet          = (*ServerLinks)(nil)
 )
@@ -0,0 +1,303 @@
+package packet
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/nbt"
+)
+
+// Data component values are not length prefixed, so finding the end of one requires knowing its structure.
+// A skipper describes that structure just enough to read past a value without decoding it.
+type skipper interface {
+	skip(r io.Reader, v Version) error
+}
+
+type (
+	skipFixed  int      // A fixed number of bytes
+	skipVarInt struct{} // A VarInt
+	skipString struct{} // A String or Identifier
+	skipNBT    struct{} // A network NBT tag, also used for text components
+	skipSeq    []skipper
+
+	skipList   struct{ elem skipper }        // A VarInt length followed by elements
+	skipOpt    struct{ value skipper }       // A Bool followed by the value if true
+	skipEither struct{ left, right skipper } // A Bool followed by left if true, or right if false
+	skipHolder struct{ inline skipper }      // A registry ID plus one, or 0 followed by an inline value
+	skipSince  struct {                      // A value which changed in a version
+		version       Version
+		before, since skipper
+	}
+
+	skipIDSet         struct{} // A tag name, or a list of registry IDs
+	skipItemStack     struct{}
+	skipEffectDetails struct{}
+	skipConsumeEffect struct{}
+)
+
+func (s skipFixed) skip(r io.Reader, _ Version) error {
+	var scratch [16]byte
+	_, err := io.ReadFull(r, scratch[:s])
+	return err
+}
+
+func (skipVarInt) skip(r io.Reader, _ Version) error {
+	_, err := buffer.VarInt.Read(r)
+	return err
+}
+
+func (skipString) skip(r io.Reader, _ Version) error {
+	length, err := buffer.VarInt.Read(r)
+	if err != nil {
+		return err
+	}
+	if length < 0 {
+		return fmt.Errorf("negative string length: %d", length)
+	}
+	return discard(r, int64(length))
+}
+
+func (skipNBT) skip(r io.Reader, _ Version) error {
+	return nbt.SkipNetwork(r)
+}
+
+func (s skipSeq) skip(r io.Reader, v Version) error {
+	for _, elem := range s {
+		if err := elem.skip(r, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s skipList) skip(r io.Reader, v Version) error {
+	length, err := buffer.VarInt.Read(r)
+	if err != nil {
+		return err
+	}
+	if length < 0 {
+		return fmt.Errorf("negative list length: %d", length)
+	}
+	for range length {
+		if err = s.elem.skip(r, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s skipOpt) skip(r io.Reader, v Version) error {
+	present, err := buffer.Bool.Read(r)
+	if err != nil || !present {
+		return err
+	}
+	return s.value.skip(r, v)
+}
+
+func (s skipEither) skip(r io.Reader, v Version) error {
+	left, err := buffer.Bool.Read(r)
+	if err != nil {
+		return err
+	}
+	if left {
+		return s.left.skip(r, v)
+	}
+	return s.right.skip(r, v)
+}
+
+func (s skipHolder) skip(r io.Reader, v Version) error {
+	id, err := buffer.VarInt.Read(r)
+	if err != nil || id != 0 {
+		return err
+	}
+	return s.inline.skip(r, v)
+}
+
+func (s skipSince) skip(r io.Reader, v Version) error {
+	if v >= s.version {
+		return s.since.skip(r, v)
+	}
+	return s.before.skip(r, v)
+}
+
+func (skipIDSet) skip(r io.Reader, v Version) error {
+	length, err := buffer.VarInt.Read(r)
+	if err != nil {
+		return err
+	}
+	if length < 0 {
+		return fmt.Errorf("negative id set length: %d", length)
+	}
+	if length == 0 {
+		return skipString{}.skip(r, v)
+	}
+	for range length - 1 {
+		if _, err = buffer.VarInt.Read(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (skipItemStack) skip(r io.Reader, v Version) error {
+	var stack ItemStack
+	return stack.read(r, v)
+}
+
+func (skipEffectDetails) skip(r io.Reader, v Version) error {
+	// Amplifier, duration, ambient, show particles, show icon and the optional hidden effect.
+	if err := (skipSeq{skipVarInt{}, skipVarInt{}, skipBool, skipBool, skipBool}).skip(r, v); err != nil {
+		return err
+	}
+	return skipOpt{skipEffectDetails{}}.skip(r, v)
+}
+
+func (skipConsumeEffect) skip(r io.Reader, v Version) error {
+	effectType, err := buffer.VarInt.Read(r)
+	if err != nil {
+		return err
+	}
+	switch effectType {
+	case 0: // minecraft:apply_effects
+		return skipSeq{skipList{skipEffect}, skipFloat}.skip(r, v)
+	case 1: // minecraft:remove_effects
+		return skipIDSet{}.skip(r, v)
+	case 2: // minecraft:clear_all_effects
+		return nil
+	case 3: // minecraft:teleport_randomly
+		return skipFloat.skip(r, v)
+	case 4: // minecraft:play_sound
+		return skipSoundEvent.skip(r, v)
+	}
+	return fmt.Errorf("unknown consume effect type: %d", effectType)
+}
+
+func discard(r io.Reader, n int64) error {
+	copied, err := io.CopyN(io.Discard, r, n)
+	if err == io.EOF && copied < n {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+var (
+	skipBool     = skipFixed(1)
+	skipInt      = skipFixed(4)
+	skipFloat    = skipFixed(4)
+	skipDouble   = skipFixed(8)
+	skipUUID     = skipFixed(16)
+	skipPosition = skipFixed(8)
+
+	skipSoundEvent   = skipHolder{skipSeq{skipString{}, skipOpt{skipFloat}}} // Location and fixed range
+	skipEffect       = skipSeq{skipVarInt{}, skipEffectDetails{}}
+	skipEnchantments = skipSeq{skipList{skipSeq{skipVarInt{}, skipVarInt{}}}, skipBool}
+
+	// A block predicate matches blocks, optional block state properties (each matching an exact value or an
+	// optional range) and optional block entity data.
+	skipBlockPredicates = skipSeq{skipList{skipSeq{
+		skipOpt{skipIDSet{}},
+		skipOpt{skipList{skipSeq{skipString{}, skipEither{skipString{}, skipSeq{skipOpt{skipString{}}, skipOpt{skipString{}}}}}}},
+		skipOpt{skipNBT{}},
+	}}, skipBool}
+
+	skipFireworkExplosion = skipSeq{skipVarInt{}, skipList{skipInt}, skipList{skipInt}, skipBool, skipBool}
+)
+
+// dataComponentSkippers describes the value of every data component type, by name.
+var dataComponentSkippers = map[string]skipper{
+	"custom_data":    skipNBT{},
+	"max_stack_size": skipVarInt{},
+	"max_damage":     skipVarInt{},
+	"damage":         skipVarInt{},
+	"unbreakable":    skipBool,
+	"custom_name":    skipNBT{},
+	"item_name":      skipNBT{},
+	"item_model":     skipString{},
+	"lore":           skipList{skipNBT{}},
+	"rarity":         skipVarInt{},
+	"enchantments":   skipEnchantments,
+	"can_place_on":   skipBlockPredicates,
+	"can_break":      skipBlockPredicates,
+	"attribute_modifiers": skipSeq{skipList{skipSeq{
+		skipVarInt{},
+		skipSince{Version1_21, skipSeq{skipUUID, skipString{}}, skipString{}}, // Modifier ID
+		skipDouble, skipVarInt{}, skipVarInt{}, // Amount, operation and slot group
+	}}, skipBool},
+	"custom_model_data":          skipVarInt{},
+	"hide_additional_tooltip":    skipSeq{},
+	"hide_tooltip":               skipSeq{},
+	"repair_cost":                skipVarInt{},
+	"creative_slot_lock":         skipSeq{},
+	"enchantment_glint_override": skipBool,
+	"intangible_projectile":      skipNBT{},
+	"food": skipSince{Version1_21_2,
+		skipSeq{skipVarInt{}, skipFloat, skipBool, skipFloat,
+			skipSince{Version1_21, skipSeq{}, skipOpt{skipItemStack{}}}, // Using converts to
+			skipList{skipSeq{skipEffect, skipFloat}}},
+		skipSeq{skipVarInt{}, skipFloat, skipBool}},
+	"consumable":       skipSeq{skipFloat, skipVarInt{}, skipSoundEvent, skipBool, skipList{skipConsumeEffect{}}},
+	"use_remainder":    skipItemStack{},
+	"use_cooldown":     skipSeq{skipFloat, skipOpt{skipString{}}},
+	"damage_resistant": skipString{},
+	"fire_resistant":   skipSeq{},
+	"tool": skipSeq{
+		skipList{skipSeq{skipIDSet{}, skipOpt{skipFloat}, skipOpt{skipBool}}},
+		skipFloat, skipVarInt{},
+	},
+	"enchantable": skipVarInt{},
+	"equippable": skipSeq{skipVarInt{}, skipSoundEvent, skipOpt{skipString{}}, skipOpt{skipString{}},
+		skipOpt{skipIDSet{}}, skipBool, skipBool, skipBool},
+	"repairable":          skipIDSet{},
+	"glider":              skipSeq{},
+	"tooltip_style":       skipString{},
+	"death_protection":    skipList{skipConsumeEffect{}},
+	"stored_enchantments": skipEnchantments,
+	"dyed_color":          skipSeq{skipInt, skipBool},
+	"map_color":           skipInt,
+	"map_id":              skipVarInt{},
+	"map_decorations":     skipNBT{},
+	"map_post_processing": skipVarInt{},
+	"charged_projectiles": skipList{skipItemStack{}},
+	"bundle_contents":     skipList{skipItemStack{}},
+	"potion_contents": skipSeq{skipOpt{skipVarInt{}}, skipOpt{skipInt}, skipList{skipEffect},
+		skipSince{Version1_21_2, skipSeq{}, skipOpt{skipString{}}}}, // Custom name
+	"suspicious_stew_effects": skipList{skipSeq{skipVarInt{}, skipVarInt{}}},
+	"writable_book_content":   skipList{skipSeq{skipString{}, skipOpt{skipString{}}}},
+	"written_book_content": skipSeq{skipString{}, skipOpt{skipString{}}, skipString{}, skipVarInt{},
+		skipList{skipSeq{skipNBT{}, skipOpt{skipNBT{}}}}, skipBool},
+	"trim": skipSeq{
+		skipHolder{skipSeq{skipString{}, skipVarInt{}, skipFloat,
+			skipList{skipSeq{skipSince{Version1_21_2, skipVarInt{}, skipString{}}, skipString{}}}, // Overrides
+			skipNBT{}}},
+		skipHolder{skipSeq{skipString{}, skipVarInt{}, skipNBT{}, skipBool}},
+		skipBool,
+	},
+	"debug_stick_state":  skipNBT{},
+	"entity_data":        skipNBT{},
+	"bucket_entity_data": skipNBT{},
+	"block_entity_data":  skipNBT{},
+	"instrument": skipHolder{skipSince{Version1_21_2,
+		skipSeq{skipSoundEvent, skipVarInt{}, skipFloat},
+		skipSeq{skipSoundEvent, skipFloat, skipFloat, skipNBT{}}}},
+	"ominous_bottle_amplifier": skipVarInt{},
+	"jukebox_playable": skipSeq{
+		skipEither{skipHolder{skipSeq{skipSoundEvent, skipNBT{}, skipFloat, skipVarInt{}}}, skipString{}},
+		skipBool,
+	},
+	"recipes":            skipNBT{},
+	"lodestone_tracker":  skipSeq{skipOpt{skipSeq{skipString{}, skipPosition}}, skipBool},
+	"firework_explosion": skipFireworkExplosion,
+	"fireworks":          skipSeq{skipVarInt{}, skipList{skipFireworkExplosion}},
+	"profile": skipSeq{skipOpt{skipString{}}, skipOpt{skipUUID},
+		skipList{skipSeq{skipString{}, skipString{}, skipOpt{skipString{}}}}},
+	"note_block_sound": skipString{},
+	"banner_patterns":  skipList{skipSeq{skipHolder{skipSeq{skipString{}, skipString{}}}, skipVarInt{}}},
+	"base_color":       skipVarInt{},
+	"pot_decorations":  skipList{skipVarInt{}},
+	"container":        skipList{skipItemStack{}},
+	"block_state":      skipList{skipSeq{skipString{}, skipString{}}},
+	"bees":             skipList{skipSeq{skipNBT{}, skipVarInt{}, skipVarInt{}}},
+	"lock":             skipNBT{},
+	"container_loot":   skipNBT{},
+}
@@ -2,17 +2,8 @@ package packet
 
 import (
 	"io"
-)
 
-const (
-	ClientConfigClientInformationID = iota
-	ClientConfigCookieResponseID
-	ClientConfigPluginMessageID
-	ClientConfigFinishConfigurationID
-	ClientConfigKeepAliveID
-	ClientConfigPongID
-	ClientConfigResourcePackResponseID
-	ClientConfigKnownPacksID
+	"github.com/mworzala/kite/pkg/buffer"
 )
 
 type ClientConfigFinishConfiguration struct{}
@@ -28,26 +19,148 @@ func (p *ClientConfigFinishConfiguration) Write(_ io.Writer) (err error) {
 	return nil
 }
 
-const (
-	ServerConfigCookieRequestID = iota
-	ServerConfigPluginMessageID
-	ServerConfigDisconnectID
-	ServerConfigFinishConfigurationID
-	ServerConfigKeepAliveID
-	ServerConfigPingID
-	ServerConfigResetChatID
-	ServerConfigRegistryDataID
-	ServerConfigRemoveResourcePackID
-	ServerConfigAddResourcePackID
-	ServerConfigStoreCookieID
-	ServerConfigTransferID
-	ServerConfigFeatureFlagsID
-	ServerConfigUpdateTagsID
-	ServerConfigKnownPacksID
-)
+type ClientConfigKnownPacks struct {
+	Packs []KnownPack
+}
+
+func (p *ClientConfigKnownPacks) Direction() Direction { return Serverbound }
+func (p *ClientConfigKnownPacks) ID(state State) int {
+	return stateId1(state, Config, ClientConfigKnownPacksID)
+}
+func (p *ClientConfigKnownPacks) Read(r io.Reader) (err error) {
+	return buffer.ReadStruct(r, p)
+}
+func (p *ClientConfigKnownPacks) Write(w io.Writer) (err error) {
+	return buffer.WriteStruct(w, p)
+}
+
+type ServerConfigFinishConfiguration struct{}
+
+func (p *ServerConfigFinishConfiguration) Direction() Direction { return Clientbound }
+func (p *ServerConfigFinishConfiguration) ID(state State) int {
+	return stateId1(state, Config, ServerConfigFinishConfigurationID)
+}
+func (p *ServerConfigFinishConfiguration) Read(_ io.Reader) (err error) {
+	return nil
+}
+func (p *ServerConfigFinishConfiguration) Write(_ io.Writer) (err error) {
+	return nil
+}
+
+type ServerConfigResetChat struct{}
+
+func (p *ServerConfigResetChat) Direction() Direction { return Clientbound }
+func (p *ServerConfigResetChat) ID(state State) int {
+	return stateId1(state, Config, ServerConfigResetChatID)
+}
+func (p *ServerConfigResetChat) Read(_ io.Reader) (err error) {
+	return nil
+}
+func (p *ServerConfigResetChat) Write(_ io.Writer) (err error) {
+	return nil
+}
+
+// ServerConfigRegistryData sends the entries of a single registry since 1.20.5. Before, every registry
+// was sent at once as a single NBT compound in Codec.
+type ServerConfigRegistryData struct {
+	RegistryID string // Since 1.20.5
+	Entries    []RegistryEntry
+	Codec      []byte // Before 1.20.5
+}
+
+// A RegistryEntry is the data of a single registry entry, as network format NBT.
+type RegistryEntry struct {
+	ID   string
+	Data []byte // nil if the client has the entry from a known pack
+}
+
+func (p *ServerConfigRegistryData) Direction() Direction { return Clientbound }
+func (p *ServerConfigRegistryData) ID(state State) int {
+	return stateId1(state, Config, ServerConfigRegistryDataID)
+}
+func (p *ServerConfigRegistryData) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerConfigRegistryData) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerConfigRegistryData) ReadVersion(r io.Reader, v Version) (err error) {
+	if v < Version1_20_5 {
+		p.Codec, err = buffer.RawNBT.Read(r)
+		return
+	}
+	if p.RegistryID, err = buffer.String.Read(r); err != nil {
+		return
+	}
+	p.Entries, err = buffer.ReadList(r, func() (entry RegistryEntry, err error) {
+		var present bool
+		if entry.ID, present, err = buffer.Read2(r, buffer.String, buffer.Bool); err != nil || !present {
+			return
+		}
+		entry.Data, err = buffer.RawNBT.Read(r)
+		return
+	})
+	return
+}
+func (p *ServerConfigRegistryData) WriteVersion(w io.Writer, v Version) (err error) {
+	if v < Version1_20_5 {
+		return buffer.RawNBT.Write(w, p.Codec)
+	}
+	if err = buffer.String.Write(w, p.RegistryID); err != nil {
+		return
+	}
+	return buffer.WriteList(w, p.Entries, func(entry RegistryEntry) error {
+		if err := buffer.Write2(w, buffer.String, entry.ID, buffer.Bool, entry.Data != nil); err != nil || entry.Data == nil {
+			return err
+		}
+		return buffer.RawNBT.Write(w, entry.Data)
+	})
+}
+
+type ServerConfigFeatureFlags struct {
+	Features []string
+}
+
+func (p *ServerConfigFeatureFlags) Direction() Direction { return Clientbound }
+func (p *ServerConfigFeatureFlags) ID(state State) int {
+	return stateId1(state, Config, ServerConfigFeatureFlagsID)
+}
+func (p *ServerConfigFeatureFlags) Read(r io.Reader) (err error) {
+	return buffer.ReadStruct(r, p)
+}
+func (p *ServerConfigFeatureFlags) Write(w io.Writer) (err error) {
+	return buffer.WriteStruct(w, p)
+}
+
+type ServerConfigKnownPacks struct {
+	Packs []KnownPack
+}
+
+func (p *ServerConfigKnownPacks) Direction() Direction { return Clientbound }
+func (p *ServerConfigKnownPacks) ID(state State) int {
+	return stateId1(state, Config, ServerConfigKnownPacksID)
+}
+func (p *ServerConfigKnownPacks) Read(r io.Reader) (err error) {
+	return buffer.ReadStruct(r, p)
+}
+func (p *ServerConfigKnownPacks) Write(w io.Writer) (err error) {
+	return buffer.WriteStruct(w, p)
+}
+
+// A KnownPack is a data pack whose registry entries need not be sent, as both sides already have them.
+type KnownPack struct {
+	Namespace string
+	ID        string
+	Version   string
+}
 
 var (
 	_ Packet = (*ClientConfigFinishConfiguration)(nil)
+	_ Packet = (*ClientConfigKnownPacks)(nil)
 
-	//_ Packet = (*ServerConfigPluginMessage)(nil)
+	_ Packet          = (*ServerConfigFinishConfiguration)(nil)
+	_ Packet          = (*ServerConfigResetChat)(nil)
+	_ VersionedPacket = (*ServerConfigRegistryData)(nil)
+	_ Packet          = (*ServerConfigFeatureFlags)(nil)
+	_ Packet          = (*ServerConfigKnownPacks)(nil)
 )
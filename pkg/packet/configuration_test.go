@@ -0,0 +1,103 @@
+package packet
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/text"
+	"github.com/stretchr/testify/require"
+)
+
+// testNBT is a network format compound containing the byte tag a=1.
+var testNBT = []byte{0x0a, 0x01, 0x00, 0x01, 'a', 0x01, 0x00}
+
+func TestConfigPackets(t *testing.T) {
+	for _, pkt := range []Packet{
+		&ClientInformation{
+			Locale: "en_us", ViewDistance: 12, ChatMode: ChatModeCommandsOnly, ChatColors: true,
+			DisplayedSkinParts: 0x7f, MainHand: MainHandRight, AllowServerListings: true,
+			ParticleStatus: ParticleStatusMinimal,
+		},
+		&ClientCookieResponse{Key: "kite:cookie", Payload: []byte{1, 2, 3}},
+		&ClientCookieResponse{Key: "kite:missing"},
+		&ClientConfigFinishConfiguration{},
+		&ClientKeepAlive{KeepAliveID: 42},
+		&ClientPong{PingID: -7},
+		&ClientResourcePackStatus{UUID: uuid.New(), Status: ResourcePackDownloaded},
+		&ClientConfigKnownPacks{Packs: []KnownPack{{"minecraft", "core", "1.21.2"}}},
+		&ClientPluginMessage{Channel: "minecraft:brand", Data: []byte("kite")},
+
+		&ServerCookieRequest{Key: "kite:cookie"},
+		&ServerPluginMessage{Channel: "minecraft:brand", Data: []byte("kite")},
+		&ServerDisconnect{Reason: &text.Text{Text: "bye"}},
+		&ServerConfigFinishConfiguration{},
+		&ServerKeepAlive{KeepAliveID: 42},
+		&ServerPing{PingID: 7},
+		&ServerConfigResetChat{},
+		&ServerConfigRegistryData{RegistryID: "minecraft:dimension_type", Entries: []RegistryEntry{
+			{ID: "minecraft:overworld", Data: testNBT},
+			{ID: "minecraft:the_nether"},
+		}},
+		&ServerResourcePackPop{Id: uuid.New()},
+		&ServerResourcePackPush{Id: "pack", Url: "https://example.com/pack.zip", Hash: "abc", Forced: true},
+		&ServerStoreCookie{Key: "kite:cookie", Payload: []byte{1, 2, 3}},
+		&ServerTransfer{Host: "example.com", Port: 25565},
+		&ServerConfigFeatureFlags{Features: []string{"minecraft:vanilla", "minecraft:bundle"}},
+		&ServerUpdateTags{Registries: []TagRegistry{
+			{Registry: "minecraft:block", Tags: []Tag{{Name: "minecraft:logs", Entries: []int32{1, 2, 300}}}},
+			{Registry: "minecraft:item", Tags: []Tag{}},
+		}},
+		&ServerConfigKnownPacks{Packs: []KnownPack{{"minecraft", "core", "1.21.2"}}},
+		&ServerCustomReportDetails{Details: []ReportDetail{{Title: "proxy", Description: "kite"}}},
+		&ServerLinks{Links: []ServerLink{
+			{BuiltinLabel: ServerLinkWebsite, URL: "https://example.com"},
+			{Label: &text.Text{Text: "Store"}, URL: "https://example.com/store"},
+		}},
+	} {
+		t.Run(reflect.TypeOf(pkt).Elem().Name(), func(t *testing.T) {
+			require.NotEqual(t, InvalidState, pkt.ID(Config))
+			requireRoundTrip(t, pkt, LatestVersion)
+		})
+	}
+}
+
+func TestClientInformation_Version(t *testing.T) {
+	pkt := &ClientInformation{Locale: "en_us", ViewDistance: 8, ParticleStatus: ParticleStatusDecreased}
+
+	var old, latest bytes.Buffer
+	require.NoError(t, Write(&old, pkt, Version1_21))
+	require.NoError(t, Write(&latest, pkt, Version1_21_2))
+	require.Equal(t, old.Len()+1, latest.Len())
+
+	read := new(ClientInformation)
+	require.NoError(t, Read(buffer.Wrap(old.Bytes()), read, Version1_21))
+	require.Equal(t, ParticleStatusAll, read.ParticleStatus)
+}
+
+func TestServerConfigRegistryData_Codec(t *testing.T) {
+	pkt := &ServerConfigRegistryData{Codec: testNBT}
+	requireRoundTrip(t, pkt, Version1_20_2)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, pkt, Version1_20_3))
+	require.Equal(t, testNBT, buf.Bytes())
+
+	// The NBT must be complete.
+	read := new(ServerConfigRegistryData)
+	require.Error(t, Read(buffer.Wrap(testNBT[:len(testNBT)-1]), read, Version1_20_2))
+}
+
+// requireRoundTrip writes pkt for version v and checks that reading it back gives an equal packet.
+func requireRoundTrip(t *testing.T, pkt Packet, v Version) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, pkt, v))
+
+	read, r := reflect.New(reflect.TypeOf(pkt).Elem()).Interface().(Packet), buffer.Wrap(buf.Bytes())
+	require.NoError(t, Read(r, read, v))
+	require.Equal(t, pkt, read)
+	require.Zero(t, r.Remaining())
+}
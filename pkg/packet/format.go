@@ -0,0 +1,242 @@
+package packet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mworzala/kite/pkg/text"
+)
+
+const (
+	// maxFormatString is the number of bytes of a string shown by FormatPacket, longer strings are cut.
+	maxFormatString = 256
+	// maxFormatBytes is the number of bytes of a byte slice shown in hex by FormatPacket.
+	maxFormatBytes = 32
+	// maxFormatElements is the number of elements of a list or map shown by FormatPacket.
+	maxFormatElements = 32
+)
+
+var (
+	stringerType  = reflect.TypeFor[fmt.Stringer]()
+	componentType = reflect.TypeFor[text.Component]()
+	rawJSONType   = reflect.TypeFor[json.RawMessage]()
+)
+
+// FormatPacket returns a readable representation of a packet for debugging, such as
+// ClientKeepAlive{KeepAliveID: 42}. Every field is shown by name, except that text components are shown as
+// plain text, byte slices as their length and first bytes, and long strings and lists are cut short.
+//
+// It is used by the String method of the packets in this package, and can be used to implement String for
+// other packets.
+func FormatPacket(pkt Packet) string {
+	var b strings.Builder
+	v := reflect.ValueOf(pkt)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	b.WriteString(v.Type().Name())
+	if v.Kind() == reflect.Struct {
+		formatStruct(&b, v)
+	} else {
+		b.WriteByte('(')
+		formatValue(&b, v)
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// PacketLogValue returns a group with an attribute for every field of a packet, formatted like
+// FormatPacket. Numbers, booleans and nested structs keep their type, so that they can be handled by
+// structured log handlers.
+//
+// It is used by the LogValue method of the packets in this package, making them slog.LogValuer.
+func PacketLogValue(pkt Packet) slog.Value {
+	v := reflect.ValueOf(pkt)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	return logValue(v, false)
+}
+
+func formatValue(b *strings.Builder, v reflect.Value) {
+	if !v.IsValid() {
+		b.WriteString("nil")
+		return
+	}
+	nilable := v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface
+	switch {
+	case nilable && v.IsNil():
+		b.WriteString("nil")
+		return
+	case v.Type().Implements(componentType):
+		b.WriteString(strconv.Quote(text.MarshalPlain(v.Interface().(text.Component))))
+		return
+	case v.Type() == rawJSONType:
+		formatString(b, string(v.Bytes()))
+		return
+	case !nilable && v.Type().Implements(stringerType):
+		b.WriteString(v.Interface().(fmt.Stringer).String())
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		formatValue(b, v.Elem())
+	case reflect.Struct:
+		formatStruct(b, v)
+	case reflect.String:
+		formatString(b, v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			b.WriteString("nil")
+		} else if v.Type().Elem().Kind() == reflect.Uint8 {
+			formatBytes(b, v)
+		} else {
+			formatList(b, v)
+		}
+	case reflect.Map:
+		formatMap(b, v)
+	default:
+		fmt.Fprint(b, v.Interface())
+	}
+}
+
+func formatStruct(b *strings.Builder, v reflect.Value) {
+	b.WriteByte('{')
+	first := true
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if !first {
+			b.WriteString(", ")
+		}
+		first = false
+		b.WriteString(field.Name + ": ")
+		formatValue(b, v.Field(i))
+	}
+	b.WriteByte('}')
+}
+
+func formatString(b *strings.Builder, s string) {
+	if len(s) <= maxFormatString {
+		b.WriteString(strconv.Quote(s))
+		return
+	}
+	end := maxFormatString
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	fmt.Fprintf(b, "%q... (%d bytes)", s[:end], len(s))
+}
+
+// formatBytes writes a byte slice or array as its length and leading bytes in hex, such as [3 bytes: 010203].
+func formatBytes(b *strings.Builder, v reflect.Value) {
+	data := make([]byte, min(v.Len(), maxFormatBytes))
+	reflect.Copy(reflect.ValueOf(data), v)
+	fmt.Fprintf(b, "[%d bytes", v.Len())
+	if len(data) > 0 {
+		b.WriteString(": " + hex.EncodeToString(data))
+		if v.Len() > len(data) {
+			b.WriteString("...")
+		}
+	}
+	b.WriteByte(']')
+}
+
+func formatList(b *strings.Builder, v reflect.Value) {
+	b.WriteByte('[')
+	for i := range min(v.Len(), maxFormatElements) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		formatValue(b, v.Index(i))
+	}
+	if v.Len() > maxFormatElements {
+		fmt.Fprintf(b, ", ... %d more", v.Len()-maxFormatElements)
+	}
+	b.WriteByte(']')
+}
+
+// formatMap writes the entries of a map sorted by key, such as {a: 1, b: 2}.
+func formatMap(b *strings.Builder, v reflect.Value) {
+	type entry struct{ key, value string }
+	entries := make([]entry, 0, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		var key, value strings.Builder
+		formatValue(&key, iter.Key())
+		formatValue(&value, iter.Value())
+		entries = append(entries, entry{key.String(), value.String()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.key, b.key) })
+
+	b.WriteByte('{')
+	for i, e := range entries[:min(len(entries), maxFormatElements)] {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(e.key + ": " + e.value)
+	}
+	if len(entries) > maxFormatElements {
+		fmt.Fprintf(b, ", ... %d more", len(entries)-maxFormatElements)
+	}
+	b.WriteByte('}')
+}
+
+// logValue returns the slog value of a field. Values without a matching kind are formatted to a string. Text
+// components and Stringers are formatted, unless formatStringer is false (for the packet itself).
+func logValue(v reflect.Value, formatStringer bool) slog.Value {
+	if !v.IsValid() {
+		return slog.AnyValue(nil)
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return slog.AnyValue(nil)
+		}
+	}
+	if formatStringer {
+		switch {
+		case v.Type().Implements(componentType):
+			return slog.StringValue(text.MarshalPlain(v.Interface().(text.Component)))
+		case v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface && v.Type().Implements(stringerType):
+			return slog.StringValue(v.Interface().(fmt.Stringer).String())
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return logValue(v.Elem(), true)
+	case reflect.Struct:
+		var attrs []slog.Attr
+		for i := range v.NumField() {
+			if field := v.Type().Field(i); field.IsExported() {
+				attrs = append(attrs, slog.Attr{Key: field.Name, Value: logValue(v.Field(i), true)})
+			}
+		}
+		return slog.GroupValue(attrs...)
+	case reflect.Bool:
+		return slog.BoolValue(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return slog.Int64Value(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return slog.Uint64Value(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return slog.Float64Value(v.Float())
+	case reflect.String:
+		if len(v.String()) <= maxFormatString {
+			return slog.StringValue(v.String())
+		}
+	}
+	var b strings.Builder
+	formatValue(&b, v)
+	return slog.StringValue(b.String())
+}
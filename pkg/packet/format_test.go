@@ -0,0 +1,66 @@
+package packet
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/text"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPacket(t *testing.T) {
+	for _, c := range []struct {
+		pkt      Packet
+		expected string
+	}{
+		{&ClientKeepAlive{KeepAliveID: 42}, "ClientKeepAlive{KeepAliveID: 42}"},
+		{&ClientPluginMessage{Channel: "kite:test", Data: []byte{1, 2, 0xFF}}, `ClientPluginMessage{Channel: "kite:test", Data: [3 bytes: 0102ff]}`},
+		{&ClientPluginMessage{Channel: "kite:test"}, `ClientPluginMessage{Channel: "kite:test", Data: nil}`},
+		{&ServerDisconnect{Reason: &text.Text{Text: "Kicked", Extra: []text.Component{&text.Text{Text: "!"}}}}, `ServerDisconnect{Reason: "Kicked!"}`},
+		{&ServerPlayPlayerInfoRemove{UUIDs: []uuid.UUID{uuid.Nil}}, "ServerPlayPlayerInfoRemove{UUIDs: [00000000-0000-0000-0000-000000000000]}"},
+		{&ServerConfigFeatureFlags{Features: []string{}}, "ServerConfigFeatureFlags{Features: []}"},
+	} {
+		require.Equal(t, c.expected, FormatPacket(c.pkt))
+		require.Equal(t, c.expected, c.pkt.(interface{ String() string }).String())
+	}
+
+	// Long values are cut short.
+	long := FormatPacket(&ClientPluginMessage{Channel: strings.Repeat("é", 200), Data: make([]byte, 100)})
+	require.Contains(t, long, `é"... (400 bytes)`)
+	require.Contains(t, long, "[100 bytes: "+strings.Repeat("00", maxFormatBytes)+"...]")
+	flags := make([]string, 40)
+	require.Contains(t, FormatPacket(&ServerConfigFeatureFlags{Features: flags}), `"", ... 8 more]`)
+}
+
+func TestPacketLogValue(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key != "pkt" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("", "pkt", &ServerDisconnect{Reason: &text.Text{Text: "Kicked"}})
+	logger.Info("", "pkt", &ServerPlayRespawn{SpawnInfo: SpawnInfo{GameMode: GameModeCreative, DimensionName: "minecraft:overworld"}, DataKept: 3})
+	logger.Info("", "pkt", NewRawPacket(Play, Serverbound, ClientPlayTeleportConfirmID, []byte{5}))
+
+	var logged []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(line, &entry))
+		logged = append(logged, entry["pkt"].(map[string]any))
+	}
+	require.Equal(t, map[string]any{"Reason": "Kicked"}, logged[0])
+	require.Equal(t, float64(3), logged[1]["DataKept"])
+	spawn := logged[1]["SpawnInfo"].(map[string]any)
+	require.Equal(t, "creative", spawn["GameMode"])
+	require.Equal(t, "minecraft:overworld", spawn["DimensionName"])
+	require.Nil(t, spawn["DeathLocation"])
+	require.Equal(t, map[string]any{"Direction": "serverbound", "State": "play", "ID": float64(0), "Name": "TeleportConfirm", "Size": float64(1)}, logged[2])
+}
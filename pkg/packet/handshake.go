@@ -6,8 +6,6 @@ import (
 	"github.com/mworzala/kite/pkg/buffer"
 )
 
-const ClientHandshakeHandshakeID = 0x00
-
 type ClientHandshake struct {
 	ProtocolVersion int32
 	ServerAddress   string
@@ -0,0 +1,1778 @@
+// Code generated by packetgen from the files in data; DO NOT EDIT.
+
+package packet
+
+const (
+	Version1_20_2 Version = 764 // 1.20.2
+	Version1_20_3 Version = 765 // 1.20.3 and 1.20.4
+	Version1_20_5 Version = 766 // 1.20.5 and 1.20.6
+	Version1_21   Version = 767 // 1.21 and 1.21.1
+	Version1_21_2 Version = 768 // 1.21.2 and 1.21.3
+
+	OldestVersion = Version1_20_2
+	LatestVersion = Version1_21_2
+)
+
+var versionNames = map[Version]string{
+	Version1_20_2: "1.20.2",
+	Version1_20_3: "1.20.3",
+	Version1_20_5: "1.20.5",
+	Version1_21:   "1.21",
+	Version1_21_2: "1.21.2",
+}
+
+// Packet IDs are named after the side sending the packet and its state. They are the IDs used by
+// LatestVersion, see Version.WireID for other versions. Names mostly match mojang, and are noted
+// when different.
+const (
+	ClientHandshakeHandshakeID = iota
+)
+
+const (
+	ClientStatusStatusRequestID = iota
+	ClientStatusPingRequestID
+)
+
+const (
+	ServerStatusStatusResponseID = iota
+	ServerStatusPingResponseID
+)
+
+const (
+	ClientLoginLoginStartID = iota
+	ClientLoginEncryptionResponseID
+	ClientLoginPluginResponseID
+	ClientLoginLoginAcknowledgedID
+	ClientLoginCookieResponseID
+)
+
+const (
+	ServerLoginDisconnectID = iota
+	ServerLoginEncryptionRequestID
+	ServerLoginLoginSuccessID
+	ServerLoginSetCompressionID
+	ServerLoginPluginRequestID
+	ServerLoginCookieRequestID
+)
+
+const (
+	ClientConfigClientInformationID = iota
+	ClientConfigCookieResponseID
+	ClientConfigPluginMessageID
+	ClientConfigFinishConfigurationID
+	ClientConfigKeepAliveID
+	ClientConfigPongID
+	ClientConfigResourcePackResponseID
+	ClientConfigKnownPacksID
+)
+
+const (
+	ServerConfigCookieRequestID = iota
+	ServerConfigPluginMessageID
+	ServerConfigDisconnectID
+	ServerConfigFinishConfigurationID
+	ServerConfigKeepAliveID
+	ServerConfigPingID
+	ServerConfigResetChatID
+	ServerConfigRegistryDataID
+	ServerConfigRemoveResourcePackID
+	ServerConfigAddResourcePackID
+	ServerConfigStoreCookieID
+	ServerConfigTransferID
+	ServerConfigFeatureFlagsID
+	ServerConfigUpdateTagsID
+	ServerConfigKnownPacksID
+	ServerConfigCustomReportDetailsID
+	ServerConfigServerLinksID
+)
+
+const (
+	ClientPlayTeleportConfirmID = iota
+	ClientPlayBlockEntityTagQueryID
+	ClientPlaySelectBundleItemID
+	ClientPlayChangeDifficultyID
+	ClientPlayChatAckID
+	ClientPlayChatCommandID
+	ClientPlayChatCommandSignedID
+	ClientPlayChatID
+	ClientPlayChatSessionUpdateID
+	ClientPlayChunkBatchReceivedID
+	ClientPlayClientStatusID // Mojang is client command
+	ClientPlayClientTickEndID
+	ClientPlayClientSettingsID // Mojang is client information
+	ClientPlayCommandSuggestionID
+	ClientPlayConfigurationAckID
+	ClientPlayContainerButtonClickID
+	ClientPlayContainerClickID
+	ClientPlayContainerCloseID
+	ClientPlayContainerSlotStateChangedID
+	ClientPlayCookieResponseID
+	ClientPlayPluginMessageID // Mojang is custom payload
+	ClientPlayDebugSampleSubscriptionID
+	ClientPlayEditBookID
+	ClientPlayEntityTagQueryID
+	ClientPlayInteractID
+	ClientPlayJigsawGenerateID
+	ClientPlayKeepAliveID
+	ClientPlayLockDifficultyID
+	ClientPlayMovePlayerPosID
+	ClientPlayMovePlayerPosRotID
+	ClientPlayMovePlayerRotID
+	ClientPlayMovePlayerStatusOnlyID
+	ClientPlayMoveVehicleID
+	ClientPlayPaddleBoatID
+	ClientPlayPickItemID
+	ClientPlayPingRequestID
+	ClientPlayPlaceRecipeID
+	ClientPlayPlayerAbilitiesID
+	ClientPlayPlayerActionID
+	ClientPlayPlayerCommandID
+	ClientPlayPlayerInputID
+	ClientPlayPongID
+	ClientPlayRecipeBookChangeSettingsID
+	ClientPlayRecipeBookSeenRecipeID
+	ClientPlayRenameItemID
+	ClientPlayResourcePackStatusID // Mojang is just resource pack
+	ClientPlaySeenAdvancementsID
+	ClientPlaySelectTradeID
+	ClientPlaySetBeaconID
+	ClientPlaySetCarriedItemID
+	ClientPlaySetCommandBlockID
+	ClientPlaySetCommandMinecartID
+	ClientPlaySetCreativeModeSlotID
+	ClientPlaySetJigsawBlockID
+	ClientPlaySetStructureBlockID
+	ClientPlaySignUpdateID
+	ClientPlaySwingID
+	ClientPlayTeleportToEntityID
+	ClientPlayUseItemOnID
+	ClientPlayUseItemID
+)
+
+const (
+	ServerPlayBundleDelimiterID = iota
+	ServerPlayAddEntityID
+	ServerPlayAddExperienceOrbID
+	ServerPlayAnimateEntityID // Mojang is just 'animate'
+	ServerPlayAwardStatsID
+	ServerPlayBlockChangedAckID
+	ServerPlayBlockDestructionID
+	ServerPlayBlockEntityDataID
+	ServerPlayBlockEventID
+	ServerPlayBlockUpdateID
+	ServerPlayBossBarID // Mojang is boss event
+	ServerPlayChangeDifficultyID
+	ServerPlayChunkBatchFinishedID
+	ServerPlayChunkBatchStartID
+	ServerPlayChunkBiomesID
+	ServerPlayClearTitleID // Mojang is clear titles
+	ServerPlayCommandSuggestionsID
+	ServerPlayCommandsID
+	ServerPlayContainerCloseID
+	ServerPlayContainerSetContentID
+	ServerPlayContainerSetDataID
+	ServerPlayContainerSetSlotID
+	ServerPlayCookieRequestID
+	ServerPlayCooldownID
+	ServerPlayCustomChatCompletionsID
+	ServerPlayPluginMessageID // Mojang is custom payload
+	ServerPlayDamageEventID
+	ServerPlayDebugSampleID
+	ServerPlayDeleteChatID
+	ServerPlayDisconnectID
+	ServerPlayDisguisedChatID
+	ServerPlayEntityEventID
+	ServerPlayEntityPositionSyncID
+	ServerPlayExplosionID   // Mojang is explode
+	ServerPlayForgetChunkID // Mojang is forget level chunk
+	ServerPlayGameEventID
+	ServerPlayHorseScreenOpenID
+	ServerPlayHurtAnimationID
+	ServerPlayInitializeBorderID
+	ServerPlayKeepAliveID
+	ServerPlayChunkDataWithLightID
+	ServerPlayWorldEventID    // Mojang is level event
+	ServerPlayWorldParticleID // Mojang is level particles
+	ServerPlayLightUpdateID
+	ServerPlayLoginID
+	ServerPlayMapDataID
+	ServerPlayMerchantOffersID
+	ServerPlayMoveEntityPosID
+	ServerPlayMoveEntityPosRotID
+	ServerPlayMoveMinecartAlongTrackID
+	ServerPlayMoveEntityRotID
+	ServerPlayMoveVehicleID
+	ServerPlayOpenBookID
+	ServerPlayOpenScreenID
+	ServerPlayOpenSignEditorID
+	ServerPlayPingID
+	ServerPlayPongResponseID
+	ServerPlayPlaceGhostRecipeID
+	ServerPlayPlayerAbilitiesID
+	ServerPlayPlayerChatID
+	ServerPlayPlayerCombatEndID
+	ServerPlayPlayerCombatEnterID
+	ServerPlayPlayerCombatKillID
+	ServerPlayPlayerInfoRemoveID
+	ServerPlayPlayerInfoUpdateID
+	ServerPlayPlayerLookAtID
+	ServerPlayPlayerPositionID
+	ServerPlayPlayerRotationID
+	ServerPlayRecipeBookAddID
+	ServerPlayRecipeBookRemoveID
+	ServerPlayRecipeBookSettingsID
+	ServerPlayRemoveEntitiesID
+	ServerPlayRemoveEntityEffectID // Mojang is remove mob effect
+	ServerPlayRemoveScoreID        // Mojang is reset score
+	ServerPlayResourcePackPopID
+	ServerPlayResourcePackPushID
+	ServerPlayRespawnID
+	ServerPlayRotateHeadID
+	ServerPlaySectionBlocksUpdateID
+	ServerPlaySelectAdvancementTabID
+	ServerPlayServerDataID
+	ServerPlaySetActionBarTextID
+	ServerPlaySetWorldCenterID
+	ServerPlaySetWorldLerpSizeID
+	ServerPlaySetWorldSizeID
+	ServerPlaySetWorldWarningDelayID
+	ServerPlaySetWorldWarningReachID
+	ServerPlaySetCameraID
+	ServerPlaySetChunkCacheCenterID
+	ServerPlaySetChunkCacheRadiusID
+	ServerPlaySetCursorItemID
+	ServerPlaySetDefaultSpawnPositionID
+	ServerPlaySetDisplayObjectiveID
+	ServerPlaySetEntityDataID
+	ServerPlaySetEntityLinkID
+	ServerPlaySetEntityVelocityID // Mojang is set entity motion
+	ServerPlaySetEquipmentID
+	ServerPlaySetExperienceID
+	ServerPlaySetHealthID
+	ServerPlaySetCarriedItemChangeID // Mojang is set held slot
+	ServerPlaySetObjectiveID
+	ServerPlaySetPassengersID
+	ServerPlaySetPlayerInventoryID
+	ServerPlaySetPlayerTeamID
+	ServerPlaySetScoreID
+	ServerPlaySetSimulationDistanceID
+	ServerPlaySetSubtitleTextID
+	ServerPlaySetTimeID
+	ServerPlaySetTitleTextID
+	ServerPlaySetTitleTimeID // Mojang is set titles animation
+	ServerPlaySoundEntityID
+	ServerPlaySoundID
+	ServerPlayStartConfigurationID
+	ServerPlayStopSoundID
+	ServerPlayStoreCookieID
+	ServerPlaySystemChatID
+	ServerPlayTabListID
+	ServerPlayTagQueryID
+	ServerPlayTakeItemEntityID
+	ServerPlayTeleportEntityID
+	ServerPlayTickingStateID
+	ServerPlayTickingStepID
+	ServerPlayTransferID
+	ServerPlayUpdateAdvancementsID
+	ServerPlayUpdateEntityAttributesID // Mojang is update attributes
+	ServerPlayUpdateEntityEffectID     // Mojang is update entity effect
+	ServerPlayUpdateRecipesID
+	ServerPlayUpdateTagsID
+	ServerPlayProjectilePowerID
+	ServerPlayCustomReportDetailsID
+	ServerPlayServerLinksID
+
+	// Packets which no longer exist in LatestVersion.
+	ServerPlayRecipeID // Removed in 1.21.2
+)
+
+// packetNames holds the names of the packet ID constants of every state and direction, in ID order.
+var packetNames = [Play + 1][Serverbound + 1][]string{
+	Handshake: {
+		Serverbound: {
+			"Handshake",
+		},
+	},
+	Status: {
+		Serverbound: {
+			"StatusRequest",
+			"PingRequest",
+		},
+		Clientbound: {
+			"StatusResponse",
+			"PingResponse",
+		},
+	},
+	Login: {
+		Serverbound: {
+			"LoginStart",
+			"EncryptionResponse",
+			"PluginResponse",
+			"LoginAcknowledged",
+			"CookieResponse",
+		},
+		Clientbound: {
+			"Disconnect",
+			"EncryptionRequest",
+			"LoginSuccess",
+			"SetCompression",
+			"PluginRequest",
+			"CookieRequest",
+		},
+	},
+	Config: {
+		Serverbound: {
+			"ClientInformation",
+			"CookieResponse",
+			"PluginMessage",
+			"FinishConfiguration",
+			"KeepAlive",
+			"Pong",
+			"ResourcePackResponse",
+			"KnownPacks",
+		},
+		Clientbound: {
+			"CookieRequest",
+			"PluginMessage",
+			"Disconnect",
+			"FinishConfiguration",
+			"KeepAlive",
+			"Ping",
+			"ResetChat",
+			"RegistryData",
+			"RemoveResourcePack",
+			"AddResourcePack",
+			"StoreCookie",
+			"Transfer",
+			"FeatureFlags",
+			"UpdateTags",
+			"KnownPacks",
+			"CustomReportDetails",
+			"ServerLinks",
+		},
+	},
+	Play: {
+		Serverbound: {
+			"TeleportConfirm",
+			"BlockEntityTagQuery",
+			"SelectBundleItem",
+			"ChangeDifficulty",
+			"ChatAck",
+			"ChatCommand",
+			"ChatCommandSigned",
+			"Chat",
+			"ChatSessionUpdate",
+			"ChunkBatchReceived",
+			"ClientStatus",
+			"ClientTickEnd",
+			"ClientSettings",
+			"CommandSuggestion",
+			"ConfigurationAck",
+			"ContainerButtonClick",
+			"ContainerClick",
+			"ContainerClose",
+			"ContainerSlotStateChanged",
+			"CookieResponse",
+			"PluginMessage",
+			"DebugSampleSubscription",
+			"EditBook",
+			"EntityTagQuery",
+			"Interact",
+			"JigsawGenerate",
+			"KeepAlive",
+			"LockDifficulty",
+			"MovePlayerPos",
+			"MovePlayerPosRot",
+			"MovePlayerRot",
+			"MovePlayerStatusOnly",
+			"MoveVehicle",
+			"PaddleBoat",
+			"PickItem",
+			"PingRequest",
+			"PlaceRecipe",
+			"PlayerAbilities",
+			"PlayerAction",
+			"PlayerCommand",
+			"PlayerInput",
+			"Pong",
+			"RecipeBookChangeSettings",
+			"RecipeBookSeenRecipe",
+			"RenameItem",
+			"ResourcePackStatus",
+			"SeenAdvancements",
+			"SelectTrade",
+			"SetBeacon",
+			"SetCarriedItem",
+			"SetCommandBlock",
+			"SetCommandMinecart",
+			"SetCreativeModeSlot",
+			"SetJigsawBlock",
+			"SetStructureBlock",
+			"SignUpdate",
+			"Swing",
+			"TeleportToEntity",
+			"UseItemOn",
+			"UseItem",
+		},
+		Clientbound: {
+			"BundleDelimiter",
+			"AddEntity",
+			"AddExperienceOrb",
+			"AnimateEntity",
+			"AwardStats",
+			"BlockChangedAck",
+			"BlockDestruction",
+			"BlockEntityData",
+			"BlockEvent",
+			"BlockUpdate",
+			"BossBar",
+			"ChangeDifficulty",
+			"ChunkBatchFinished",
+			"ChunkBatchStart",
+			"ChunkBiomes",
+			"ClearTitle",
+			"CommandSuggestions",
+			"Commands",
+			"ContainerClose",
+			"ContainerSetContent",
+			"ContainerSetData",
+			"ContainerSetSlot",
+			"CookieRequest",
+			"Cooldown",
+			"CustomChatCompletions",
+			"PluginMessage",
+			"DamageEvent",
+			"DebugSample",
+			"DeleteChat",
+			"Disconnect",
+			"DisguisedChat",
+			"EntityEvent",
+			"EntityPositionSync",
+			"Explosion",
+			"ForgetChunk",
+			"GameEvent",
+			"HorseScreenOpen",
+			"HurtAnimation",
+			"InitializeBorder",
+			"KeepAlive",
+			"ChunkDataWithLight",
+			"WorldEvent",
+			"WorldParticle",
+			"LightUpdate",
+			"Login",
+			"MapData",
+			"MerchantOffers",
+			"MoveEntityPos",
+			"MoveEntityPosRot",
+			"MoveMinecartAlongTrack",
+			"MoveEntityRot",
+			"MoveVehicle",
+			"OpenBook",
+			"OpenScreen",
+			"OpenSignEditor",
+			"Ping",
+			"PongResponse",
+			"PlaceGhostRecipe",
+			"PlayerAbilities",
+			"PlayerChat",
+			"PlayerCombatEnd",
+			"PlayerCombatEnter",
+			"PlayerCombatKill",
+			"PlayerInfoRemove",
+			"PlayerInfoUpdate",
+			"PlayerLookAt",
+			"PlayerPosition",
+			"PlayerRotation",
+			"RecipeBookAdd",
+			"RecipeBookRemove",
+			"RecipeBookSettings",
+			"RemoveEntities",
+			"RemoveEntityEffect",
+			"RemoveScore",
+			"ResourcePackPop",
+			"ResourcePackPush",
+			"Respawn",
+			"RotateHead",
+			"SectionBlocksUpdate",
+			"SelectAdvancementTab",
+			"ServerData",
+			"SetActionBarText",
+			"SetWorldCenter",
+			"SetWorldLerpSize",
+			"SetWorldSize",
+			"SetWorldWarningDelay",
+			"SetWorldWarningReach",
+			"SetCamera",
+			"SetChunkCacheCenter",
+			"SetChunkCacheRadius",
+			"SetCursorItem",
+			"SetDefaultSpawnPosition",
+			"SetDisplayObjective",
+			"SetEntityData",
+			"SetEntityLink",
+			"SetEntityVelocity",
+			"SetEquipment",
+			"SetExperience",
+			"SetHealth",
+			"SetCarriedItemChange",
+			"SetObjective",
+			"SetPassengers",
+			"SetPlayerInventory",
+			"SetPlayerTeam",
+			"SetScore",
+			"SetSimulationDistance",
+			"SetSubtitleText",
+			"SetTime",
+			"SetTitleText",
+			"SetTitleTime",
+			"SoundEntity",
+			"Sound",
+			"StartConfiguration",
+			"StopSound",
+			"StoreCookie",
+			"SystemChat",
+			"TabList",
+			"TagQuery",
+			"TakeItemEntity",
+			"TeleportEntity",
+			"TickingState",
+			"TickingStep",
+			"Transfer",
+			"UpdateAdvancements",
+			"UpdateEntityAttributes",
+			"UpdateEntityEffect",
+			"UpdateRecipes",
+			"UpdateTags",
+			"ProjectilePower",
+			"CustomReportDetails",
+			"ServerLinks",
+			"Recipe",
+		},
+	},
+}
+
+// versionPacketIDs lists the packets of every version, in wire ID order.
+var versionPacketIDs = map[Version]versionIDs{
+	Version1_20_2: {
+		Handshake: {
+			Serverbound: {
+				ClientHandshakeHandshakeID,
+			},
+		},
+		Status: {
+			Serverbound: {
+				ClientStatusStatusRequestID,
+				ClientStatusPingRequestID,
+			},
+			Clientbound: {
+				ServerStatusStatusResponseID,
+				ServerStatusPingResponseID,
+			},
+		},
+		Login: {
+			Serverbound: {
+				ClientLoginLoginStartID,
+				ClientLoginEncryptionResponseID,
+				ClientLoginPluginResponseID,
+				ClientLoginLoginAcknowledgedID,
+			},
+			Clientbound: {
+				ServerLoginDisconnectID,
+				ServerLoginEncryptionRequestID,
+				ServerLoginLoginSuccessID,
+				ServerLoginSetCompressionID,
+				ServerLoginPluginRequestID,
+			},
+		},
+		Config: {
+			Serverbound: {
+				ClientConfigClientInformationID,
+				ClientConfigPluginMessageID,
+				ClientConfigFinishConfigurationID,
+				ClientConfigKeepAliveID,
+				ClientConfigPongID,
+				ClientConfigResourcePackResponseID,
+			},
+			Clientbound: {
+				ServerConfigPluginMessageID,
+				ServerConfigDisconnectID,
+				ServerConfigFinishConfigurationID,
+				ServerConfigKeepAliveID,
+				ServerConfigPingID,
+				ServerConfigRegistryDataID,
+				ServerConfigAddResourcePackID,
+				ServerConfigFeatureFlagsID,
+				ServerConfigUpdateTagsID,
+			},
+		},
+		Play: {
+			Serverbound: {
+				ClientPlayTeleportConfirmID,
+				ClientPlayBlockEntityTagQueryID,
+				ClientPlayChangeDifficultyID,
+				ClientPlayChatAckID,
+				ClientPlayChatCommandID,
+				ClientPlayChatID,
+				ClientPlayChatSessionUpdateID,
+				ClientPlayChunkBatchReceivedID,
+				ClientPlayClientStatusID,
+				ClientPlayClientSettingsID,
+				ClientPlayCommandSuggestionID,
+				ClientPlayConfigurationAckID,
+				ClientPlayContainerButtonClickID,
+				ClientPlayContainerClickID,
+				ClientPlayContainerCloseID,
+				ClientPlayPluginMessageID,
+				ClientPlayEditBookID,
+				ClientPlayEntityTagQueryID,
+				ClientPlayInteractID,
+				ClientPlayJigsawGenerateID,
+				ClientPlayKeepAliveID,
+				ClientPlayLockDifficultyID,
+				ClientPlayMovePlayerPosID,
+				ClientPlayMovePlayerPosRotID,
+				ClientPlayMovePlayerRotID,
+				ClientPlayMovePlayerStatusOnlyID,
+				ClientPlayMoveVehicleID,
+				ClientPlayPaddleBoatID,
+				ClientPlayPickItemID,
+				ClientPlayPingRequestID,
+				ClientPlayPlaceRecipeID,
+				ClientPlayPlayerAbilitiesID,
+				ClientPlayPlayerActionID,
+				ClientPlayPlayerCommandID,
+				ClientPlayPlayerInputID,
+				ClientPlayPongID,
+				ClientPlayRecipeBookChangeSettingsID,
+				ClientPlayRecipeBookSeenRecipeID,
+				ClientPlayRenameItemID,
+				ClientPlayResourcePackStatusID,
+				ClientPlaySeenAdvancementsID,
+				ClientPlaySelectTradeID,
+				ClientPlaySetBeaconID,
+				ClientPlaySetCarriedItemID,
+				ClientPlaySetCommandBlockID,
+				ClientPlaySetCommandMinecartID,
+				ClientPlaySetCreativeModeSlotID,
+				ClientPlaySetJigsawBlockID,
+				ClientPlaySetStructureBlockID,
+				ClientPlaySignUpdateID,
+				ClientPlaySwingID,
+				ClientPlayTeleportToEntityID,
+				ClientPlayUseItemOnID,
+				ClientPlayUseItemID,
+			},
+			Clientbound: {
+				ServerPlayBundleDelimiterID,
+				ServerPlayAddEntityID,
+				ServerPlayAddExperienceOrbID,
+				ServerPlayAnimateEntityID,
+				ServerPlayAwardStatsID,
+				ServerPlayBlockChangedAckID,
+				ServerPlayBlockDestructionID,
+				ServerPlayBlockEntityDataID,
+				ServerPlayBlockEventID,
+				ServerPlayBlockUpdateID,
+				ServerPlayBossBarID,
+				ServerPlayChangeDifficultyID,
+				ServerPlayChunkBatchFinishedID,
+				ServerPlayChunkBatchStartID,
+				ServerPlayChunkBiomesID,
+				ServerPlayClearTitleID,
+				ServerPlayCommandSuggestionsID,
+				ServerPlayCommandsID,
+				ServerPlayContainerCloseID,
+				ServerPlayContainerSetContentID,
+				ServerPlayContainerSetDataID,
+				ServerPlayContainerSetSlotID,
+				ServerPlayCooldownID,
+				ServerPlayCustomChatCompletionsID,
+				ServerPlayPluginMessageID,
+				ServerPlayDamageEventID,
+				ServerPlayDeleteChatID,
+				ServerPlayDisconnectID,
+				ServerPlayDisguisedChatID,
+				ServerPlayEntityEventID,
+				ServerPlayExplosionID,
+				ServerPlayForgetChunkID,
+				ServerPlayGameEventID,
+				ServerPlayHorseScreenOpenID,
+				ServerPlayHurtAnimationID,
+				ServerPlayInitializeBorderID,
+				ServerPlayKeepAliveID,
+				ServerPlayChunkDataWithLightID,
+				ServerPlayWorldEventID,
+				ServerPlayWorldParticleID,
+				ServerPlayLightUpdateID,
+				ServerPlayLoginID,
+				ServerPlayMapDataID,
+				ServerPlayMerchantOffersID,
+				ServerPlayMoveEntityPosID,
+				ServerPlayMoveEntityPosRotID,
+				ServerPlayMoveEntityRotID,
+				ServerPlayMoveVehicleID,
+				ServerPlayOpenBookID,
+				ServerPlayOpenScreenID,
+				ServerPlayOpenSignEditorID,
+				ServerPlayPingID,
+				ServerPlayPongResponseID,
+				ServerPlayPlaceGhostRecipeID,
+				ServerPlayPlayerAbilitiesID,
+				ServerPlayPlayerChatID,
+				ServerPlayPlayerCombatEndID,
+				ServerPlayPlayerCombatEnterID,
+				ServerPlayPlayerCombatKillID,
+				ServerPlayPlayerInfoRemoveID,
+				ServerPlayPlayerInfoUpdateID,
+				ServerPlayPlayerLookAtID,
+				ServerPlayPlayerPositionID,
+				ServerPlayRecipeID,
+				ServerPlayRemoveEntitiesID,
+				ServerPlayRemoveEntityEffectID,
+				ServerPlayResourcePackPushID,
+				ServerPlayRespawnID,
+				ServerPlayRotateHeadID,
+				ServerPlaySectionBlocksUpdateID,
+				ServerPlaySelectAdvancementTabID,
+				ServerPlayServerDataID,
+				ServerPlaySetActionBarTextID,
+				ServerPlaySetWorldCenterID,
+				ServerPlaySetWorldLerpSizeID,
+				ServerPlaySetWorldSizeID,
+				ServerPlaySetWorldWarningDelayID,
+				ServerPlaySetWorldWarningReachID,
+				ServerPlaySetCameraID,
+				ServerPlaySetCarriedItemChangeID,
+				ServerPlaySetChunkCacheCenterID,
+				ServerPlaySetChunkCacheRadiusID,
+				ServerPlaySetDefaultSpawnPositionID,
+				ServerPlaySetDisplayObjectiveID,
+				ServerPlaySetEntityDataID,
+				ServerPlaySetEntityLinkID,
+				ServerPlaySetEntityVelocityID,
+				ServerPlaySetEquipmentID,
+				ServerPlaySetExperienceID,
+				ServerPlaySetHealthID,
+				ServerPlaySetObjectiveID,
+				ServerPlaySetPassengersID,
+				ServerPlaySetPlayerTeamID,
+				ServerPlaySetScoreID,
+				ServerPlaySetSimulationDistanceID,
+				ServerPlaySetSubtitleTextID,
+				ServerPlaySetTimeID,
+				ServerPlaySetTitleTextID,
+				ServerPlaySetTitleTimeID,
+				ServerPlaySoundEntityID,
+				ServerPlaySoundID,
+				ServerPlayStartConfigurationID,
+				ServerPlayStopSoundID,
+				ServerPlaySystemChatID,
+				ServerPlayTabListID,
+				ServerPlayTagQueryID,
+				ServerPlayTakeItemEntityID,
+				ServerPlayTeleportEntityID,
+				ServerPlayUpdateAdvancementsID,
+				ServerPlayUpdateEntityAttributesID,
+				ServerPlayUpdateEntityEffectID,
+				ServerPlayUpdateRecipesID,
+				ServerPlayUpdateTagsID,
+			},
+		},
+	},
+	Version1_20_3: {
+		Handshake: {
+			Serverbound: {
+				ClientHandshakeHandshakeID,
+			},
+		},
+		Status: {
+			Serverbound: {
+				ClientStatusStatusRequestID,
+				ClientStatusPingRequestID,
+			},
+			Clientbound: {
+				ServerStatusStatusResponseID,
+				ServerStatusPingResponseID,
+			},
+		},
+		Login: {
+			Serverbound: {
+				ClientLoginLoginStartID,
+				ClientLoginEncryptionResponseID,
+				ClientLoginPluginResponseID,
+				ClientLoginLoginAcknowledgedID,
+			},
+			Clientbound: {
+				ServerLoginDisconnectID,
+				ServerLoginEncryptionRequestID,
+				ServerLoginLoginSuccessID,
+				ServerLoginSetCompressionID,
+				ServerLoginPluginRequestID,
+			},
+		},
+		Config: {
+			Serverbound: {
+				ClientConfigClientInformationID,
+				ClientConfigPluginMessageID,
+				ClientConfigFinishConfigurationID,
+				ClientConfigKeepAliveID,
+				ClientConfigPongID,
+				ClientConfigResourcePackResponseID,
+			},
+			Clientbound: {
+				ServerConfigPluginMessageID,
+				ServerConfigDisconnectID,
+				ServerConfigFinishConfigurationID,
+				ServerConfigKeepAliveID,
+				ServerConfigPingID,
+				ServerConfigRegistryDataID,
+				ServerConfigRemoveResourcePackID,
+				ServerConfigAddResourcePackID,
+				ServerConfigFeatureFlagsID,
+				ServerConfigUpdateTagsID,
+			},
+		},
+		Play: {
+			Serverbound: {
+				ClientPlayTeleportConfirmID,
+				ClientPlayBlockEntityTagQueryID,
+				ClientPlayChangeDifficultyID,
+				ClientPlayChatAckID,
+				ClientPlayChatCommandID,
+				ClientPlayChatID,
+				ClientPlayChatSessionUpdateID,
+				ClientPlayChunkBatchReceivedID,
+				ClientPlayClientStatusID,
+				ClientPlayClientSettingsID,
+				ClientPlayCommandSuggestionID,
+				ClientPlayConfigurationAckID,
+				ClientPlayContainerButtonClickID,
+				ClientPlayContainerClickID,
+				ClientPlayContainerCloseID,
+				ClientPlayContainerSlotStateChangedID,
+				ClientPlayPluginMessageID,
+				ClientPlayEditBookID,
+				ClientPlayEntityTagQueryID,
+				ClientPlayInteractID,
+				ClientPlayJigsawGenerateID,
+				ClientPlayKeepAliveID,
+				ClientPlayLockDifficultyID,
+				ClientPlayMovePlayerPosID,
+				ClientPlayMovePlayerPosRotID,
+				ClientPlayMovePlayerRotID,
+				ClientPlayMovePlayerStatusOnlyID,
+				ClientPlayMoveVehicleID,
+				ClientPlayPaddleBoatID,
+				ClientPlayPickItemID,
+				ClientPlayPingRequestID,
+				ClientPlayPlaceRecipeID,
+				ClientPlayPlayerAbilitiesID,
+				ClientPlayPlayerActionID,
+				ClientPlayPlayerCommandID,
+				ClientPlayPlayerInputID,
+				ClientPlayPongID,
+				ClientPlayRecipeBookChangeSettingsID,
+				ClientPlayRecipeBookSeenRecipeID,
+				ClientPlayRenameItemID,
+				ClientPlayResourcePackStatusID,
+				ClientPlaySeenAdvancementsID,
+				ClientPlaySelectTradeID,
+				ClientPlaySetBeaconID,
+				ClientPlaySetCarriedItemID,
+				ClientPlaySetCommandBlockID,
+				ClientPlaySetCommandMinecartID,
+				ClientPlaySetCreativeModeSlotID,
+				ClientPlaySetJigsawBlockID,
+				ClientPlaySetStructureBlockID,
+				ClientPlaySignUpdateID,
+				ClientPlaySwingID,
+				ClientPlayTeleportToEntityID,
+				ClientPlayUseItemOnID,
+				ClientPlayUseItemID,
+			},
+			Clientbound: {
+				ServerPlayBundleDelimiterID,
+				ServerPlayAddEntityID,
+				ServerPlayAddExperienceOrbID,
+				ServerPlayAnimateEntityID,
+				ServerPlayAwardStatsID,
+				ServerPlayBlockChangedAckID,
+				ServerPlayBlockDestructionID,
+				ServerPlayBlockEntityDataID,
+				ServerPlayBlockEventID,
+				ServerPlayBlockUpdateID,
+				ServerPlayBossBarID,
+				ServerPlayChangeDifficultyID,
+				ServerPlayChunkBatchFinishedID,
+				ServerPlayChunkBatchStartID,
+				ServerPlayChunkBiomesID,
+				ServerPlayClearTitleID,
+				ServerPlayCommandSuggestionsID,
+				ServerPlayCommandsID,
+				ServerPlayContainerCloseID,
+				ServerPlayContainerSetContentID,
+				ServerPlayContainerSetDataID,
+				ServerPlayContainerSetSlotID,
+				ServerPlayCooldownID,
+				ServerPlayCustomChatCompletionsID,
+				ServerPlayPluginMessageID,
+				ServerPlayDamageEventID,
+				ServerPlayDeleteChatID,
+				ServerPlayDisconnectID,
+				ServerPlayDisguisedChatID,
+				ServerPlayEntityEventID,
+				ServerPlayExplosionID,
+				ServerPlayForgetChunkID,
+				ServerPlayGameEventID,
+				ServerPlayHorseScreenOpenID,
+				ServerPlayHurtAnimationID,
+				ServerPlayInitializeBorderID,
+				ServerPlayKeepAliveID,
+				ServerPlayChunkDataWithLightID,
+				ServerPlayWorldEventID,
+				ServerPlayWorldParticleID,
+				ServerPlayLightUpdateID,
+				ServerPlayLoginID,
+				ServerPlayMapDataID,
+				ServerPlayMerchantOffersID,
+				ServerPlayMoveEntityPosID,
+				ServerPlayMoveEntityPosRotID,
+				ServerPlayMoveEntityRotID,
+				ServerPlayMoveVehicleID,
+				ServerPlayOpenBookID,
+				ServerPlayOpenScreenID,
+				ServerPlayOpenSignEditorID,
+				ServerPlayPingID,
+				ServerPlayPongResponseID,
+				ServerPlayPlaceGhostRecipeID,
+				ServerPlayPlayerAbilitiesID,
+				ServerPlayPlayerChatID,
+				ServerPlayPlayerCombatEndID,
+				ServerPlayPlayerCombatEnterID,
+				ServerPlayPlayerCombatKillID,
+				ServerPlayPlayerInfoRemoveID,
+				ServerPlayPlayerInfoUpdateID,
+				ServerPlayPlayerLookAtID,
+				ServerPlayPlayerPositionID,
+				ServerPlayRecipeID,
+				ServerPlayRemoveEntitiesID,
+				ServerPlayRemoveEntityEffectID,
+				ServerPlayRemoveScoreID,
+				ServerPlayResourcePackPopID,
+				ServerPlayResourcePackPushID,
+				ServerPlayRespawnID,
+				ServerPlayRotateHeadID,
+				ServerPlaySectionBlocksUpdateID,
+				ServerPlaySelectAdvancementTabID,
+				ServerPlayServerDataID,
+				ServerPlaySetActionBarTextID,
+				ServerPlaySetWorldCenterID,
+				ServerPlaySetWorldLerpSizeID,
+				ServerPlaySetWorldSizeID,
+				ServerPlaySetWorldWarningDelayID,
+				ServerPlaySetWorldWarningReachID,
+				ServerPlaySetCameraID,
+				ServerPlaySetCarriedItemChangeID,
+				ServerPlaySetChunkCacheCenterID,
+				ServerPlaySetChunkCacheRadiusID,
+				ServerPlaySetDefaultSpawnPositionID,
+				ServerPlaySetDisplayObjectiveID,
+				ServerPlaySetEntityDataID,
+				ServerPlaySetEntityLinkID,
+				ServerPlaySetEntityVelocityID,
+				ServerPlaySetEquipmentID,
+				ServerPlaySetExperienceID,
+				ServerPlaySetHealthID,
+				ServerPlaySetObjectiveID,
+				ServerPlaySetPassengersID,
+				ServerPlaySetPlayerTeamID,
+				ServerPlaySetScoreID,
+				ServerPlaySetSimulationDistanceID,
+				ServerPlaySetSubtitleTextID,
+				ServerPlaySetTimeID,
+				ServerPlaySetTitleTextID,
+				ServerPlaySetTitleTimeID,
+				ServerPlaySoundEntityID,
+				ServerPlaySoundID,
+				ServerPlayStartConfigurationID,
+				ServerPlayStopSoundID,
+				ServerPlaySystemChatID,
+				ServerPlayTabListID,
+				ServerPlayTagQueryID,
+				ServerPlayTakeItemEntityID,
+				ServerPlayTeleportEntityID,
+				ServerPlayTickingStateID,
+				ServerPlayTickingStepID,
+				ServerPlayUpdateAdvancementsID,
+				ServerPlayUpdateEntityAttributesID,
+				ServerPlayUpdateEntityEffectID,
+				ServerPlayUpdateRecipesID,
+				ServerPlayUpdateTagsID,
+			},
+		},
+	},
+	Version1_20_5: {
+		Handshake: {
+			Serverbound: {
+				ClientHandshakeHandshakeID,
+			},
+		},
+		Status: {
+			Serverbound: {
+				ClientStatusStatusRequestID,
+				ClientStatusPingRequestID,
+			},
+			Clientbound: {
+				ServerStatusStatusResponseID,
+				ServerStatusPingResponseID,
+			},
+		},
+		Login: {
+			Serverbound: {
+				ClientLoginLoginStartID,
+				ClientLoginEncryptionResponseID,
+				ClientLoginPluginResponseID,
+				ClientLoginLoginAcknowledgedID,
+				ClientLoginCookieResponseID,
+			},
+			Clientbound: {
+				ServerLoginDisconnectID,
+				ServerLoginEncryptionRequestID,
+				ServerLoginLoginSuccessID,
+				ServerLoginSetCompressionID,
+				ServerLoginPluginRequestID,
+				ServerLoginCookieRequestID,
+			},
+		},
+		Config: {
+			Serverbound: {
+				ClientConfigClientInformationID,
+				ClientConfigCookieResponseID,
+				ClientConfigPluginMessageID,
+				ClientConfigFinishConfigurationID,
+				ClientConfigKeepAliveID,
+				ClientConfigPongID,
+				ClientConfigResourcePackResponseID,
+				ClientConfigKnownPacksID,
+			},
+			Clientbound: {
+				ServerConfigCookieRequestID,
+				ServerConfigPluginMessageID,
+				ServerConfigDisconnectID,
+				ServerConfigFinishConfigurationID,
+				ServerConfigKeepAliveID,
+				ServerConfigPingID,
+				ServerConfigResetChatID,
+				ServerConfigRegistryDataID,
+				ServerConfigRemoveResourcePackID,
+				ServerConfigAddResourcePackID,
+				ServerConfigStoreCookieID,
+				ServerConfigTransferID,
+				ServerConfigFeatureFlagsID,
+				ServerConfigUpdateTagsID,
+				ServerConfigKnownPacksID,
+			},
+		},
+		Play: {
+			Serverbound: {
+				ClientPlayTeleportConfirmID,
+				ClientPlayBlockEntityTagQueryID,
+				ClientPlayChangeDifficultyID,
+				ClientPlayChatAckID,
+				ClientPlayChatCommandID,
+				ClientPlayChatCommandSignedID,
+				ClientPlayChatID,
+				ClientPlayChatSessionUpdateID,
+				ClientPlayChunkBatchReceivedID,
+				ClientPlayClientStatusID,
+				ClientPlayClientSettingsID,
+				ClientPlayCommandSuggestionID,
+				ClientPlayConfigurationAckID,
+				ClientPlayContainerButtonClickID,
+				ClientPlayContainerClickID,
+				ClientPlayContainerCloseID,
+				ClientPlayContainerSlotStateChangedID,
+				ClientPlayCookieResponseID,
+				ClientPlayPluginMessageID,
+				ClientPlayDebugSampleSubscriptionID,
+				ClientPlayEditBookID,
+				ClientPlayEntityTagQueryID,
+				ClientPlayInteractID,
+				ClientPlayJigsawGenerateID,
+				ClientPlayKeepAliveID,
+				ClientPlayLockDifficultyID,
+				ClientPlayMovePlayerPosID,
+				ClientPlayMovePlayerPosRotID,
+				ClientPlayMovePlayerRotID,
+				ClientPlayMovePlayerStatusOnlyID,
+				ClientPlayMoveVehicleID,
+				ClientPlayPaddleBoatID,
+				ClientPlayPickItemID,
+				ClientPlayPingRequestID,
+				ClientPlayPlaceRecipeID,
+				ClientPlayPlayerAbilitiesID,
+				ClientPlayPlayerActionID,
+				ClientPlayPlayerCommandID,
+				ClientPlayPlayerInputID,
+				ClientPlayPongID,
+				ClientPlayRecipeBookChangeSettingsID,
+				ClientPlayRecipeBookSeenRecipeID,
+				ClientPlayRenameItemID,
+				ClientPlayResourcePackStatusID,
+				ClientPlaySeenAdvancementsID,
+				ClientPlaySelectTradeID,
+				ClientPlaySetBeaconID,
+				ClientPlaySetCarriedItemID,
+				ClientPlaySetCommandBlockID,
+				ClientPlaySetCommandMinecartID,
+				ClientPlaySetCreativeModeSlotID,
+				ClientPlaySetJigsawBlockID,
+				ClientPlaySetStructureBlockID,
+				ClientPlaySignUpdateID,
+				ClientPlaySwingID,
+				ClientPlayTeleportToEntityID,
+				ClientPlayUseItemOnID,
+				ClientPlayUseItemID,
+			},
+			Clientbound: {
+				ServerPlayBundleDelimiterID,
+				ServerPlayAddEntityID,
+				ServerPlayAddExperienceOrbID,
+				ServerPlayAnimateEntityID,
+				ServerPlayAwardStatsID,
+				ServerPlayBlockChangedAckID,
+				ServerPlayBlockDestructionID,
+				ServerPlayBlockEntityDataID,
+				ServerPlayBlockEventID,
+				ServerPlayBlockUpdateID,
+				ServerPlayBossBarID,
+				ServerPlayChangeDifficultyID,
+				ServerPlayChunkBatchFinishedID,
+				ServerPlayChunkBatchStartID,
+				ServerPlayChunkBiomesID,
+				ServerPlayClearTitleID,
+				ServerPlayCommandSuggestionsID,
+				ServerPlayCommandsID,
+				ServerPlayContainerCloseID,
+				ServerPlayContainerSetContentID,
+				ServerPlayContainerSetDataID,
+				ServerPlayContainerSetSlotID,
+				ServerPlayCookieRequestID,
+				ServerPlayCooldownID,
+				ServerPlayCustomChatCompletionsID,
+				ServerPlayPluginMessageID,
+				ServerPlayDamageEventID,
+				ServerPlayDebugSampleID,
+				ServerPlayDeleteChatID,
+				ServerPlayDisconnectID,
+				ServerPlayDisguisedChatID,
+				ServerPlayEntityEventID,
+				ServerPlayExplosionID,
+				ServerPlayForgetChunkID,
+				ServerPlayGameEventID,
+				ServerPlayHorseScreenOpenID,
+				ServerPlayHurtAnimationID,
+				ServerPlayInitializeBorderID,
+				ServerPlayKeepAliveID,
+				ServerPlayChunkDataWithLightID,
+				ServerPlayWorldEventID,
+				ServerPlayWorldParticleID,
+				ServerPlayLightUpdateID,
+				ServerPlayLoginID,
+				ServerPlayMapDataID,
+				ServerPlayMerchantOffersID,
+				ServerPlayMoveEntityPosID,
+				ServerPlayMoveEntityPosRotID,
+				ServerPlayMoveEntityRotID,
+				ServerPlayMoveVehicleID,
+				ServerPlayOpenBookID,
+				ServerPlayOpenScreenID,
+				ServerPlayOpenSignEditorID,
+				ServerPlayPingID,
+				ServerPlayPongResponseID,
+				ServerPlayPlaceGhostRecipeID,
+				ServerPlayPlayerAbilitiesID,
+				ServerPlayPlayerChatID,
+				ServerPlayPlayerCombatEndID,
+				ServerPlayPlayerCombatEnterID,
+				ServerPlayPlayerCombatKillID,
+				ServerPlayPlayerInfoRemoveID,
+				ServerPlayPlayerInfoUpdateID,
+				ServerPlayPlayerLookAtID,
+				ServerPlayPlayerPositionID,
+				ServerPlayRecipeID,
+				ServerPlayRemoveEntitiesID,
+				ServerPlayRemoveEntityEffectID,
+				ServerPlayRemoveScoreID,
+				ServerPlayResourcePackPopID,
+				ServerPlayResourcePackPushID,
+				ServerPlayRespawnID,
+				ServerPlayRotateHeadID,
+				ServerPlaySectionBlocksUpdateID,
+				ServerPlaySelectAdvancementTabID,
+				ServerPlayServerDataID,
+				ServerPlaySetActionBarTextID,
+				ServerPlaySetWorldCenterID,
+				ServerPlaySetWorldLerpSizeID,
+				ServerPlaySetWorldSizeID,
+				ServerPlaySetWorldWarningDelayID,
+				ServerPlaySetWorldWarningReachID,
+				ServerPlaySetCameraID,
+				ServerPlaySetCarriedItemChangeID,
+				ServerPlaySetChunkCacheCenterID,
+				ServerPlaySetChunkCacheRadiusID,
+				ServerPlaySetDefaultSpawnPositionID,
+				ServerPlaySetDisplayObjectiveID,
+				ServerPlaySetEntityDataID,
+				ServerPlaySetEntityLinkID,
+				ServerPlaySetEntityVelocityID,
+				ServerPlaySetEquipmentID,
+				ServerPlaySetExperienceID,
+				ServerPlaySetHealthID,
+				ServerPlaySetObjectiveID,
+				ServerPlaySetPassengersID,
+				ServerPlaySetPlayerTeamID,
+				ServerPlaySetScoreID,
+				ServerPlaySetSimulationDistanceID,
+				ServerPlaySetSubtitleTextID,
+				ServerPlaySetTimeID,
+				ServerPlaySetTitleTextID,
+				ServerPlaySetTitleTimeID,
+				ServerPlaySoundEntityID,
+				ServerPlaySoundID,
+				ServerPlayStartConfigurationID,
+				ServerPlayStopSoundID,
+				ServerPlayStoreCookieID,
+				ServerPlaySystemChatID,
+				ServerPlayTabListID,
+				ServerPlayTagQueryID,
+				ServerPlayTakeItemEntityID,
+				ServerPlayTeleportEntityID,
+				ServerPlayTickingStateID,
+				ServerPlayTickingStepID,
+				ServerPlayTransferID,
+				ServerPlayUpdateAdvancementsID,
+				ServerPlayUpdateEntityAttributesID,
+				ServerPlayUpdateEntityEffectID,
+				ServerPlayUpdateRecipesID,
+				ServerPlayUpdateTagsID,
+				ServerPlayProjectilePowerID,
+			},
+		},
+	},
+	Version1_21: {
+		Handshake: {
+			Serverbound: {
+				ClientHandshakeHandshakeID,
+			},
+		},
+		Status: {
+			Serverbound: {
+				ClientStatusStatusRequestID,
+				ClientStatusPingRequestID,
+			},
+			Clientbound: {
+				ServerStatusStatusResponseID,
+				ServerStatusPingResponseID,
+			},
+		},
+		Login: {
+			Serverbound: {
+				ClientLoginLoginStartID,
+				ClientLoginEncryptionResponseID,
+				ClientLoginPluginResponseID,
+				ClientLoginLoginAcknowledgedID,
+				ClientLoginCookieResponseID,
+			},
+			Clientbound: {
+				ServerLoginDisconnectID,
+				ServerLoginEncryptionRequestID,
+				ServerLoginLoginSuccessID,
+				ServerLoginSetCompressionID,
+				ServerLoginPluginRequestID,
+				ServerLoginCookieRequestID,
+			},
+		},
+		Config: {
+			Serverbound: {
+				ClientConfigClientInformationID,
+				ClientConfigCookieResponseID,
+				ClientConfigPluginMessageID,
+				ClientConfigFinishConfigurationID,
+				ClientConfigKeepAliveID,
+				ClientConfigPongID,
+				ClientConfigResourcePackResponseID,
+				ClientConfigKnownPacksID,
+			},
+			Clientbound: {
+				ServerConfigCookieRequestID,
+				ServerConfigPluginMessageID,
+				ServerConfigDisconnectID,
+				ServerConfigFinishConfigurationID,
+				ServerConfigKeepAliveID,
+				ServerConfigPingID,
+				ServerConfigResetChatID,
+				ServerConfigRegistryDataID,
+				ServerConfigRemoveResourcePackID,
+				ServerConfigAddResourcePackID,
+				ServerConfigStoreCookieID,
+				ServerConfigTransferID,
+				ServerConfigFeatureFlagsID,
+				ServerConfigUpdateTagsID,
+				ServerConfigKnownPacksID,
+				ServerConfigCustomReportDetailsID,
+				ServerConfigServerLinksID,
+			},
+		},
+		Play: {
+			Serverbound: {
+				ClientPlayTeleportConfirmID,
+				ClientPlayBlockEntityTagQueryID,
+				ClientPlayChangeDifficultyID,
+				ClientPlayChatAckID,
+				ClientPlayChatCommandID,
+				ClientPlayChatCommandSignedID,
+				ClientPlayChatID,
+				ClientPlayChatSessionUpdateID,
+				ClientPlayChunkBatchReceivedID,
+				ClientPlayClientStatusID,
+				ClientPlayClientSettingsID,
+				ClientPlayCommandSuggestionID,
+				ClientPlayConfigurationAckID,
+				ClientPlayContainerButtonClickID,
+				ClientPlayContainerClickID,
+				ClientPlayContainerCloseID,
+				ClientPlayContainerSlotStateChangedID,
+				ClientPlayCookieResponseID,
+				ClientPlayPluginMessageID,
+				ClientPlayDebugSampleSubscriptionID,
+				ClientPlayEditBookID,
+				ClientPlayEntityTagQueryID,
+				ClientPlayInteractID,
+				ClientPlayJigsawGenerateID,
+				ClientPlayKeepAliveID,
+				ClientPlayLockDifficultyID,
+				ClientPlayMovePlayerPosID,
+				ClientPlayMovePlayerPosRotID,
+				ClientPlayMovePlayerRotID,
+				ClientPlayMovePlayerStatusOnlyID,
+				ClientPlayMoveVehicleID,
+				ClientPlayPaddleBoatID,
+				ClientPlayPickItemID,
+				ClientPlayPingRequestID,
+				ClientPlayPlaceRecipeID,
+				ClientPlayPlayerAbilitiesID,
+				ClientPlayPlayerActionID,
+				ClientPlayPlayerCommandID,
+				ClientPlayPlayerInputID,
+				ClientPlayPongID,
+				ClientPlayRecipeBookChangeSettingsID,
+				ClientPlayRecipeBookSeenRecipeID,
+				ClientPlayRenameItemID,
+				ClientPlayResourcePackStatusID,
+				ClientPlaySeenAdvancementsID,
+				ClientPlaySelectTradeID,
+				ClientPlaySetBeaconID,
+				ClientPlaySetCarriedItemID,
+				ClientPlaySetCommandBlockID,
+				ClientPlaySetCommandMinecartID,
+				ClientPlaySetCreativeModeSlotID,
+				ClientPlaySetJigsawBlockID,
+				ClientPlaySetStructureBlockID,
+				ClientPlaySignUpdateID,
+				ClientPlaySwingID,
+				ClientPlayTeleportToEntityID,
+				ClientPlayUseItemOnID,
+				ClientPlayUseItemID,
+			},
+			Clientbound: {
+				ServerPlayBundleDelimiterID,
+				ServerPlayAddEntityID,
+				ServerPlayAddExperienceOrbID,
+				ServerPlayAnimateEntityID,
+				ServerPlayAwardStatsID,
+				ServerPlayBlockChangedAckID,
+				ServerPlayBlockDestructionID,
+				ServerPlayBlockEntityDataID,
+				ServerPlayBlockEventID,
+				ServerPlayBlockUpdateID,
+				ServerPlayBossBarID,
+				ServerPlayChangeDifficultyID,
+				ServerPlayChunkBatchFinishedID,
+				ServerPlayChunkBatchStartID,
+				ServerPlayChunkBiomesID,
+				ServerPlayClearTitleID,
+				ServerPlayCommandSuggestionsID,
+				ServerPlayCommandsID,
+				ServerPlayContainerCloseID,
+				ServerPlayContainerSetContentID,
+				ServerPlayContainerSetDataID,
+				ServerPlayContainerSetSlotID,
+				ServerPlayCookieRequestID,
+				ServerPlayCooldownID,
+				ServerPlayCustomChatCompletionsID,
+				ServerPlayPluginMessageID,
+				ServerPlayDamageEventID,
+				ServerPlayDebugSampleID,
+				ServerPlayDeleteChatID,
+				ServerPlayDisconnectID,
+				ServerPlayDisguisedChatID,
+				ServerPlayEntityEventID,
+				ServerPlayExplosionID,
+				ServerPlayForgetChunkID,
+				ServerPlayGameEventID,
+				ServerPlayHorseScreenOpenID,
+				ServerPlayHurtAnimationID,
+				ServerPlayInitializeBorderID,
+				ServerPlayKeepAliveID,
+				ServerPlayChunkDataWithLightID,
+				ServerPlayWorldEventID,
+				ServerPlayWorldParticleID,
+				ServerPlayLightUpdateID,
+				ServerPlayLoginID,
+				ServerPlayMapDataID,
+				ServerPlayMerchantOffersID,
+				ServerPlayMoveEntityPosID,
+				ServerPlayMoveEntityPosRotID,
+				ServerPlayMoveEntityRotID,
+				ServerPlayMoveVehicleID,
+				ServerPlayOpenBookID,
+				ServerPlayOpenScreenID,
+				ServerPlayOpenSignEditorID,
+				ServerPlayPingID,
+				ServerPlayPongResponseID,
+				ServerPlayPlaceGhostRecipeID,
+				ServerPlayPlayerAbilitiesID,
+				ServerPlayPlayerChatID,
+				ServerPlayPlayerCombatEndID,
+				ServerPlayPlayerCombatEnterID,
+				ServerPlayPlayerCombatKillID,
+				ServerPlayPlayerInfoRemoveID,
+				ServerPlayPlayerInfoUpdateID,
+				ServerPlayPlayerLookAtID,
+				ServerPlayPlayerPositionID,
+				ServerPlayRecipeID,
+				ServerPlayRemoveEntitiesID,
+				ServerPlayRemoveEntityEffectID,
+				ServerPlayRemoveScoreID,
+				ServerPlayResourcePackPopID,
+				ServerPlayResourcePackPushID,
+				ServerPlayRespawnID,
+				ServerPlayRotateHeadID,
+				ServerPlaySectionBlocksUpdateID,
+				ServerPlaySelectAdvancementTabID,
+				ServerPlayServerDataID,
+				ServerPlaySetActionBarTextID,
+				ServerPlaySetWorldCenterID,
+				ServerPlaySetWorldLerpSizeID,
+				ServerPlaySetWorldSizeID,
+				ServerPlaySetWorldWarningDelayID,
+				ServerPlaySetWorldWarningReachID,
+				ServerPlaySetCameraID,
+				ServerPlaySetCarriedItemChangeID,
+				ServerPlaySetChunkCacheCenterID,
+				ServerPlaySetChunkCacheRadiusID,
+				ServerPlaySetDefaultSpawnPositionID,
+				ServerPlaySetDisplayObjectiveID,
+				ServerPlaySetEntityDataID,
+				ServerPlaySetEntityLinkID,
+				ServerPlaySetEntityVelocityID,
+				ServerPlaySetEquipmentID,
+				ServerPlaySetExperienceID,
+				ServerPlaySetHealthID,
+				ServerPlaySetObjectiveID,
+				ServerPlaySetPassengersID,
+				ServerPlaySetPlayerTeamID,
+				ServerPlaySetScoreID,
+				ServerPlaySetSimulationDistanceID,
+				ServerPlaySetSubtitleTextID,
+				ServerPlaySetTimeID,
+				ServerPlaySetTitleTextID,
+				ServerPlaySetTitleTimeID,
+				ServerPlaySoundEntityID,
+				ServerPlaySoundID,
+				ServerPlayStartConfigurationID,
+				ServerPlayStopSoundID,
+				ServerPlayStoreCookieID,
+				ServerPlaySystemChatID,
+				ServerPlayTabListID,
+				ServerPlayTagQueryID,
+				ServerPlayTakeItemEntityID,
+				ServerPlayTeleportEntityID,
+				ServerPlayTickingStateID,
+				ServerPlayTickingStepID,
+				ServerPlayTransferID,
+				ServerPlayUpdateAdvancementsID,
+				ServerPlayUpdateEntityAttributesID,
+				ServerPlayUpdateEntityEffectID,
+				ServerPlayUpdateRecipesID,
+				ServerPlayUpdateTagsID,
+				ServerPlayProjectilePowerID,
+				ServerPlayCustomReportDetailsID,
+				ServerPlayServerLinksID,
+			},
+		},
+	},
+	Version1_21_2: {
+		Handshake: {
+			Serverbound: {
+				ClientHandshakeHandshakeID,
+			},
+		},
+		Status: {
+			Serverbound: {
+				ClientStatusStatusRequestID,
+				ClientStatusPingRequestID,
+			},
+			Clientbound: {
+				ServerStatusStatusResponseID,
+				ServerStatusPingResponseID,
+			},
+		},
+		Login: {
+			Serverbound: {
+				ClientLoginLoginStartID,
+				ClientLoginEncryptionResponseID,
+				ClientLoginPluginResponseID,
+				ClientLoginLoginAcknowledgedID,
+				ClientLoginCookieResponseID,
+			},
+			Clientbound: {
+				ServerLoginDisconnectID,
+				ServerLoginEncryptionRequestID,
+				ServerLoginLoginSuccessID,
+				ServerLoginSetCompressionID,
+				ServerLoginPluginRequestID,
+				ServerLoginCookieRequestID,
+			},
+		},
+		Config: {
+			Serverbound: {
+				ClientConfigClientInformationID,
+				ClientConfigCookieResponseID,
+				ClientConfigPluginMessageID,
+				ClientConfigFinishConfigurationID,
+				ClientConfigKeepAliveID,
+				ClientConfigPongID,
+				ClientConfigResourcePackResponseID,
+				ClientConfigKnownPacksID,
+			},
+			Clientbound: {
+				ServerConfigCookieRequestID,
+				ServerConfigPluginMessageID,
+				ServerConfigDisconnectID,
+				ServerConfigFinishConfigurationID,
+				ServerConfigKeepAliveID,
+				ServerConfigPingID,
+				ServerConfigResetChatID,
+				ServerConfigRegistryDataID,
+				ServerConfigRemoveResourcePackID,
+				ServerConfigAddResourcePackID,
+				ServerConfigStoreCookieID,
+				ServerConfigTransferID,
+				ServerConfigFeatureFlagsID,
+				ServerConfigUpdateTagsID,
+				ServerConfigKnownPacksID,
+				ServerConfigCustomReportDetailsID,
+				ServerConfigServerLinksID,
+			},
+		},
+		Play: {
+			Serverbound: {
+				ClientPlayTeleportConfirmID,
+				ClientPlayBlockEntityTagQueryID,
+				ClientPlaySelectBundleItemID,
+				ClientPlayChangeDifficultyID,
+				ClientPlayChatAckID,
+				ClientPlayChatCommandID,
+				ClientPlayChatCommandSignedID,
+				ClientPlayChatID,
+				ClientPlayChatSessionUpdateID,
+				ClientPlayChunkBatchReceivedID,
+				ClientPlayClientStatusID,
+				ClientPlayClientTickEndID,
+				ClientPlayClientSettingsID,
+				ClientPlayCommandSuggestionID,
+				ClientPlayConfigurationAckID,
+				ClientPlayContainerButtonClickID,
+				ClientPlayContainerClickID,
+				ClientPlayContainerCloseID,
+				ClientPlayContainerSlotStateChangedID,
+				ClientPlayCookieResponseID,
+				ClientPlayPluginMessageID,
+				ClientPlayDebugSampleSubscriptionID,
+				ClientPlayEditBookID,
+				ClientPlayEntityTagQueryID,
+				ClientPlayInteractID,
+				ClientPlayJigsawGenerateID,
+				ClientPlayKeepAliveID,
+				ClientPlayLockDifficultyID,
+				ClientPlayMovePlayerPosID,
+				ClientPlayMovePlayerPosRotID,
+				ClientPlayMovePlayerRotID,
+				ClientPlayMovePlayerStatusOnlyID,
+				ClientPlayMoveVehicleID,
+				ClientPlayPaddleBoatID,
+				ClientPlayPickItemID,
+				ClientPlayPingRequestID,
+				ClientPlayPlaceRecipeID,
+				ClientPlayPlayerAbilitiesID,
+				ClientPlayPlayerActionID,
+				ClientPlayPlayerCommandID,
+				ClientPlayPlayerInputID,
+				ClientPlayPongID,
+				ClientPlayRecipeBookChangeSettingsID,
+				ClientPlayRecipeBookSeenRecipeID,
+				ClientPlayRenameItemID,
+				ClientPlayResourcePackStatusID,
+				ClientPlaySeenAdvancementsID,
+				ClientPlaySelectTradeID,
+				ClientPlaySetBeaconID,
+				ClientPlaySetCarriedItemID,
+				ClientPlaySetCommandBlockID,
+				ClientPlaySetCommandMinecartID,
+				ClientPlaySetCreativeModeSlotID,
+				ClientPlaySetJigsawBlockID,
+				ClientPlaySetStructureBlockID,
+				ClientPlaySignUpdateID,
+				ClientPlaySwingID,
+				ClientPlayTeleportToEntityID,
+				ClientPlayUseItemOnID,
+				ClientPlayUseItemID,
+			},
+			Clientbound: {
+				ServerPlayBundleDelimiterID,
+				ServerPlayAddEntityID,
+				ServerPlayAddExperienceOrbID,
+				ServerPlayAnimateEntityID,
+				ServerPlayAwardStatsID,
+				ServerPlayBlockChangedAckID,
+				ServerPlayBlockDestructionID,
+				ServerPlayBlockEntityDataID,
+				ServerPlayBlockEventID,
+				ServerPlayBlockUpdateID,
+				ServerPlayBossBarID,
+				ServerPlayChangeDifficultyID,
+				ServerPlayChunkBatchFinishedID,
+				ServerPlayChunkBatchStartID,
+				ServerPlayChunkBiomesID,
+				ServerPlayClearTitleID,
+				ServerPlayCommandSuggestionsID,
+				ServerPlayCommandsID,
+				ServerPlayContainerCloseID,
+				ServerPlayContainerSetContentID,
+				ServerPlayContainerSetDataID,
+				ServerPlayContainerSetSlotID,
+				ServerPlayCookieRequestID,
+				ServerPlayCooldownID,
+				ServerPlayCustomChatCompletionsID,
+				ServerPlayPluginMessageID,
+				ServerPlayDamageEventID,
+				ServerPlayDebugSampleID,
+				ServerPlayDeleteChatID,
+				ServerPlayDisconnectID,
+				ServerPlayDisguisedChatID,
+				ServerPlayEntityEventID,
+				ServerPlayEntityPositionSyncID,
+				ServerPlayExplosionID,
+				ServerPlayForgetChunkID,
+				ServerPlayGameEventID,
+				ServerPlayHorseScreenOpenID,
+				ServerPlayHurtAnimationID,
+				ServerPlayInitializeBorderID,
+				ServerPlayKeepAliveID,
+				ServerPlayChunkDataWithLightID,
+				ServerPlayWorldEventID,
+				ServerPlayWorldParticleID,
+				ServerPlayLightUpdateID,
+				ServerPlayLoginID,
+				ServerPlayMapDataID,
+				ServerPlayMerchantOffersID,
+				ServerPlayMoveEntityPosID,
+				ServerPlayMoveEntityPosRotID,
+				ServerPlayMoveMinecartAlongTrackID,
+				ServerPlayMoveEntityRotID,
+				ServerPlayMoveVehicleID,
+				ServerPlayOpenBookID,
+				ServerPlayOpenScreenID,
+				ServerPlayOpenSignEditorID,
+				ServerPlayPingID,
+				ServerPlayPongResponseID,
+				ServerPlayPlaceGhostRecipeID,
+				ServerPlayPlayerAbilitiesID,
+				ServerPlayPlayerChatID,
+				ServerPlayPlayerCombatEndID,
+				ServerPlayPlayerCombatEnterID,
+				ServerPlayPlayerCombatKillID,
+				ServerPlayPlayerInfoRemoveID,
+				ServerPlayPlayerInfoUpdateID,
+				ServerPlayPlayerLookAtID,
+				ServerPlayPlayerPositionID,
+				ServerPlayPlayerRotationID,
+				ServerPlayRecipeBookAddID,
+				ServerPlayRecipeBookRemoveID,
+				ServerPlayRecipeBookSettingsID,
+				ServerPlayRemoveEntitiesID,
+				ServerPlayRemoveEntityEffectID,
+				ServerPlayRemoveScoreID,
+				ServerPlayResourcePackPopID,
+				ServerPlayResourcePackPushID,
+				ServerPlayRespawnID,
+				ServerPlayRotateHeadID,
+				ServerPlaySectionBlocksUpdateID,
+				ServerPlaySelectAdvancementTabID,
+				ServerPlayServerDataID,
+				ServerPlaySetActionBarTextID,
+				ServerPlaySetWorldCenterID,
+				ServerPlaySetWorldLerpSizeID,
+				ServerPlaySetWorldSizeID,
+				ServerPlaySetWorldWarningDelayID,
+				ServerPlaySetWorldWarningReachID,
+				ServerPlaySetCameraID,
+				ServerPlaySetChunkCacheCenterID,
+				ServerPlaySetChunkCacheRadiusID,
+				ServerPlaySetCursorItemID,
+				ServerPlaySetDefaultSpawnPositionID,
+				ServerPlaySetDisplayObjectiveID,
+				ServerPlaySetEntityDataID,
+				ServerPlaySetEntityLinkID,
+				ServerPlaySetEntityVelocityID,
+				ServerPlaySetEquipmentID,
+				ServerPlaySetExperienceID,
+				ServerPlaySetHealthID,
+				ServerPlaySetCarriedItemChangeID,
+				ServerPlaySetObjectiveID,
+				ServerPlaySetPassengersID,
+				ServerPlaySetPlayerInventoryID,
+				ServerPlaySetPlayerTeamID,
+				ServerPlaySetScoreID,
+				ServerPlaySetSimulationDistanceID,
+				ServerPlaySetSubtitleTextID,
+				ServerPlaySetTimeID,
+				ServerPlaySetTitleTextID,
+				ServerPlaySetTitleTimeID,
+				ServerPlaySoundEntityID,
+				ServerPlaySoundID,
+				ServerPlayStartConfigurationID,
+				ServerPlayStopSoundID,
+				ServerPlayStoreCookieID,
+				ServerPlaySystemChatID,
+				ServerPlayTabListID,
+				ServerPlayTagQueryID,
+				ServerPlayTakeItemEntityID,
+				ServerPlayTeleportEntityID,
+				ServerPlayTickingStateID,
+				ServerPlayTickingStepID,
+				ServerPlayTransferID,
+				ServerPlayUpdateAdvancementsID,
+				ServerPlayUpdateEntityAttributesID,
+				ServerPlayUpdateEntityEffectID,
+				ServerPlayUpdateRecipesID,
+				ServerPlayUpdateTagsID,
+				ServerPlayProjectilePowerID,
+				ServerPlayCustomReportDetailsID,
+				ServerPlayServerLinksID,
+			},
+		},
+	},
+}
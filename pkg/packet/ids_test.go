@@ -0,0 +1,76 @@
+package packet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPacketIDs checks the ID of every Packet in DefaultRegistry against the type column of the data files.
+func TestPacketIDs(t *testing.T) {
+	factories := map[string]Factory{}
+	for _, factory := range DefaultRegistry.factories {
+		factories[reflect.TypeOf(factory()).Elem().Name()] = factory
+	}
+
+	for version := range versionPacketIDs {
+		records := readDataFile(t, fmt.Sprintf("data/%d.csv", version))
+		listed := map[string]map[State]bool{}
+		for _, record := range records[1:] {
+			typeName := record[4]
+			if typeName == "" {
+				continue
+			}
+			factory, ok := factories[typeName]
+			require.True(t, ok, "%s: %s is not in DefaultRegistry", version, typeName)
+			pkt := factory()
+			state := parseState(t, record[0])
+			wireID, err := strconv.ParseInt(record[2], 0, 32)
+			require.NoError(t, err)
+
+			require.Equal(t, record[1], pkt.Direction().String(), "%s: direction of %s", version, typeName)
+			id, ok := version.PacketID(state, pkt.Direction(), int(wireID))
+			require.True(t, ok)
+			require.Equal(t, id, pkt.ID(state), "%s: %s ID in %s state should be %s (%#x)", version, typeName, state, record[3], id)
+
+			if listed[typeName] == nil {
+				listed[typeName] = map[State]bool{}
+			}
+			listed[typeName][state] = true
+		}
+
+		// Packets must not have IDs in states where they are not listed.
+		for typeName, factory := range factories {
+			pkt := factory()
+			for state := Handshake; state <= Play; state++ {
+				if _, ok := version.WireID(state, pkt.Direction(), pkt.ID(state)); ok {
+					require.True(t, listed[typeName][state], "%s: %s has an ID in %s state but is not listed", version, typeName, state)
+				}
+			}
+		}
+	}
+}
+
+func readDataFile(t *testing.T, name string) [][]string {
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func parseState(t *testing.T, s string) State {
+	for state := Handshake; state <= Play; state++ {
+		if state.String() == s {
+			return state
+		}
+	}
+	t.Fatalf("invalid state %q", s)
+	return InvalidState
+}
@@ -0,0 +1,235 @@
+package packet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/nbt"
+)
+
+// An ItemStack is the contents of an inventory slot. A stack with a Count of zero is empty, and has no other
+// fields.
+//
+// Data component values are not length prefixed, so reading a stack requires knowing the encoding of each of
+// its components. Unknown components cannot be preserved: a component type missing from the tables of the
+// version, for example one added by a mod, makes the stack (and the packet containing it) unreadable with an
+// UnknownComponentError. Such packets can only be forwarded without decoding them.
+type ItemStack struct {
+	Item  int32 // An ID in the item registry
+	Count int32
+
+	// Components are the data components added to (or changed from) the defaults of the item, in the order
+	// they are sent. Since 1.20.5.
+	Components []DataComponent
+	// RemovedComponents are the types of the default components removed from the item. Since 1.20.5.
+	RemovedComponents []int32
+
+	NBT nbt.Tag // Before 1.20.5, optional
+}
+
+// A DataComponent is an item data component. The value is kept encoded, so components whose type is known
+// but whose value is not otherwise interpreted are forwarded unchanged. Unknown types cannot be read, see
+// ItemStack.
+type DataComponent struct {
+	Type int32  // An ID in the data component type registry, see DataComponentName
+	Data []byte // The encoded value
+}
+
+// An UnknownComponentError is returned when reading an item stack with a data component type that is not
+// known for the protocol version. As the size of the component is unknown, nothing after it can be read.
+type UnknownComponentError struct {
+	Version Version
+	Type    int32
+}
+
+func (e *UnknownComponentError) Error() string {
+	return fmt.Sprintf("unknown data component type %d in version %s", e.Type, e.Version.String())
+}
+
+// maxItemDepth is how deeply item stacks may be nested in the components of other stacks. The encoded
+// components of every level include those of the levels within, so reading is quadratic in the depth.
+const maxItemDepth = 16
+
+// IsEmpty returns whether the stack is an empty slot.
+func (s *ItemStack) IsEmpty() bool {
+	return s.Count <= 0
+}
+
+func (s *ItemStack) read(r io.Reader, v Version) (err error) {
+	*s = ItemStack{}
+	if v < Version1_20_5 {
+		var present bool
+		if present, err = buffer.Bool.Read(r); err != nil || !present {
+			return
+		}
+		var count int8
+		if s.Item, count, s.NBT, err = buffer.Read3(r, buffer.VarInt, buffer.SignedByte, buffer.NBT); err != nil {
+			return
+		}
+		s.Count = int32(count)
+		return
+	}
+
+	if s.Count, err = buffer.VarInt.Read(r); err != nil || s.Count <= 0 {
+		s.Count = 0
+		return
+	}
+	var added, removed int32
+	if s.Item, added, removed, err = buffer.Read3(r, buffer.VarInt, buffer.VarInt, buffer.VarInt); err != nil {
+		return
+	}
+	if added < 0 || removed < 0 {
+		return fmt.Errorf("invalid data component counts: %d, %d", added, removed)
+	}
+	depth := 0
+	if parent, ok := r.(*componentReader); ok {
+		if depth = parent.depth + 1; depth > maxItemDepth {
+			return fmt.Errorf("item stack nested more than %d deep", maxItemDepth)
+		}
+	}
+	names := dataComponentNames(v)
+	s.Components = make([]DataComponent, 0, min(added, 64))
+	for range added {
+		var component DataComponent
+		if component.Type, err = buffer.VarInt.Read(r); err != nil {
+			return
+		}
+		if component.Type < 0 || int(component.Type) >= len(names) {
+			return &UnknownComponentError{Version: v, Type: component.Type}
+		}
+		// Components are not length prefixed, so the value is read to find where it ends.
+		value := &componentReader{r: r, depth: depth}
+		if err = dataComponentSkippers[names[component.Type]].skip(value, v); err != nil {
+			return fmt.Errorf("data component %s: %w", names[component.Type], err)
+		}
+		component.Data = value.data.Bytes()
+		s.Components = append(s.Components, component)
+	}
+	s.RemovedComponents = make([]int32, 0, min(removed, 64))
+	for range removed {
+		var componentType int32
+		if componentType, err = buffer.VarInt.Read(r); err != nil {
+			return
+		}
+		s.RemovedComponents = append(s.RemovedComponents, componentType)
+	}
+	return
+}
+
+// A componentReader captures the data read from r while skipping a data component value. Item stacks
+// within the value find their nesting depth from it.
+type componentReader struct {
+	r     io.Reader
+	data  bytes.Buffer
+	depth int // Nesting depth of the item stack the component belongs to
+}
+
+func (c *componentReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.data.Write(p[:n])
+	return n, err
+}
+
+func (s *ItemStack) write(w io.Writer, v Version) (err error) {
+	if v < Version1_20_5 {
+		if err = buffer.Bool.Write(w, !s.IsEmpty()); err != nil || s.IsEmpty() {
+			return
+		}
+		return buffer.Write3(w, buffer.VarInt, s.Item, buffer.SignedByte, int8(s.Count), buffer.NBT, s.NBT)
+	}
+
+	if s.IsEmpty() {
+		return buffer.VarInt.Write(w, 0)
+	}
+	err = buffer.Write4(w, buffer.VarInt, s.Count, buffer.VarInt, s.Item,
+		buffer.VarInt, int32(len(s.Components)), buffer.VarInt, int32(len(s.RemovedComponents)))
+	if err != nil {
+		return
+	}
+	for _, component := range s.Components {
+		if err = buffer.Write2(w, buffer.VarInt, component.Type, buffer.RawBytes, component.Data); err != nil {
+			return
+		}
+	}
+	for _, componentType := range s.RemovedComponents {
+		if err = buffer.VarInt.Write(w, componentType); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// DataComponentName returns the name of a data component type in a version, for example minecraft:damage.
+func DataComponentName(v Version, id int32) (string, bool) {
+	names := dataComponentNames(v)
+	if id < 0 || int(id) >= len(names) {
+		return "", false
+	}
+	return "minecraft:" + names[id], true
+}
+
+// DataComponentType returns the ID of a data component type in a version from its name.
+func DataComponentType(v Version, name string) (int32, bool) {
+	for id, other := range dataComponentNames(v) {
+		if "minecraft:"+other == name {
+			return int32(id), true
+		}
+	}
+	return 0, false
+}
+
+func dataComponentNames(v Version) []string {
+	switch {
+	case v >= Version1_21_2:
+		return dataComponents1_21_2
+	case v >= Version1_21:
+		return dataComponents1_21
+	case v >= Version1_20_5:
+		return dataComponents1_20_5
+	}
+	return nil
+}
+
+// The data component type registry of each version, in ID order.
+var (
+	dataComponents1_20_5 = []string{
+		"custom_data", "max_stack_size", "max_damage", "damage", "unbreakable", "custom_name", "item_name", "lore",
+		"rarity", "enchantments", "can_place_on", "can_break", "attribute_modifiers", "custom_model_data",
+		"hide_additional_tooltip", "hide_tooltip", "repair_cost", "creative_slot_lock", "enchantment_glint_override",
+		"intangible_projectile", "food", "fire_resistant", "tool", "stored_enchantments", "dyed_color", "map_color",
+		"map_id", "map_decorations", "map_post_processing", "charged_projectiles", "bundle_contents",
+		"potion_contents", "suspicious_stew_effects", "writable_book_content", "written_book_content", "trim",
+		"debug_stick_state", "entity_data", "bucket_entity_data", "block_entity_data", "instrument",
+		"ominous_bottle_amplifier", "recipes", "lodestone_tracker", "firework_explosion", "fireworks", "profile",
+		"note_block_sound", "banner_patterns", "base_color", "pot_decorations", "container", "block_state", "bees",
+		"lock", "container_loot",
+	}
+	dataComponents1_21 = []string{
+		"custom_data", "max_stack_size", "max_damage", "damage", "unbreakable", "custom_name", "item_name", "lore",
+		"rarity", "enchantments", "can_place_on", "can_break", "attribute_modifiers", "custom_model_data",
+		"hide_additional_tooltip", "hide_tooltip", "repair_cost", "creative_slot_lock", "enchantment_glint_override",
+		"intangible_projectile", "food", "fire_resistant", "tool", "stored_enchantments", "dyed_color", "map_color",
+		"map_id", "map_decorations", "map_post_processing", "charged_projectiles", "bundle_contents",
+		"potion_contents", "suspicious_stew_effects", "writable_book_content", "written_book_content", "trim",
+		"debug_stick_state", "entity_data", "bucket_entity_data", "block_entity_data", "instrument",
+		"ominous_bottle_amplifier", "jukebox_playable", "recipes", "lodestone_tracker", "firework_explosion",
+		"fireworks", "profile", "note_block_sound", "banner_patterns", "base_color", "pot_decorations", "container",
+		"block_state", "bees", "lock", "container_loot",
+	}
+	dataComponents1_21_2 = []string{
+		"custom_data", "max_stack_size", "max_damage", "damage", "unbreakable", "custom_name", "item_name",
+		"item_model", "lore", "rarity", "enchantments", "can_place_on", "can_break", "attribute_modifiers",
+		"custom_model_data", "hide_additional_tooltip", "hide_tooltip", "repair_cost", "creative_slot_lock",
+		"enchantment_glint_override", "intangible_projectile", "food", "consumable", "use_remainder",
+		"use_cooldown", "damage_resistant", "tool", "enchantable", "equippable", "repairable", "glider",
+		"tooltip_style", "death_protection", "stored_enchantments", "dyed_color", "map_color", "map_id",
+		"map_decorations", "map_post_processing", "charged_projectiles", "bundle_contents", "potion_contents",
+		"suspicious_stew_effects", "writable_book_content", "written_book_content", "trim", "debug_stick_state",
+		"entity_data", "bucket_entity_data", "block_entity_data", "instrument", "ominous_bottle_amplifier",
+		"jukebox_playable", "recipes", "lodestone_tracker", "firework_explosion", "fireworks", "profile",
+		"note_block_sound", "banner_patterns", "base_color", "pot_decorations", "container", "block_state", "bees",
+		"lock", "container_loot",
+	}
+)
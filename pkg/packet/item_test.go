@@ -0,0 +1,169 @@
+package packet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/nbt"
+	"github.com/stretchr/testify/require"
+)
+
+// testComponent returns a data component of the named type, with data built by write.
+func testComponent(t *testing.T, v Version, name string, write func(w io.Writer)) DataComponent {
+	t.Helper()
+	id, ok := DataComponentType(v, name)
+	require.True(t, ok, name)
+	var data bytes.Buffer
+	write(&data)
+	return DataComponent{Type: id, Data: data.Bytes()}
+}
+
+func testItemStack(t *testing.T, v Version) ItemStack {
+	if v < Version1_20_5 {
+		return ItemStack{Item: 800, Count: 64, NBT: nbt.Compound{"Damage": nbt.Int(3)}}
+	}
+	nested := ItemStack{Item: 1, Count: 1, Components: []DataComponent{
+		testComponent(t, v, "minecraft:damage", func(w io.Writer) { _ = buffer.VarInt.Write(w, 5) }),
+	}, RemovedComponents: []int32{}}
+	stack := ItemStack{Item: 800, Count: 64, RemovedComponents: []int32{4, 2}, Components: []DataComponent{
+		testComponent(t, v, "minecraft:custom_name", func(w io.Writer) { _ = buffer.NBT.Write(w, nbt.String("Kite")) }),
+		testComponent(t, v, "minecraft:enchantments", func(w io.Writer) {
+			_ = buffer.Write4(w, buffer.VarInt, 1, buffer.VarInt, 3, buffer.VarInt, 2, buffer.Bool, true)
+		}),
+		testComponent(t, v, "minecraft:attribute_modifiers", func(w io.Writer) {
+			_ = buffer.Write2(w, buffer.VarInt, 1, buffer.VarInt, 7)
+			if v < Version1_21 {
+				_ = buffer.Write2(w, buffer.UUID, [16]byte{1}, buffer.String, "speed")
+			} else {
+				_ = buffer.String.Write(w, "minecraft:speed")
+			}
+			_ = buffer.Write4(w, buffer.Double, 0.5, buffer.VarInt, 0, buffer.VarInt, 1, buffer.Bool, true)
+		}),
+		testComponent(t, v, "minecraft:potion_contents", func(w io.Writer) {
+			// An inline effect with a hidden effect, and no potion or color.
+			_ = buffer.Write4(w, buffer.Bool, false, buffer.Bool, false, buffer.VarInt, 1, buffer.VarInt, 10)
+			_ = buffer.Write5(w, buffer.VarInt, 1, buffer.VarInt, 200, buffer.Bool, false, buffer.Bool, true, buffer.Bool, true)
+			_ = buffer.Write4(w, buffer.Bool, true, buffer.VarInt, 0, buffer.VarInt, 100, buffer.Bool, false)
+			_ = buffer.Write3(w, buffer.Bool, false, buffer.Bool, false, buffer.Bool, false)
+			if v >= Version1_21_2 {
+				_ = buffer.Bool.Write(w, false)
+			}
+		}),
+		testComponent(t, v, "minecraft:container", func(w io.Writer) {
+			_ = buffer.VarInt.Write(w, 2)
+			_ = nested.write(w, v)
+			_ = (&ItemStack{}).write(w, v)
+		}),
+		testComponent(t, v, "minecraft:hide_tooltip", func(io.Writer) {}),
+	}}
+	if v >= Version1_21_2 {
+		stack.Components = append(stack.Components,
+			testComponent(t, v, "minecraft:equippable", func(w io.Writer) {
+				_ = buffer.Write3(w, buffer.VarInt, 4, buffer.VarInt, 0, buffer.String, "minecraft:item.armor.equip_elytra")
+				_ = buffer.Write4(w, buffer.Bool, false, buffer.Bool, true, buffer.String, "minecraft:elytra", buffer.Bool, false)
+				_ = buffer.Write4(w, buffer.Bool, true, buffer.VarInt, 0, buffer.String, "minecraft:players", buffer.Bool, true)
+				_ = buffer.Write2(w, buffer.Bool, false, buffer.Bool, true)
+			}),
+			testComponent(t, v, "minecraft:death_protection", func(w io.Writer) {
+				_ = buffer.Write3(w, buffer.VarInt, 2, buffer.VarInt, 2, buffer.VarInt, 3)
+				_ = buffer.Float.Write(w, 16)
+			}))
+	}
+	return stack
+}
+
+func TestItemStack(t *testing.T) {
+	for v := OldestVersion; v <= LatestVersion; v++ {
+		for _, stack := range []ItemStack{{}, {Item: 1, Count: 1, Components: []DataComponent{}, RemovedComponents: []int32{}}, testItemStack(t, v)} {
+			if v < Version1_20_5 && stack.Components != nil {
+				stack = ItemStack{Item: stack.Item, Count: stack.Count}
+			}
+			var buf bytes.Buffer
+			require.NoError(t, stack.write(&buf, v))
+			buf.WriteString("rest")
+
+			var read ItemStack
+			require.NoError(t, read.read(&buf, v), v)
+			require.Equal(t, stack, read, v)
+			require.Equal(t, "rest", buf.String())
+		}
+	}
+}
+
+func TestItemStack_Invalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"unknown type":      {0x01, 0x01, 0x01, 0x00, 0x7F},
+		"negative count":    {0x01, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F, 0x00},
+		"truncated":         {0x01, 0x01, 0x01, 0x00, 0x05, 0x01},
+		"truncated removed": {0x01, 0x01, 0x00, 0x01},
+		"unknown effect":    {0x01, 0x01, 0x01, 0x00, 0x20, 0x01, 0x09},
+	} {
+		var stack ItemStack
+		require.Error(t, stack.read(bytes.NewReader(data), LatestVersion), name)
+	}
+
+	// Unknown components are reported with a typed error, also when nested in another component.
+	nested := []byte{0x01, 0x01, 0x01, 0x00, 0x7F}
+	container, ok := DataComponentType(LatestVersion, "minecraft:container")
+	require.True(t, ok)
+	for _, data := range [][]byte{nested, append([]byte{0x01, 0x01, 0x01, 0x00, byte(container), 0x01}, nested...)} {
+		var stack ItemStack
+		var unknown *UnknownComponentError
+		require.ErrorAs(t, stack.read(bytes.NewReader(data), LatestVersion), &unknown)
+		require.Equal(t, &UnknownComponentError{Version: LatestVersion, Type: 0x7F}, unknown)
+	}
+}
+
+func TestItemStack_Depth(t *testing.T) {
+	// nestedStack returns a stack with depth levels of stacks nested in its container component.
+	var nestedStack func(depth int) ItemStack
+	nestedStack = func(depth int) ItemStack {
+		stack := ItemStack{Item: 1, Count: 1, Components: []DataComponent{}, RemovedComponents: []int32{}}
+		if depth > 0 {
+			nested := nestedStack(depth - 1)
+			stack.Components = append(stack.Components, testComponent(t, LatestVersion, "minecraft:container", func(w io.Writer) {
+				_ = buffer.VarInt.Write(w, 1)
+				_ = nested.write(w, LatestVersion)
+			}))
+		}
+		return stack
+	}
+
+	for depth, valid := range map[int]bool{maxItemDepth: true, maxItemDepth + 1: false} {
+		stack := nestedStack(depth)
+		var buf bytes.Buffer
+		require.NoError(t, stack.write(&buf, LatestVersion))
+		var read ItemStack
+		if err := read.read(&buf, LatestVersion); valid {
+			require.NoError(t, err)
+			require.Equal(t, stack, read)
+		} else {
+			require.ErrorContains(t, err, "nested")
+		}
+	}
+}
+
+func TestDataComponentTypes(t *testing.T) {
+	for v := Version1_20_5; v <= LatestVersion; v++ {
+		for _, name := range dataComponentNames(v) {
+			require.Contains(t, dataComponentSkippers, name, "%s: %s", v, name)
+		}
+	}
+
+	for v, expected := range map[Version]int32{Version1_20_5: 55, Version1_21: 56, Version1_21_2: 66} {
+		id, ok := DataComponentType(v, "minecraft:container_loot")
+		require.True(t, ok)
+		require.Equal(t, expected, id)
+		name, ok := DataComponentName(v, id)
+		require.True(t, ok)
+		require.Equal(t, "minecraft:container_loot", name)
+	}
+	_, ok := DataComponentName(Version1_21, 57)
+	require.False(t, ok)
+	_, ok = DataComponentType(Version1_20_5, "minecraft:jukebox_playable")
+	require.False(t, ok)
+	_, ok = DataComponentType(Version1_20_3, "minecraft:damage")
+	require.False(t, ok)
+}
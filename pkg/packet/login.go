@@ -9,14 +9,6 @@ import (
 	"github.com/mworzala/kite/pkg/text"
 )
 
-const (
-	ClientLoginLoginStartID = iota
-	ClientLoginEncryptionResponseID
-	ClientLoginPluginResponseID
-	ClientLoginLoginAcknowledgedID
-	ClientLoginCookieResponseID
-)
-
 type ClientLoginStart struct {
 	Name string
 	UUID uuid.UUID
@@ -71,6 +63,9 @@ func (p *ClientLoginPluginResponse) Read(r io.Reader) (err error) {
 	if p.Data, err = buffer.RawBytes.Read(r); err != nil {
 		return
 	}
+	if p.Data == nil {
+		p.Data = []byte{} // Successful, but empty
+	}
 	return nil
 }
 func (p *ClientLoginPluginResponse) Write(w io.Writer) (err error) {
@@ -100,15 +95,6 @@ func (p *ClientLoginAcknowledged) Write(_ io.Writer) (err error) {
 	return nil
 }
 
-const (
-	ServerLoginDisconnectID = iota
-	ServerLoginEncryptionRequestID
-	ServerLoginLoginSuccessID
-	ServerLoginSetCompressionID
-	ServerLoginPluginRequestID
-	ServerLoginCookieRequestID
-)
-
 type ServerLoginDisconnect struct {
 	Reason text.Component
 }
@@ -129,7 +115,7 @@ type ServerEncryptionRequest struct {
 	ServerID           string
 	PublicKey          []byte
 	VerifyToken        []byte
-	ShouldAuthenticate bool
+	ShouldAuthenticate bool // Since 1.20.5
 }
 
 func (p *ServerEncryptionRequest) Direction() Direction { return Clientbound }
@@ -137,17 +123,33 @@ func (p *ServerEncryptionRequest) ID(state State) int {
 	return stateId1(state, Login, ServerLoginEncryptionRequestID)
 }
 func (p *ServerEncryptionRequest) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerEncryptionRequest) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerEncryptionRequest) ReadVersion(r io.Reader, v Version) (err error) {
+	if v < Version1_20_5 {
+		p.ServerID, p.PublicKey, p.VerifyToken, err = buffer.Read3(r,
+			buffer.String, buffer.ByteArray, buffer.ByteArray)
+		return
+	}
 	p.ServerID, p.PublicKey, p.VerifyToken, p.ShouldAuthenticate, err = buffer.Read4(r,
 		buffer.String, buffer.ByteArray, buffer.ByteArray, buffer.Bool)
 	return
 }
-func (p *ServerEncryptionRequest) Write(w io.Writer) (err error) {
+func (p *ServerEncryptionRequest) WriteVersion(w io.Writer, v Version) (err error) {
+	if v < Version1_20_5 {
+		return buffer.Write3(w, buffer.String, p.ServerID, buffer.ByteArray, p.PublicKey,
+			buffer.ByteArray, p.VerifyToken)
+	}
 	return buffer.Write4(w, buffer.String, p.ServerID, buffer.ByteArray, p.PublicKey,
 		buffer.ByteArray, p.VerifyToken, buffer.Bool, p.ShouldAuthenticate)
 }
 
 type ServerLoginSuccess struct {
 	mojang.GameProfile
+	StrictErrorHandling bool // Only in 1.20.5 and 1.21
 }
 
 func (p *ServerLoginSuccess) Direction() Direction { return Clientbound }
@@ -155,10 +157,44 @@ func (p *ServerLoginSuccess) ID(state State) int {
 	return stateId1(state, Login, ServerLoginLoginSuccessID)
 }
 func (p *ServerLoginSuccess) Read(r io.Reader) (err error) {
-	return p.GameProfile.Read(r)
+	return p.ReadVersion(r, LatestVersion)
 }
 func (p *ServerLoginSuccess) Write(w io.Writer) (err error) {
-	return p.GameProfile.Write(w)
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerLoginSuccess) ReadVersion(r io.Reader, v Version) (err error) {
+	if err = p.GameProfile.Read(r); err != nil {
+		return err
+	}
+	if v >= Version1_20_5 && v < Version1_21_2 {
+		p.StrictErrorHandling, err = buffer.Bool.Read(r)
+	}
+	return
+}
+func (p *ServerLoginSuccess) WriteVersion(w io.Writer, v Version) (err error) {
+	if err = p.GameProfile.Write(w); err != nil {
+		return err
+	}
+	if v >= Version1_20_5 && v < Version1_21_2 {
+		err = buffer.Bool.Write(w, p.StrictErrorHandling)
+	}
+	return
+}
+
+type ServerLoginSetCompression struct {
+	Threshold int32 // Negative to disable compression
+}
+
+func (p *ServerLoginSetCompression) Direction() Direction { return Clientbound }
+func (p *ServerLoginSetCompression) ID(state State) int {
+	return stateId1(state, Login, ServerLoginSetCompressionID)
+}
+func (p *ServerLoginSetCompression) Read(r io.Reader) (err error) {
+	p.Threshold, err = buffer.VarInt.Read(r)
+	return
+}
+func (p *ServerLoginSetCompression) Write(w io.Writer) (err error) {
+	return buffer.VarInt.Write(w, p.Threshold)
 }
 
 type ServerLoginPluginRequest struct {
@@ -182,10 +218,12 @@ func (p *ServerLoginPluginRequest) Write(w io.Writer) (err error) {
 var (
 	_ Packet = (*ClientLoginStart)(nil)
 	_ Packet = (*ClientEncryptionResponse)(nil)
+	_ Packet = (*ClientLoginPluginResponse)(nil)
 	_ Packet = (*ClientLoginAcknowledged)(nil)
 
-	_ Packet = (*ServerLoginDisconnect)(nil)
-	_ Packet = (*ServerEncryptionRequest)(nil)
-	_ Packet = (*ServerLoginSuccess)(nil)
-	_ Packet = (*ServerLoginPluginRequest)(nil)
+	_ Packet          = (*ServerLoginDisconnect)(nil)
+	_ VersionedPacket = (*ServerEncryptionRequest)(nil)
+	_ VersionedPacket = (*ServerLoginSuccess)(nil)
+	_ Packet          = (*ServerLoginSetCompression)(nil)
+	_ Packet          = (*ServerLoginPluginRequest)(nil)
 )
@@ -0,0 +1,75 @@
+package packet
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/mojang"
+	"github.com/mworzala/kite/pkg/text"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginPackets(t *testing.T) {
+	profile := mojang.GameProfile{
+		ID:   uuid.New(),
+		Name: "kite",
+		Properties: []mojang.ProfileProperty{
+			{Name: "textures", Value: "e30=", Signature: "c2ln"},
+			{Name: "unsigned", Value: "e30=", Signature: ""},
+		},
+	}
+	for _, pkt := range []Packet{
+		&ClientLoginStart{Name: "kite", UUID: uuid.New()},
+		&ClientEncryptionResponse{SharedSecret: []byte{1, 2, 3}, VerifyToken: []byte{4, 5, 6}},
+		&ClientLoginPluginResponse{MessageID: 3, Data: []byte("response")},
+		&ClientLoginPluginResponse{MessageID: 4, Data: []byte{}},
+		&ClientLoginPluginResponse{MessageID: 5},
+		&ClientLoginAcknowledged{},
+		&ClientCookieResponse{Key: "kite:cookie", Payload: []byte{1, 2, 3}},
+		&ClientCookieResponse{Key: "kite:missing"},
+
+		&ServerLoginDisconnect{Reason: &text.Text{Text: "bye"}},
+		&ServerEncryptionRequest{ServerID: "", PublicKey: []byte{1, 2, 3}, VerifyToken: []byte{4, 5, 6}, ShouldAuthenticate: true},
+		&ServerLoginSuccess{GameProfile: profile},
+		&ServerLoginSetCompression{Threshold: 256},
+		&ServerLoginSetCompression{Threshold: -1},
+		&ServerLoginPluginRequest{MessageID: 3, Channel: "velocity:player_info", Data: []byte{4}},
+		&ServerCookieRequest{Key: "kite:cookie"},
+	} {
+		t.Run(reflect.TypeOf(pkt).Elem().Name(), func(t *testing.T) {
+			require.NotEqual(t, InvalidState, pkt.ID(Login))
+			requireRoundTrip(t, pkt, LatestVersion)
+		})
+	}
+}
+
+func TestServerEncryptionRequest_Version(t *testing.T) {
+	pkt := &ServerEncryptionRequest{ServerID: "", PublicKey: []byte{1, 2, 3}, VerifyToken: []byte{4, 5, 6}}
+	requireRoundTrip(t, pkt, Version1_20_3)
+
+	var old, latest bytes.Buffer
+	require.NoError(t, Write(&old, pkt, Version1_20_3))
+	require.NoError(t, Write(&latest, pkt, Version1_20_5))
+	require.Equal(t, old.Len()+1, latest.Len())
+
+	// ShouldAuthenticate is not sent before 1.20.5, so reads as false.
+	pkt.ShouldAuthenticate = true
+	old.Reset()
+	require.NoError(t, Write(&old, pkt, Version1_20_3))
+	read := new(ServerEncryptionRequest)
+	require.NoError(t, Read(buffer.Wrap(old.Bytes()), read, Version1_20_3))
+	require.False(t, read.ShouldAuthenticate)
+}
+
+func TestServerLoginSuccess_StrictErrorHandling(t *testing.T) {
+	pkt := &ServerLoginSuccess{
+		GameProfile:         mojang.GameProfile{ID: uuid.New(), Name: "kite", Properties: []mojang.ProfileProperty{}},
+		StrictErrorHandling: true,
+	}
+	for _, v := range []Version{Version1_20_5, Version1_21} {
+		requireRoundTrip(t, pkt, v)
+	}
+}
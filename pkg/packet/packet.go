@@ -4,6 +4,8 @@ import (
 	"io"
 )
 
+//go:generate go run ../../internal/cmd/codecgen
+
 type Direction int
 
 const (
@@ -57,6 +59,8 @@ func (s State) String() string {
 
 // A Packet is a generic interface for both client and server packets.
 // The only required structure is that they have an ID in a game state (could be implemented by custom packets).
+// Simple packets can be annotated with a kite:packet directive to generate their methods, see
+// internal/cmd/codecgen.
 type Packet interface {
 	Direction() Direction
 	ID(state State) int
@@ -83,3 +87,10 @@ func stateId2(actual, expected1, expected2 State, id1, id2 int) int {
 	}
 	return InvalidState
 }
+
+func stateId3(actual, expected1, expected2, expected3 State, id1, id2, id3 int) int {
+	if actual == expected3 {
+		return id3
+	}
+	return stateId2(actual, expected1, expected2, id1, id2)
+}
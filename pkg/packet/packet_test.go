@@ -0,0 +1,37 @@
+package packet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/stretchr/testify/require"
+)
+
+// FuzzRead decodes arbitrary data as the packet registered in DefaultRegistry for a state, direction and ID.
+// Reading must never panic, and a packet which was read must encode the same way every time it is written
+// and read again. The seed corpus in testdata/fuzz/FuzzRead holds an encoding of every registered packet.
+func FuzzRead(f *testing.F) {
+	f.Fuzz(func(t *testing.T, state, direction, version uint8, id uint16, data []byte) {
+		pkt, ok := DefaultRegistry.Lookup(State(state), Direction(direction), int(id))
+		if !ok {
+			return
+		}
+		// The version is an offset from the oldest supported, so that every input is read by some version.
+		v := OldestVersion + Version(int(version)%int(LatestVersion-OldestVersion+1))
+		if err := Read(buffer.Wrap(data), pkt, v); err != nil {
+			return
+		}
+
+		var encoded bytes.Buffer
+		require.NoError(t, Write(&encoded, pkt, v), "%T", pkt)
+		read := DefaultRegistry.New(State(state), Direction(direction), int(id))
+		r := buffer.Wrap(encoded.Bytes())
+		require.NoError(t, Read(r, read, v), "%T", pkt)
+		require.Zero(t, r.Remaining(), "%T", pkt)
+
+		var again bytes.Buffer
+		require.NoError(t, Write(&again, read, v), "%T", pkt)
+		require.Equal(t, encoded.Bytes(), again.Bytes(), "%T", pkt)
+	})
+}
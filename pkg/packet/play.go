@@ -1,90 +1,67 @@
 package packet
 
 import (
-	"github.com/mworzala/kite/pkg/buffer"
+	"fmt"
 	"io"
-)
 
-// IDs mostly match mojang, and are noted when different.
-const (
-	ClientPlayTeleportConfirmID = iota
-	ClientPlayBlockEntityTagQueryID
-	ClientPlaySelectBundleItemID
-	ClientPlayChangeDifficultyID
-	ClientPlayChatAckID
-	ClientPlayChatCommandID
-	ClientPlayChatCommandSignedID
-	ClientPlayChatID
-	ClientPlayChatSessionUpdateID
-	ClientPlayChunkBatchReceivedID
-	ClientPlayClientStatusID   // Mojang is client command
-	ClientPlayClientSettingsID // Mojang is client information
-	ClientPlayCommandSuggestionID
-	ClientPlayConfigurationAckID
-	ClientPlayContainerButtonClickID
-	ClientPlayContainerClickID
-	ClientPlayContainerCloseID
-	ClientPlayContainerSlotStateChangedID
-	ClientPlayCookieResponseID
-	ClientPlayPluginMessageID // Mojang is custom payload
-	ClientPlayDebugSampleSubscriptionID
-	ClientPlayEditBookID
-	ClientPlayEntityTagQueryID
-	ClientPlayInteractID
-	ClientPlayJigsawGenerateID
-	ClientPlayKeepAliveID
-	ClientPlayLockDifficultyID
-	ClientPlayMovePlayerPosID
-	ClientPlayMovePlayerPosRotID
-	ClientPlayMovePlayerRotID
-	ClientPlayMovePlayerStatusOnlyID
-	ClientPlayMoveVehicleID
-	ClientPlayPaddleBoatID
-	ClientPlayPickItemID
-	ClientPlayPingRequestID
-	ClientPlayPlaceRecipeID
-	ClientPlayPlayerAbilitiesID
-	ClientPlayPlayerActionID
-	ClientPlayPlayerCommandID
-	ClientPlayPlayerInputID
-	ClientPlayPongID
-	ClientPlayRecipeBookChangeSettingsID
-	ClientPlayRecipeBookSeenRecipeID
-	ClientPlayRenameItemID
-	ClientPlayResourcePackStatusID // Mojang is just resource pack
-	ClientPlaySeenAdvancementsID
-	ClientPlaySelectTradeID
-	ClientPlaySetBeaconID
-	ClientPlaySetCarriedItemID
-	ClientPlaySetCommandBlockID
-	ClientPlaySetCommandMinecartID
-	ClientPlaySetCreativeModeSlotID
-	ClientPlaySetJigsawBlockID
-	ClientPlaySetStructureBlockID
-	ClientPlaySetJigsawID
-	ClientPlaySignUpdateID
-	ClientPlaySwingID
-	ClientPlayTeleportToEntityID
-	ClientPlayUseItemOnID
-	ClientPlayUseItemID
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/mojang"
+	"github.com/mworzala/kite/pkg/nbt"
+	"github.com/mworzala/kite/pkg/text"
 )
 
 type ClientPlayChat struct {
-	Message string
+	Message      string
+	Timestamp    int64 // Milliseconds since the Unix epoch
+	Salt         int64
+	Signature    []byte // Nil if the message is not signed
+	MessageCount int32
+	Acknowledged buffer.Bits // The last seen messages acknowledged by the client
 }
 
+const (
+	maxChatMessageLength   = 256
+	chatSignatureLength    = 256
+	chatAcknowledgedLength = 20
+)
+
 func (p *ClientPlayChat) Direction() Direction { return Serverbound }
 func (p *ClientPlayChat) ID(state State) int {
-	return stateId1(state, Play, ClientPlayChatAckID)
+	return stateId1(state, Play, ClientPlayChatID)
 }
 func (p *ClientPlayChat) Read(r io.Reader) (err error) {
-	if p.Message, err = buffer.String.Read(r); err != nil {
+	var signed bool
+	if p.Message, p.Timestamp, p.Salt, signed, err = buffer.Read4(r,
+		buffer.LimitedString(maxChatMessageLength), buffer.Long, buffer.Long, buffer.Bool); err != nil {
 		return err
 	}
+	p.Signature = nil
+	if signed {
+		p.Signature = make([]byte, chatSignatureLength)
+		if _, err = io.ReadFull(r, p.Signature); err != nil {
+			return err
+		}
+	}
+	p.MessageCount, p.Acknowledged, err = buffer.Read2(r,
+		buffer.VarInt, buffer.FixedBitSet(chatAcknowledgedLength))
 	return
 }
-func (p *ClientPlayChat) Write(_ io.Writer) (err error) {
-	panic("not implemented")
+func (p *ClientPlayChat) Write(w io.Writer) (err error) {
+	if p.Signature != nil && len(p.Signature) != chatSignatureLength {
+		return fmt.Errorf("chat signature must be %d bytes, got %d", chatSignatureLength, len(p.Signature))
+	}
+	if err = buffer.Write4(w, buffer.LimitedString(maxChatMessageLength), p.Message, buffer.Long, p.Timestamp,
+		buffer.Long, p.Salt, buffer.Bool, p.Signature != nil); err != nil {
+		return err
+	}
+	if p.Signature != nil {
+		if _, err = w.Write(p.Signature); err != nil {
+			return err
+		}
+	}
+	return buffer.Write2(w, buffer.VarInt, p.MessageCount,
+		buffer.FixedBitSet(chatAcknowledgedLength), p.Acknowledged)
 }
 
 type ClientConfigurationAck struct{}
@@ -100,132 +77,6 @@ func (p *ClientConfigurationAck) Write(_ io.Writer) (err error) {
 	return nil
 }
 
-// IDs mostly match mojang, and are noted when different.
-const (
-	ServerPlayBundleDelimiterID = iota
-	ServerPlayAddEntityID
-	ServerPlayAddExperienceOrbID
-	ServerPlayAnimateEntityID // Mojang is just 'animate'
-	ServerPlayAwardStatsID
-	ServerPlayBlockChangedAckID
-	ServerPlayBlockDestructionID
-	ServerPlayBlockEntityDataID
-	ServerPlayBlockEventID
-	ServerPlayBlockUpdateID
-	ServerPlayBossBarID // Mojang is boss event
-	ServerPlayChangeDifficultyID
-	ServerPlayChunkBatchFinishedID
-	ServerPlayChunkBatchStartID
-	ServerPlayChunkBiomesID
-	ServerPlayClearTitleID // Mojang is clear titles
-	ServerPlayCommandSuggestionsID
-	ServerPlayCommandsID
-	ServerPlayContainerCloseID
-	ServerPlayContainerSetContentID
-	ServerPlayContainerSetDataID
-	ServerPlayContainerSetSlotID
-	ServerPlayCookieRequestID
-	ServerPlayCooldownID
-	ServerPlayCustomChatCompletionsID
-	ServerPlayPluginMessageID // Mojang is custom payload
-	ServerPlayDamageEventID
-	ServerPlayDebugSampleID
-	ServerPlayDeleteChatID
-	ServerPlayDisconnectID
-	ServerPlayDisguisedChatID
-	ServerPlayEntityEventID
-	ServerPlayExplosionID   // Mojang is explode
-	ServerPlayForgetChunkID // Mojang is forget level chunk
-	ServerPlayGameEventID
-	ServerPlayHorseScreenOpenID
-	ServerPlayHurtAnimationID
-	ServerPlayInitializeBorderID
-	ServerPlayKeepAliveID
-	ServerPlayChunkDataWithLightID
-	ServerPlayWorldEventID    // Mojang is level event
-	ServerPlayWorldParticleID // Mojang is level particles
-	ServerPlayLightUpdateID
-	ServerPlayLoginID
-	ServerPlayMapDataID
-	ServerPlayMerchantOffersID
-	ServerPlayMoveEntityPosID
-	ServerPlayMoveEntityPosRotID
-	ServerPlayMoveEntityRotID
-	ServerPlayMoveVehicleID
-	ServerPlayOpenBookID
-	ServerPlayOpenScreenID
-	ServerPlayOpenSignEditorID
-	ServerPlayPingID
-	ServerPlayPongResponseID
-	ServerPlayPlaceGhostRecipeID
-	ServerPlayPlayerAbilitiesID
-	ServerPlayPlayerChatID
-	ServerPlayPlayerCombatEndID
-	ServerPlayPlayerCombatEnterID
-	ServerPlayPlayerCombatKillID
-	ServerPlayPlayerInfoRemoveID
-	ServerPlayPlayerInfoUpdateID
-	ServerPlayPlayerLookAtID
-	ServerPlayPlayerPositionID
-	ServerPlayRecipeID
-	ServerPlayRemoveEntitiesID
-	ServerPlayRemoveEntityEffectID // Mojang is remove mob effect
-	ServerPlayRemoveScoreID
-	ServerPlayResourcePackPopID
-	ServerPlayResourcePackPushID
-	ServerPlayRespawnID
-	ServerPlayRotateHeadID
-	ServerPlaySectionBlocksUpdateID
-	ServerPlaySelectAdvancementTabID
-	ServerPlayServerDataID
-	ServerPlaySetActionBarTextID
-	ServerPlaySetWorldCenterID
-	ServerPlaySetWorldLerpSizeID
-	ServerPlaySetWorldSizeID
-	ServerPlaySetWorldWarningDelayID
-	ServerPlaySetWorldWarningReachID
-	ServerPlaySetCameraID
-	ServerPlaySetCarriedItemChangeID
-	ServerPlaySetChunkCacheCenterID
-	ServerPlaySetChunkCacheRadiusID
-	ServerPlaySetDefaultSpawnPositionID
-	ServerPlaySetDisplayObjectiveID
-	ServerPlaySetEntityDataID
-	ServerPlaySetEntityLinkID
-	ServerPlaySetEntityVelocityID // Mojang is set entity motion
-	ServerPlaySetEquipmentID
-	ServerPlaySetExperienceID
-	ServerPlaySetHealthID
-	ServerPlaySetObjectiveID
-	ServerPlaySetPassengersID
-	ServerPlaySetPlayerTeamID
-	ServerPlaySetScoreID
-	ServerPlaySetSimulationDistanceID
-	ServerPlaySetSubtitleTextID
-	ServerPlaySetTimeID
-	ServerPlaySetTitleTextID
-	ServerPlaySetTitleTimeID // Mojang is set titles animation
-	ServerPlaySoundEntityID
-	ServerPlaySoundID
-	ServerPlayStartConfigurationID
-	ServerPlayStopSoundID
-	ServerPlayStoreCookieID
-	ServerPlaySystemChatID
-	ServerPlayTabListID
-	ServerPlayTagQueryID
-	ServerPlayTakeItemEntityID
-	ServerPlayTeleportEntityID
-	ServerPlayTickingStateID
-	ServerPlayTickingStepID
-	ServerPlayTransferID
-	ServerPlayUpdateAdvancementsID
-	ServerPlayUpdateEntityAttributesID // Mojang is update attributes
-	ServerPlayUpdateEntityEffectID     // Mojang is update entity effect
-	ServerPlayUpdateRecipesID
-	ServerPlayUpdateTagsID
-	ServerPlayProjectilePowerID
-)
-
 type ServerStartConfiguration struct {
 }
 
@@ -240,8 +91,840 @@ func (p *ServerStartConfiguration) Write(_ io.Writer) (err error) {
 	return nil
 }
 
+type ServerPlayLogin struct {
+	EntityID            int32
+	IsHardcore          bool
+	DimensionNames      []string
+	MaxPlayers          int32 // Unused by the client
+	ViewDistance        int32
+	SimulationDistance  int32
+	ReducedDebugInfo    bool
+	EnableRespawnScreen bool
+	DoLimitedCrafting   bool
+	SpawnInfo
+	EnforcesSecureChat bool // Since 1.20.5
+}
+
+func (p *ServerPlayLogin) Direction() Direction { return Clientbound }
+func (p *ServerPlayLogin) ID(state State) int {
+	return stateId1(state, Play, ServerPlayLoginID)
+}
+func (p *ServerPlayLogin) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerPlayLogin) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerPlayLogin) ReadVersion(r io.Reader, v Version) (err error) {
+	p.EntityID, p.IsHardcore, p.DimensionNames, p.MaxPlayers, p.ViewDistance, p.SimulationDistance,
+		p.ReducedDebugInfo, p.EnableRespawnScreen, p.DoLimitedCrafting, err = buffer.Read9(r,
+		buffer.Int, buffer.Bool, buffer.List(buffer.String), buffer.VarInt, buffer.VarInt, buffer.VarInt,
+		buffer.Bool, buffer.Bool, buffer.Bool)
+	if err != nil {
+		return
+	}
+	if err = p.SpawnInfo.read(r, v); err != nil || v < Version1_20_5 {
+		return
+	}
+	p.EnforcesSecureChat, err = buffer.Bool.Read(r)
+	return
+}
+func (p *ServerPlayLogin) WriteVersion(w io.Writer, v Version) (err error) {
+	err = buffer.Write9(w, buffer.Int, p.EntityID, buffer.Bool, p.IsHardcore,
+		buffer.List(buffer.String), p.DimensionNames, buffer.VarInt, p.MaxPlayers,
+		buffer.VarInt, p.ViewDistance, buffer.VarInt, p.SimulationDistance, buffer.Bool, p.ReducedDebugInfo,
+		buffer.Bool, p.EnableRespawnScreen, buffer.Bool, p.DoLimitedCrafting)
+	if err != nil {
+		return
+	}
+	if err = p.SpawnInfo.write(w, v); err != nil || v < Version1_20_5 {
+		return
+	}
+	return buffer.Bool.Write(w, p.EnforcesSecureChat)
+}
+
+// Respawn data kept flags.
+const (
+	RespawnKeepAttributes byte = 1 << iota
+	RespawnKeepMetadata
+)
+
+type ServerPlayRespawn struct {
+	SpawnInfo
+	DataKept byte
+}
+
+func (p *ServerPlayRespawn) Direction() Direction { return Clientbound }
+func (p *ServerPlayRespawn) ID(state State) int {
+	return stateId1(state, Play, ServerPlayRespawnID)
+}
+func (p *ServerPlayRespawn) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerPlayRespawn) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerPlayRespawn) ReadVersion(r io.Reader, v Version) (err error) {
+	if err = p.SpawnInfo.read(r, v); err != nil {
+		return
+	}
+	p.DataKept, err = buffer.Byte.Read(r)
+	return
+}
+func (p *ServerPlayRespawn) WriteVersion(w io.Writer, v Version) (err error) {
+	if err = p.SpawnInfo.write(w, v); err != nil {
+		return
+	}
+	return buffer.Byte.Write(w, p.DataKept)
+}
+
+// SpawnInfo describes the world a player spawns into, in ServerPlayLogin and ServerPlayRespawn.
+type SpawnInfo struct {
+	DimensionType     int32  // Since 1.20.5, an ID in the dimension type registry
+	DimensionTypeName string // Before 1.20.5
+	DimensionName     string
+	HashedSeed        int64
+	GameMode          GameMode
+	PreviousGameMode  GameMode
+	IsDebug           bool
+	IsFlat            bool
+	DeathLocation     *DeathLocation // Optional
+	PortalCooldown    int32
+	SeaLevel          int32 // Since 1.21.2
+}
+
+type DeathLocation struct {
+	Dimension string
+	Position  buffer.BlockPos
+}
+
+func (s *SpawnInfo) read(r io.Reader, v Version) (err error) {
+	if v < Version1_20_5 {
+		s.DimensionTypeName, err = buffer.String.Read(r)
+	} else {
+		s.DimensionType, err = buffer.VarInt.Read(r)
+	}
+	if err != nil {
+		return
+	}
+	var gameMode, previousGameMode byte
+	var hasDeathLocation bool
+	s.DimensionName, s.HashedSeed, gameMode, previousGameMode, s.IsDebug, s.IsFlat, hasDeathLocation, err = buffer.Read7(r,
+		buffer.String, buffer.Long, buffer.Byte, buffer.Byte, buffer.Bool, buffer.Bool, buffer.Bool)
+	if err != nil {
+		return
+	}
+	s.GameMode, s.PreviousGameMode = GameMode(gameMode), GameMode(int8(previousGameMode))
+	if !s.GameMode.Validate() || s.GameMode == GameModeUndefined || !s.PreviousGameMode.Validate() {
+		return fmt.Errorf("invalid game mode: %d, %d", gameMode, int8(previousGameMode))
+	}
+	if hasDeathLocation {
+		s.DeathLocation = new(DeathLocation)
+		if s.DeathLocation.Dimension, s.DeathLocation.Position, err = buffer.Read2(r, buffer.String, buffer.Position); err != nil {
+			return
+		}
+	}
+	if s.PortalCooldown, err = buffer.VarInt.Read(r); err != nil || v < Version1_21_2 {
+		return
+	}
+	s.SeaLevel, err = buffer.VarInt.Read(r)
+	return
+}
+
+func (s *SpawnInfo) write(w io.Writer, v Version) (err error) {
+	if v < Version1_20_5 {
+		err = buffer.String.Write(w, s.DimensionTypeName)
+	} else {
+		err = buffer.VarInt.Write(w, s.DimensionType)
+	}
+	if err != nil {
+		return
+	}
+	err = buffer.Write7(w, buffer.String, s.DimensionName, buffer.Long, s.HashedSeed,
+		buffer.Byte, byte(s.GameMode), buffer.Byte, byte(s.PreviousGameMode),
+		buffer.Bool, s.IsDebug, buffer.Bool, s.IsFlat, buffer.Bool, s.DeathLocation != nil)
+	if err != nil {
+		return
+	}
+	if s.DeathLocation != nil {
+		if err = buffer.Write2(w, buffer.String, s.DeathLocation.Dimension, buffer.Position, s.DeathLocation.Position); err != nil {
+			return
+		}
+	}
+	if err = buffer.VarInt.Write(w, s.PortalCooldown); err != nil || v < Version1_21_2 {
+		return
+	}
+	return buffer.VarInt.Write(w, s.SeaLevel)
+}
+
+type ServerPlayPlayerInfoUpdate struct {
+	Actions PlayerInfoActions
+	Entries []PlayerInfoEntry
+}
+
+// A PlayerInfoEntry updates a player in the tab list. Only the fields included in the actions of the
+// packet are sent.
+type PlayerInfoEntry struct {
+	UUID         uuid.UUID
+	Name         string                   // PlayerInfoAddPlayer
+	Properties   []mojang.ProfileProperty // PlayerInfoAddPlayer
+	ChatSession  *ChatSession             // PlayerInfoInitializeChat, optional
+	GameMode     GameMode                 // PlayerInfoUpdateGameMode
+	Listed       bool                     // PlayerInfoUpdateListed
+	Latency      int32                    // PlayerInfoUpdateLatency, in milliseconds
+	DisplayName  text.Component           // PlayerInfoUpdateDisplayName, optional
+	ListPriority int32                    // PlayerInfoUpdateListPriority
+}
+
+// A ChatSession holds the public key a player signs chat messages with.
+type ChatSession struct {
+	ID           uuid.UUID
+	ExpiresAt    int64 // Unix milliseconds
+	PublicKey    []byte
+	KeySignature []byte
+}
+
+func (p *ServerPlayPlayerInfoUpdate) Direction() Direction { return Clientbound }
+func (p *ServerPlayPlayerInfoUpdate) ID(state State) int {
+	return stateId1(state, Play, ServerPlayPlayerInfoUpdateID)
+}
+func (p *ServerPlayPlayerInfoUpdate) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerPlayPlayerInfoUpdate) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerPlayPlayerInfoUpdate) ReadVersion(r io.Reader, v Version) (err error) {
+	var actions byte
+	if actions, err = buffer.Byte.Read(r); err != nil {
+		return
+	}
+	p.Actions = PlayerInfoActions(actions)
+	p.Entries, err = buffer.ReadList(r, func() (entry PlayerInfoEntry, err error) {
+		err = entry.read(r, p.Actions, v)
+		return
+	})
+	return
+}
+func (p *ServerPlayPlayerInfoUpdate) WriteVersion(w io.Writer, v Version) (err error) {
+	if err = buffer.Byte.Write(w, byte(p.Actions)); err != nil {
+		return
+	}
+	return buffer.WriteList(w, p.Entries, func(entry PlayerInfoEntry) error {
+		return entry.write(w, p.Actions, v)
+	})
+}
+
+func (e *PlayerInfoEntry) read(r io.Reader, actions PlayerInfoActions, v Version) (err error) {
+	if e.UUID, err = buffer.UUID.Read(r); err != nil {
+		return
+	}
+	if actions.Has(PlayerInfoAddPlayer) {
+		if e.Name, err = buffer.String.Read(r); err != nil {
+			return
+		}
+		e.Properties, err = buffer.ReadList(r, func() (pp mojang.ProfileProperty, err error) {
+			pp.Name, pp.Value, pp.Signature, err = buffer.Read3(r,
+				buffer.String, buffer.String, buffer.Opt(buffer.String))
+			return pp, err
+		})
+		if err != nil {
+			return
+		}
+	}
+	if actions.Has(PlayerInfoInitializeChat) {
+		var present bool
+		if present, err = buffer.Bool.Read(r); err != nil {
+			return
+		}
+		if present {
+			e.ChatSession = new(ChatSession)
+			e.ChatSession.ID, e.ChatSession.ExpiresAt, e.ChatSession.PublicKey, e.ChatSession.KeySignature, err = buffer.Read4(r,
+				buffer.UUID, buffer.Long, buffer.ByteArray, buffer.ByteArray)
+			if err != nil {
+				return
+			}
+		}
+	}
+	if actions.Has(PlayerInfoUpdateGameMode) {
+		var gameMode int32
+		if gameMode, err = buffer.VarInt.Read(r); err != nil {
+			return
+		}
+		if e.GameMode = GameMode(gameMode); !e.GameMode.Validate() || e.GameMode == GameModeUndefined {
+			return fmt.Errorf("invalid game mode: %d", gameMode)
+		}
+	}
+	if actions.Has(PlayerInfoUpdateListed) {
+		if e.Listed, err = buffer.Bool.Read(r); err != nil {
+			return
+		}
+	}
+	if actions.Has(PlayerInfoUpdateLatency) {
+		if e.Latency, err = buffer.VarInt.Read(r); err != nil {
+			return
+		}
+	}
+	if actions.Has(PlayerInfoUpdateDisplayName) {
+		if e.DisplayName, err = buffer.Opt(textComponent(v)).Read(r); err != nil {
+			return
+		}
+	}
+	if actions.Has(PlayerInfoUpdateListPriority) && v >= Version1_21_2 {
+		e.ListPriority, err = buffer.VarInt.Read(r)
+	}
+	return
+}
+
+func (e *PlayerInfoEntry) write(w io.Writer, actions PlayerInfoActions, v Version) (err error) {
+	if err = buffer.UUID.Write(w, e.UUID); err != nil {
+		return
+	}
+	if actions.Has(PlayerInfoAddPlayer) {
+		if err = buffer.String.Write(w, e.Name); err != nil {
+			return
+		}
+		err = buffer.WriteList(w, e.Properties, func(pp mojang.ProfileProperty) error {
+			return buffer.Write3(w, buffer.String, pp.Name, buffer.String,
+				pp.Value, buffer.Opt(buffer.String), pp.Signature)
+		})
+		if err != nil {
+			return
+		}
+	}
+	if actions.Has(PlayerInfoInitializeChat) {
+		if err = buffer.Bool.Write(w, e.ChatSession != nil); err != nil {
+			return
+		}
+		if e.ChatSession != nil {
+			err = buffer.Write4(w, buffer.UUID, e.ChatSession.ID, buffer.Long, e.ChatSession.ExpiresAt,
+				buffer.ByteArray, e.ChatSession.PublicKey, buffer.ByteArray, e.ChatSession.KeySignature)
+			if err != nil {
+				return
+			}
+		}
+	}
+	if actions.Has(PlayerInfoUpdateGameMode) {
+		if err = buffer.VarInt.Write(w, int32(e.GameMode)); err != nil {
+			return
+		}
+	}
+	if actions.Has(PlayerInfoUpdateListed) {
+		if err = buffer.Bool.Write(w, e.Listed); err != nil {
+			return
+		}
+	}
+	if actions.Has(PlayerInfoUpdateLatency) {
+		if err = buffer.VarInt.Write(w, e.Latency); err != nil {
+			return
+		}
+	}
+	if actions.Has(PlayerInfoUpdateDisplayName) {
+		if err = buffer.Opt(textComponent(v)).Write(w, e.DisplayName); err != nil {
+			return
+		}
+	}
+	if actions.Has(PlayerInfoUpdateListPriority) && v >= Version1_21_2 {
+		err = buffer.VarInt.Write(w, e.ListPriority)
+	}
+	return
+}
+
+//kite:packet Play=ServerPlayPlayerInfoRemoveID
+type ServerPlayPlayerInfoRemove struct {
+	UUIDs []uuid.UUID
+}
+
+// ServerPlayBossBar adds, removes or updates a boss bar. Only the fields used by Action are sent.
+type ServerPlayBossBar struct {
+	UUID     uuid.UUID
+	Action   BossBarAction
+	Title    text.Component  // BossBarAdd, BossBarUpdateTitle
+	Health   float32         // BossBarAdd, BossBarUpdateHealth, from 0 to 1
+	Color    BossBarColor    // BossBarAdd, BossBarUpdateStyle
+	Division BossBarDivision // BossBarAdd, BossBarUpdateStyle
+	Flags    byte            // BossBarAdd, BossBarUpdateFlags
+}
+
+func (p *ServerPlayBossBar) Direction() Direction { return Clientbound }
+func (p *ServerPlayBossBar) ID(state State) int {
+	return stateId1(state, Play, ServerPlayBossBarID)
+}
+func (p *ServerPlayBossBar) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerPlayBossBar) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerPlayBossBar) ReadVersion(r io.Reader, v Version) (err error) {
+	if p.UUID, p.Action, err = buffer.Read2(r, buffer.UUID, buffer.Enum[BossBarAction]{}); err != nil {
+		return
+	}
+	switch p.Action {
+	case BossBarAdd:
+		p.Title, p.Health, p.Color, p.Division, p.Flags, err = buffer.Read5(r, textComponent(v), buffer.Float,
+			buffer.Enum[BossBarColor]{}, buffer.Enum[BossBarDivision]{}, buffer.Byte)
+	case BossBarUpdateHealth:
+		p.Health, err = buffer.Float.Read(r)
+	case BossBarUpdateTitle:
+		p.Title, err = textComponent(v).Read(r)
+	case BossBarUpdateStyle:
+		p.Color, p.Division, err = buffer.Read2(r, buffer.Enum[BossBarColor]{}, buffer.Enum[BossBarDivision]{})
+	case BossBarUpdateFlags:
+		p.Flags, err = buffer.Byte.Read(r)
+	}
+	return
+}
+func (p *ServerPlayBossBar) WriteVersion(w io.Writer, v Version) (err error) {
+	if err = buffer.Write2(w, buffer.UUID, p.UUID, buffer.Enum[BossBarAction]{}, p.Action); err != nil {
+		return
+	}
+	switch p.Action {
+	case BossBarAdd:
+		return buffer.Write5(w, textComponent(v), p.Title, buffer.Float, p.Health,
+			buffer.Enum[BossBarColor]{}, p.Color, buffer.Enum[BossBarDivision]{}, p.Division, buffer.Byte, p.Flags)
+	case BossBarUpdateHealth:
+		return buffer.Float.Write(w, p.Health)
+	case BossBarUpdateTitle:
+		return textComponent(v).Write(w, p.Title)
+	case BossBarUpdateStyle:
+		return buffer.Write2(w, buffer.Enum[BossBarColor]{}, p.Color, buffer.Enum[BossBarDivision]{}, p.Division)
+	case BossBarUpdateFlags:
+		return buffer.Byte.Write(w, p.Flags)
+	}
+	return nil
+}
+
+// Team friendly flags.
+const (
+	TeamAllowFriendlyFire byte = 1 << iota
+	TeamSeeFriendlyInvisibles
+)
+
+// ServerPlaySetPlayerTeam creates, removes or updates a team. Only the fields used by Method are sent.
+type ServerPlaySetPlayerTeam struct {
+	Name              string
+	Method            TeamMethod
+	DisplayName       text.Component // TeamCreate, TeamUpdateInfo
+	FriendlyFlags     byte           // TeamCreate, TeamUpdateInfo
+	NameTagVisibility string         // TeamCreate, TeamUpdateInfo, for example always or never
+	CollisionRule     string         // TeamCreate, TeamUpdateInfo, for example always or never
+	Color             int32          // TeamCreate, TeamUpdateInfo, the ID of a chat formatting
+	Prefix            text.Component // TeamCreate, TeamUpdateInfo
+	Suffix            text.Component // TeamCreate, TeamUpdateInfo
+	Players           []string       // TeamCreate, TeamAddPlayers, TeamRemovePlayers
+}
+
+func (p *ServerPlaySetPlayerTeam) Direction() Direction { return Clientbound }
+func (p *ServerPlaySetPlayerTeam) ID(state State) int {
+	return stateId1(state, Play, ServerPlaySetPlayerTeamID)
+}
+func (p *ServerPlaySetPlayerTeam) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerPlaySetPlayerTeam) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerPlaySetPlayerTeam) ReadVersion(r io.Reader, v Version) (err error) {
+	var method byte
+	if p.Name, method, err = buffer.Read2(r, buffer.String, buffer.Byte); err != nil {
+		return
+	}
+	if p.Method = TeamMethod(method); !p.Method.Validate() {
+		return fmt.Errorf("invalid team method: %d", method)
+	}
+	if p.Method == TeamCreate || p.Method == TeamUpdateInfo {
+		p.DisplayName, p.FriendlyFlags, p.NameTagVisibility, p.CollisionRule, p.Color, p.Prefix, p.Suffix, err = buffer.Read7(r,
+			textComponent(v), buffer.Byte, buffer.String, buffer.String, buffer.VarInt, textComponent(v), textComponent(v))
+		if err != nil {
+			return
+		}
+	}
+	if p.Method == TeamCreate || p.Method == TeamAddPlayers || p.Method == TeamRemovePlayers {
+		p.Players, err = buffer.List(buffer.String).Read(r)
+	}
+	return
+}
+func (p *ServerPlaySetPlayerTeam) WriteVersion(w io.Writer, v Version) (err error) {
+	if !p.Method.Validate() {
+		return fmt.Errorf("invalid team method: %d", p.Method)
+	}
+	if err = buffer.Write2(w, buffer.String, p.Name, buffer.Byte, byte(p.Method)); err != nil {
+		return
+	}
+	if p.Method == TeamCreate || p.Method == TeamUpdateInfo {
+		err = buffer.Write7(w, textComponent(v), p.DisplayName, buffer.Byte, p.FriendlyFlags,
+			buffer.String, p.NameTagVisibility, buffer.String, p.CollisionRule, buffer.VarInt, p.Color,
+			textComponent(v), p.Prefix, textComponent(v), p.Suffix)
+		if err != nil {
+			return
+		}
+	}
+	if p.Method == TeamCreate || p.Method == TeamAddPlayers || p.Method == TeamRemovePlayers {
+		err = buffer.List(buffer.String).Write(w, p.Players)
+	}
+	return
+}
+
+// ServerPlaySetObjective creates, removes or updates a scoreboard objective. Only the fields used by
+// Mode are sent.
+type ServerPlaySetObjective struct {
+	Name         string
+	Mode         ObjectiveMode
+	DisplayName  text.Component      // ObjectiveCreate, ObjectiveUpdate
+	RenderType   ObjectiveRenderType // ObjectiveCreate, ObjectiveUpdate
+	NumberFormat *NumberFormat       // ObjectiveCreate, ObjectiveUpdate, optional, since 1.20.3
+}
+
+// A NumberFormat controls how scores are displayed.
+type NumberFormat struct {
+	Type    NumberFormatType
+	Style   []byte         // NumberFormatStyled, an NBT compound
+	Content text.Component // NumberFormatFixed
+}
+
+func (p *ServerPlaySetObjective) Direction() Direction { return Clientbound }
+func (p *ServerPlaySetObjective) ID(state State) int {
+	return stateId1(state, Play, ServerPlaySetObjectiveID)
+}
+func (p *ServerPlaySetObjective) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerPlaySetObjective) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerPlaySetObjective) ReadVersion(r io.Reader, v Version) (err error) {
+	var mode byte
+	if p.Name, mode, err = buffer.Read2(r, buffer.String, buffer.Byte); err != nil {
+		return
+	}
+	if p.Mode = ObjectiveMode(mode); !p.Mode.Validate() {
+		return fmt.Errorf("invalid objective mode: %d", mode)
+	}
+	if p.Mode == ObjectiveRemove {
+		return nil
+	}
+	if p.DisplayName, p.RenderType, err = buffer.Read2(r, textComponent(v), buffer.Enum[ObjectiveRenderType]{}); err != nil || v < Version1_20_3 {
+		return
+	}
+	p.NumberFormat, err = readNumberFormat(r)
+	return
+}
+func (p *ServerPlaySetObjective) WriteVersion(w io.Writer, v Version) (err error) {
+	if !p.Mode.Validate() {
+		return fmt.Errorf("invalid objective mode: %d", p.Mode)
+	}
+	if err = buffer.Write2(w, buffer.String, p.Name, buffer.Byte, byte(p.Mode)); err != nil || p.Mode == ObjectiveRemove {
+		return
+	}
+	if err = buffer.Write2(w, textComponent(v), p.DisplayName, buffer.Enum[ObjectiveRenderType]{}, p.RenderType); err != nil || v < Version1_20_3 {
+		return
+	}
+	return writeNumberFormat(w, p.NumberFormat)
+}
+
+func readNumberFormat(r io.Reader) (f *NumberFormat, err error) {
+	var present bool
+	if present, err = buffer.Bool.Read(r); err != nil || !present {
+		return
+	}
+	f = new(NumberFormat)
+	if f.Type, err = (buffer.Enum[NumberFormatType]{}).Read(r); err != nil {
+		return
+	}
+	switch f.Type {
+	case NumberFormatStyled:
+		f.Style, err = buffer.RawNBT.Read(r)
+	case NumberFormatFixed:
+		f.Content, err = buffer.TextComponent.Read(r)
+	}
+	return
+}
+
+func writeNumberFormat(w io.Writer, f *NumberFormat) (err error) {
+	if err = buffer.Bool.Write(w, f != nil); err != nil || f == nil {
+		return
+	}
+	if err = (buffer.Enum[NumberFormatType]{}).Write(w, f.Type); err != nil {
+		return
+	}
+	switch f.Type {
+	case NumberFormatStyled:
+		return buffer.RawNBT.Write(w, f.Style)
+	case NumberFormatFixed:
+		return buffer.TextComponent.Write(w, f.Content)
+	}
+	return nil
+}
+
+type ServerPlaySetDisplayObjective struct {
+	Slot          int32  // 0 is the list, 1 the sidebar, 2 below the name and 3-18 the sidebar of each team color
+	ObjectiveName string // Empty to clear the slot
+}
+
+func (p *ServerPlaySetDisplayObjective) Direction() Direction { return Clientbound }
+func (p *ServerPlaySetDisplayObjective) ID(state State) int {
+	return stateId1(state, Play, ServerPlaySetDisplayObjectiveID)
+}
+func (p *ServerPlaySetDisplayObjective) Read(r io.Reader) (err error) {
+	return buffer.ReadStruct(r, p)
+}
+func (p *ServerPlaySetDisplayObjective) Write(w io.Writer) (err error) {
+	return buffer.WriteStruct(w, p)
+}
+
+type ServerPlayRemoveScore struct {
+	EntityName    string
+	ObjectiveName string `mc:"opt"` // Empty to remove the score from every objective
+}
+
+func (p *ServerPlayRemoveScore) Direction() Direction { return Clientbound }
+func (p *ServerPlayRemoveScore) ID(state State) int {
+	return stateId1(state, Play, ServerPlayRemoveScoreID)
+}
+func (p *ServerPlayRemoveScore) Read(r io.Reader) (err error) {
+	return buffer.ReadStruct(r, p)
+}
+func (p *ServerPlayRemoveScore) Write(w io.Writer) (err error) {
+	return buffer.WriteStruct(w, p)
+}
+
+//kite:packet Play=ServerPlayRemoveEntitiesID
+type ServerPlayRemoveEntities struct {
+	EntityIDs []int32
+}
+
+// ServerPlayBlockEntityData sets the data of a block entity, for example the text of a sign.
+//
+//kite:packet Play=ServerPlayBlockEntityDataID
+type ServerPlayBlockEntityData struct {
+	Location buffer.BlockPos
+	Type     int32 // The block entity type registry ID
+	Data     nbt.Tag
+}
+
+// ServerPlayContainerSetContent replaces every slot of a container, and the item carried by the cursor.
+type ServerPlayContainerSetContent struct {
+	WindowID int32 // 0 for the player inventory
+	StateID  int32
+	Slots    []ItemStack
+	Carried  ItemStack
+}
+
+func (p *ServerPlayContainerSetContent) Direction() Direction { return Clientbound }
+func (p *ServerPlayContainerSetContent) ID(state State) int {
+	return stateId1(state, Play, ServerPlayContainerSetContentID)
+}
+func (p *ServerPlayContainerSetContent) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerPlayContainerSetContent) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerPlayContainerSetContent) ReadVersion(r io.Reader, v Version) (err error) {
+	if v < Version1_21_2 {
+		var windowID byte
+		windowID, err = buffer.Byte.Read(r)
+		p.WindowID = int32(windowID)
+	} else {
+		p.WindowID, err = buffer.VarInt.Read(r)
+	}
+	if err != nil {
+		return
+	}
+	if p.StateID, err = buffer.VarInt.Read(r); err != nil {
+		return
+	}
+	p.Slots, err = buffer.ReadList(r, func() (stack ItemStack, err error) {
+		err = stack.read(r, v)
+		return
+	})
+	if err != nil {
+		return
+	}
+	return p.Carried.read(r, v)
+}
+func (p *ServerPlayContainerSetContent) WriteVersion(w io.Writer, v Version) (err error) {
+	if v < Version1_21_2 {
+		err = buffer.Byte.Write(w, byte(p.WindowID))
+	} else {
+		err = buffer.VarInt.Write(w, p.WindowID)
+	}
+	if err != nil {
+		return
+	}
+	if err = buffer.VarInt.Write(w, p.StateID); err != nil {
+		return
+	}
+	if err = buffer.WriteList(w, p.Slots, func(stack ItemStack) error { return stack.write(w, v) }); err != nil {
+		return
+	}
+	return p.Carried.write(w, v)
+}
+
+// ServerPlayContainerSetSlot replaces one slot of a container.
+type ServerPlayContainerSetSlot struct {
+	// WindowID is 0 for the player inventory. Before 1.21.2, -1 sets the carried item and -2 sets a slot of
+	// the player inventory without opening it.
+	WindowID int32
+	StateID  int32
+	Slot     int16
+	Item     ItemStack
+}
+
+func (p *ServerPlayContainerSetSlot) Direction() Direction { return Clientbound }
+func (p *ServerPlayContainerSetSlot) ID(state State) int {
+	return stateId1(state, Play, ServerPlayContainerSetSlotID)
+}
+func (p *ServerPlayContainerSetSlot) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerPlayContainerSetSlot) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerPlayContainerSetSlot) ReadVersion(r io.Reader, v Version) (err error) {
+	if v < Version1_21_2 {
+		var windowID int8
+		windowID, err = buffer.SignedByte.Read(r)
+		p.WindowID = int32(windowID)
+	} else {
+		p.WindowID, err = buffer.VarInt.Read(r)
+	}
+	if err != nil {
+		return
+	}
+	if p.StateID, p.Slot, err = buffer.Read2(r, buffer.VarInt, buffer.Short); err != nil {
+		return
+	}
+	return p.Item.read(r, v)
+}
+func (p *ServerPlayContainerSetSlot) WriteVersion(w io.Writer, v Version) (err error) {
+	if v < Version1_21_2 {
+		err = buffer.SignedByte.Write(w, int8(p.WindowID))
+	} else {
+		err = buffer.VarInt.Write(w, p.WindowID)
+	}
+	if err != nil {
+		return
+	}
+	if err = buffer.Write2(w, buffer.VarInt, p.StateID, buffer.Short, p.Slot); err != nil {
+		return
+	}
+	return p.Item.write(w, v)
+}
+
+// ServerPlaySetEquipment sets the visible equipment of an entity.
+type ServerPlaySetEquipment struct {
+	EntityID  int32
+	Equipment []Equipment // At least one
+}
+
+type Equipment struct {
+	Slot EquipmentSlot
+	Item ItemStack
+}
+
+// equipmentHasNext is set on the slot of every entry except the last.
+const equipmentHasNext = 0x80
+
+func (p *ServerPlaySetEquipment) Direction() Direction { return Clientbound }
+func (p *ServerPlaySetEquipment) ID(state State) int {
+	return stateId1(state, Play, ServerPlaySetEquipmentID)
+}
+func (p *ServerPlaySetEquipment) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ServerPlaySetEquipment) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ServerPlaySetEquipment) ReadVersion(r io.Reader, v Version) (err error) {
+	if p.EntityID, err = buffer.VarInt.Read(r); err != nil {
+		return
+	}
+	p.Equipment = nil
+	for {
+		var slot byte
+		if slot, err = buffer.Byte.Read(r); err != nil {
+			return
+		}
+		entry := Equipment{Slot: EquipmentSlot(slot &^ equipmentHasNext)}
+		if !entry.Slot.Validate() {
+			return fmt.Errorf("invalid equipment slot: %d", entry.Slot)
+		}
+		if err = entry.Item.read(r, v); err != nil {
+			return
+		}
+		p.Equipment = append(p.Equipment, entry)
+		if slot&equipmentHasNext == 0 {
+			return nil
+		}
+	}
+}
+func (p *ServerPlaySetEquipment) WriteVersion(w io.Writer, v Version) (err error) {
+	if len(p.Equipment) == 0 {
+		return fmt.Errorf("no equipment for entity %d", p.EntityID)
+	}
+	if err = buffer.VarInt.Write(w, p.EntityID); err != nil {
+		return
+	}
+	for i, entry := range p.Equipment {
+		slot := byte(entry.Slot)
+		if i < len(p.Equipment)-1 {
+			slot |= equipmentHasNext
+		}
+		if err = buffer.Byte.Write(w, slot); err != nil {
+			return
+		}
+		if err = entry.Item.write(w, v); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// ClientPlaySetCreativeModeSlot sets a slot of the player inventory in creative mode.
+type ClientPlaySetCreativeModeSlot struct {
+	Slot int16 // -1 to drop the item
+	Item ItemStack
+}
+
+func (p *ClientPlaySetCreativeModeSlot) Direction() Direction { return Serverbound }
+func (p *ClientPlaySetCreativeModeSlot) ID(state State) int {
+	return stateId1(state, Play, ClientPlaySetCreativeModeSlotID)
+}
+func (p *ClientPlaySetCreativeModeSlot) Read(r io.Reader) (err error) {
+	return p.ReadVersion(r, LatestVersion)
+}
+func (p *ClientPlaySetCreativeModeSlot) Write(w io.Writer) (err error) {
+	return p.WriteVersion(w, LatestVersion)
+}
+func (p *ClientPlaySetCreativeModeSlot) ReadVersion(r io.Reader, v Version) (err error) {
+	if p.Slot, err = buffer.Short.Read(r); err != nil {
+		return
+	}
+	return p.Item.read(r, v)
+}
+func (p *ClientPlaySetCreativeModeSlot) WriteVersion(w io.Writer, v Version) (err error) {
+	if err = buffer.Short.Write(w, p.Slot); err != nil {
+		return
+	}
+	return p.Item.write(w, v)
+}
+
 var (
-	_ Packet = (*ClientConfigurationAck)(nil)
+	_ Packet          = (*ClientConfigurationAck)(nil)
+	_ VersionedPacket = (*ClientPlaySetCreativeModeSlot)(nil)
 
-	_ Packet = (*ServerStartConfiguration)(nil)
+	_ Packet          = (*ServerStartConfiguration)(nil)
+	_ VersionedPacket = (*ServerPlayLogin)(nil)
+	_ VersionedPacket = (*ServerPlayRespawn)(nil)
+	_ VersionedPacket = (*ServerPlayPlayerInfoUpdate)(nil)
+	_ VersionedPacket = (*ServerPlayBossBar)(nil)
+	_ VersionedPacket = (*ServerPlaySetPlayerTeam)(nil)
+	_ VersionedPacket = (*ServerPlaySetObjective)(nil)
+	_ Packet          = (*ServerPlaySetDisplayObjective)(nil)
+	_ Packet          = (*ServerPlayRemoveScore)(nil)
+	_ VersionedPacket = (*ServerPlayContainerSetContent)(nil)
+	_ VersionedPacket = (*ServerPlayContainerSetSlot)(nil)
+	_ VersionedPacket = (*ServerPlaySetEquipment)(nil)
 )
@@ -0,0 +1,138 @@
+package packet
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/mojang"
+	"github.com/mworzala/kite/pkg/nbt"
+	"github.com/mworzala/kite/pkg/text"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayPackets(t *testing.T) {
+	spawnInfo := func(v Version) SpawnInfo {
+		info := SpawnInfo{
+			DimensionName: "minecraft:overworld", HashedSeed: -1234, GameMode: GameModeCreative,
+			PreviousGameMode: GameModeUndefined, IsFlat: true, PortalCooldown: 20,
+			DeathLocation: &DeathLocation{Dimension: "minecraft:the_nether", Position: buffer.BlockPos{X: -30000000, Y: -64, Z: 123}},
+		}
+		if v < Version1_20_5 {
+			info.DimensionTypeName = "minecraft:overworld"
+		} else {
+			info.DimensionType = 2
+		}
+		if v >= Version1_21_2 {
+			info.SeaLevel = 63
+		}
+		return info
+	}
+	allActions := PlayerInfoAddPlayer | PlayerInfoInitializeChat | PlayerInfoUpdateGameMode | PlayerInfoUpdateListed |
+		PlayerInfoUpdateLatency | PlayerInfoUpdateDisplayName | PlayerInfoUpdateListPriority
+
+	for v := OldestVersion; v <= LatestVersion; v++ {
+		var listPriority int32
+		var numberFormat *NumberFormat
+		if v >= Version1_21_2 {
+			listPriority = 5
+		}
+		if v >= Version1_20_3 {
+			numberFormat = &NumberFormat{Type: NumberFormatFixed, Content: &text.Text{Text: "-"}}
+		}
+
+		for _, pkt := range []Packet{
+			&ClientPlayChat{Message: "hello", Timestamp: 1700000000000, Salt: -42, MessageCount: 3,
+				Acknowledged: buffer.Bits{0b101}},
+			&ClientPlayChat{Message: "signed", Signature: bytes.Repeat([]byte{0xab}, 256),
+				Acknowledged: buffer.Bits{1 << 19}},
+			&ClientConfigurationAck{},
+
+			&ServerStartConfiguration{},
+			&ServerPlayLogin{
+				EntityID: 7, DimensionNames: []string{"minecraft:overworld", "minecraft:the_nether"},
+				MaxPlayers: 100, ViewDistance: 10, SimulationDistance: 8, EnableRespawnScreen: true,
+				SpawnInfo: spawnInfo(v), EnforcesSecureChat: v >= Version1_20_5,
+			},
+			&ServerPlayRespawn{SpawnInfo: spawnInfo(v), DataKept: RespawnKeepAttributes | RespawnKeepMetadata},
+			&ServerPlayPlayerInfoUpdate{Actions: allActions, Entries: []PlayerInfoEntry{
+				{
+					UUID: uuid.New(), Name: "kite",
+					Properties:  []mojang.ProfileProperty{{Name: "textures", Value: "e30=", Signature: "c2ln"}},
+					ChatSession: &ChatSession{ID: uuid.New(), ExpiresAt: 1700000000000, PublicKey: []byte{1}, KeySignature: []byte{2}},
+					GameMode:    GameModeSurvival, Listed: true, Latency: 42,
+					DisplayName: &text.Text{Text: "Kite"}, ListPriority: listPriority,
+				},
+				{UUID: uuid.New(), Name: "other", Properties: []mojang.ProfileProperty{}, GameMode: GameModeSpectator},
+			}},
+			&ServerPlayPlayerInfoUpdate{Actions: PlayerInfoUpdateLatency, Entries: []PlayerInfoEntry{{UUID: uuid.New(), Latency: 100}}},
+			&ServerPlayPlayerInfoRemove{UUIDs: []uuid.UUID{uuid.New(), uuid.New()}},
+			&ServerPlayBossBar{UUID: uuid.New(), Action: BossBarAdd, Title: &text.Text{Text: "Boss"}, Health: 0.5,
+				Color: BossBarPurple, Division: BossBarNotched10, Flags: BossBarCreateFog},
+			&ServerPlayBossBar{UUID: uuid.New(), Action: BossBarRemove},
+			&ServerPlayBossBar{UUID: uuid.New(), Action: BossBarUpdateHealth, Health: 1},
+			&ServerPlayBossBar{UUID: uuid.New(), Action: BossBarUpdateTitle, Title: &text.Text{Text: "Title"}},
+			&ServerPlayBossBar{UUID: uuid.New(), Action: BossBarUpdateStyle, Color: BossBarWhite, Division: BossBarNotched20},
+			&ServerPlayBossBar{UUID: uuid.New(), Action: BossBarUpdateFlags, Flags: BossBarDarkenScreen},
+			&ServerPlaySetPlayerTeam{Name: "red", Method: TeamCreate, DisplayName: &text.Text{Text: "Red"},
+				FriendlyFlags: TeamAllowFriendlyFire, NameTagVisibility: "always", CollisionRule: "never", Color: 12,
+				Prefix: &text.Text{Text: "["}, Suffix: &text.Text{Text: "]"}, Players: []string{"kite"}},
+			&ServerPlaySetPlayerTeam{Name: "red", Method: TeamRemove},
+			&ServerPlaySetPlayerTeam{Name: "red", Method: TeamRemovePlayers, Players: []string{"kite"}},
+			&ServerPlaySetObjective{Name: "kills", Mode: ObjectiveCreate, DisplayName: &text.Text{Text: "Kills"},
+				RenderType: ObjectiveHearts, NumberFormat: numberFormat},
+			&ServerPlaySetObjective{Name: "kills", Mode: ObjectiveRemove},
+			&ServerPlaySetDisplayObjective{Slot: 1, ObjectiveName: "kills"},
+			&ServerPlayRemoveEntities{EntityIDs: []int32{1, 2, 3}},
+			&ServerPlayBlockEntityData{Location: buffer.BlockPos{X: 1, Y: 64, Z: -1}, Type: 7, Data: nbt.Compound{
+				"front_text": nbt.Compound{"messages": nbt.List{nbt.String(`"kite"`), nbt.String(`""`)}},
+				"is_waxed":   nbt.Bool(true),
+			}},
+			&ServerPlayContainerSetContent{WindowID: 1, StateID: 3,
+				Slots: []ItemStack{testItemStack(t, v), {}}, Carried: testItemStack(t, v)},
+			&ServerPlayContainerSetSlot{WindowID: 1, StateID: 4, Slot: 36, Item: testItemStack(t, v)},
+			&ServerPlaySetEquipment{EntityID: 7, Equipment: []Equipment{
+				{Slot: EquipmentMainHand, Item: testItemStack(t, v)}, {Slot: EquipmentHead},
+			}},
+			&ClientPlaySetCreativeModeSlot{Slot: -1, Item: testItemStack(t, v)},
+		} {
+			t.Run(v.String()+"/"+reflect.TypeOf(pkt).Elem().Name(), func(t *testing.T) {
+				_, ok := v.WireID(Play, pkt.Direction(), pkt.ID(Play))
+				require.True(t, ok)
+				requireRoundTrip(t, pkt, v)
+			})
+		}
+	}
+}
+
+func TestServerPlaySetEquipment(t *testing.T) {
+	pkt := &ServerPlaySetEquipment{EntityID: 1, Equipment: []Equipment{{Slot: EquipmentOffHand}, {Slot: EquipmentBody}}}
+	var buf bytes.Buffer
+	require.NoError(t, pkt.WriteVersion(&buf, LatestVersion))
+	require.Equal(t, []byte{0x01, 0x81, 0x00, 0x06, 0x00}, buf.Bytes())
+
+	require.Error(t, (&ServerPlaySetEquipment{EntityID: 1}).Write(&buf))
+	require.Error(t, new(ServerPlaySetEquipment).Read(bytes.NewReader([]byte{0x01, 0x07, 0x00})))
+}
+
+func TestServerPlayRemoveScore(t *testing.T) {
+	requireRoundTrip(t, &ServerPlayRemoveScore{EntityName: "kite", ObjectiveName: "kills"}, LatestVersion)
+	requireRoundTrip(t, &ServerPlayRemoveScore{EntityName: "kite"}, LatestVersion)
+
+	_, ok := Version1_20_2.WireID(Play, Clientbound, ServerPlayRemoveScoreID)
+	require.False(t, ok)
+}
+
+func TestServerPlaySetObjective_NumberFormat(t *testing.T) {
+	for _, format := range []*NumberFormat{
+		nil,
+		{Type: NumberFormatBlank},
+		{Type: NumberFormatStyled, Style: testNBT},
+		{Type: NumberFormatFixed, Content: &text.Text{Text: "fixed"}},
+	} {
+		requireRoundTrip(t, &ServerPlaySetObjective{Name: "kills", Mode: ObjectiveUpdate,
+			DisplayName: &text.Text{Text: "Kills"}, NumberFormat: format}, LatestVersion)
+	}
+}
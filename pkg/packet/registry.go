@@ -0,0 +1,203 @@
+package packet
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/mworzala/kite/pkg/buffer"
+)
+
+// A Factory creates a new, empty packet to be read into.
+type Factory func() Packet
+
+type registryKey struct {
+	state     State
+	direction Direction
+	id        int
+}
+
+// A Registry maps a state, direction and packet ID to the Packet type which should be used to decode it.
+// It is safe for concurrent use, though packets are typically registered once during startup.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[registryKey]Factory
+}
+
+// DefaultRegistry contains every packet implemented by this package.
+var DefaultRegistry = newDefaultRegistry()
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[registryKey]Factory)}
+}
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, factory := range []Factory{
+		// Handshake
+		func() Packet { return new(ClientHandshake) },
+		// Status
+		func() Packet { return new(ClientStatusRequest) },
+		func() Packet { return new(ClientStatusPingRequest) },
+		func() Packet { return new(ServerStatusResponse) },
+		func() Packet { return new(ServerStatusPingResponse) },
+		// Login
+		func() Packet { return new(ClientLoginStart) },
+		func() Packet { return new(ClientEncryptionResponse) },
+		func() Packet { return new(ClientLoginPluginResponse) },
+		func() Packet { return new(ClientLoginAcknowledged) },
+		func() Packet { return new(ServerLoginDisconnect) },
+		func() Packet { return new(ServerEncryptionRequest) },
+		func() Packet { return new(ServerLoginSuccess) },
+		func() Packet { return new(ServerLoginSetCompression) },
+		func() Packet { return new(ServerLoginPluginRequest) },
+		// Login, Config & Play
+		func() Packet { return new(ClientCookieResponse) },
+		func() Packet { return new(ServerCookieRequest) },
+		// Config & Play
+		func() Packet { return new(ClientInformation) },
+		func() Packet { return new(ClientResourcePackStatus) },
+		func() Packet { return new(ClientKeepAlive) },
+		func() Packet { return new(ClientPluginMessage) },
+		func() Packet { return new(ClientPong) },
+		func() Packet { return new(ServerResourcePackPush) },
+		func() Packet { return new(ServerResourcePackPop) },
+		func() Packet { return new(ServerKeepAlive) },
+		func() Packet { return new(ServerPluginMessage) },
+		func() Packet { return new(ServerDisconnect) },
+		func() Packet { return new(ServerPing) },
+		func() Packet { return new(ServerStoreCookie) },
+		func() Packet { return new(ServerTransfer) },
+		func() Packet { return new(ServerUpdateTags) },
+		func() Packet { return new(ServerCustomReportDetails) },
+		func() Packet { return new(ServerLinks) },
+		// Config
+		func() Packet { return new(ClientConfigFinishConfiguration) },
+		func() Packet { return new(ClientConfigKnownPacks) },
+		func() Packet { return new(ServerConfigFinishConfiguration) },
+		func() Packet { return new(ServerConfigResetChat) },
+		func() Packet { return new(ServerConfigRegistryData) },
+		func() Packet { return new(ServerConfigFeatureFlags) },
+		func() Packet { return new(ServerConfigKnownPacks) },
+		// Play
+		func() Packet { return new(ClientPlayChat) },
+		func() Packet { return new(ClientConfigurationAck) },
+		func() Packet { return new(ServerStartConfiguration) },
+		func() Packet { return new(ServerPlayLogin) },
+		func() Packet { return new(ServerPlayRespawn) },
+		func() Packet { return new(ServerPlayPlayerInfoUpdate) },
+		func() Packet { return new(ServerPlayPlayerInfoRemove) },
+		func() Packet { return new(ServerPlayBossBar) },
+		func() Packet { return new(ServerPlaySetPlayerTeam) },
+		func() Packet { return new(ServerPlaySetObjective) },
+		func() Packet { return new(ServerPlaySetDisplayObjective) },
+		func() Packet { return new(ServerPlayRemoveScore) },
+		func() Packet { return new(ServerPlayRemoveEntities) },
+		func() Packet { return new(ServerPlayBlockEntityData) },
+		func() Packet { return new(ServerPlayContainerSetContent) },
+		func() Packet { return new(ServerPlayContainerSetSlot) },
+		func() Packet { return new(ServerPlaySetEquipment) },
+		func() Packet { return new(ClientPlaySetCreativeModeSlot) },
+	} {
+		r.Register(factory)
+	}
+	return r
+}
+
+// Register adds the packet created by factory in every state where it has an ID, replacing any packet
+// previously registered with the same state, direction and ID.
+func (r *Registry) Register(factory Factory) {
+	sample := factory()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for state := Handshake; state <= Play; state++ {
+		if id := sample.ID(state); id != InvalidState {
+			r.factories[registryKey{state, sample.Direction(), id}] = factory
+		}
+	}
+}
+
+// RegisterID adds the packet created by factory for a single state, direction and ID, for packets
+// which do not report their own IDs (or to override them).
+func (r *Registry) RegisterID(state State, direction Direction, id int, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[registryKey{state, direction, id}] = factory
+}
+
+// Lookup creates a new packet of the type registered for the given state, direction and ID.
+// ok is false if there is no such packet.
+func (r *Registry) Lookup(state State, direction Direction, id int) (pkt Packet, ok bool) {
+	r.mu.RLock()
+	factory, ok := r.factories[registryKey{state, direction, id}]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// New is like Lookup, but returns a RawPacket if there is no registered packet.
+func (r *Registry) New(state State, direction Direction, id int) Packet {
+	if pkt, ok := r.Lookup(state, direction, id); ok {
+		return pkt
+	}
+	return NewRawPacket(state, direction, id, nil)
+}
+
+// Clone returns a copy of the registry, for example to extend DefaultRegistry without modifying it.
+func (r *Registry) Clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	clone := NewRegistry()
+	for k, v := range r.factories {
+		clone.factories[k] = v
+	}
+	return clone
+}
+
+// A RawPacket is a packet with an unknown structure, holding its encoded payload (excluding the ID).
+type RawPacket struct {
+	Data []byte
+
+	state     State
+	direction Direction
+	id        int
+}
+
+// NewRawPacket creates a raw packet with the given ID, only valid in the given state.
+func NewRawPacket(state State, direction Direction, id int, data []byte) *RawPacket {
+	return &RawPacket{Data: data, state: state, direction: direction, id: id}
+}
+
+func (p *RawPacket) Direction() Direction { return p.direction }
+func (p *RawPacket) ID(state State) int {
+	return stateId1(state, p.state, p.id)
+}
+func (p *RawPacket) Read(r io.Reader) (err error) {
+	p.Data, err = buffer.RawBytes.Read(r)
+	return
+}
+func (p *RawPacket) Write(w io.Writer) (err error) {
+	return buffer.RawBytes.Write(w, p.Data)
+}
+
+func (p *RawPacket) String() string {
+	return fmt.Sprintf("RawPacket{%s %s 0x%02x, %d bytes}", p.direction, p.state, p.id, len(p.Data))
+}
+
+func (p *RawPacket) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("Direction", p.direction.String()),
+		slog.String("State", p.state.String()),
+		slog.Int("ID", p.id),
+	}
+	if name, ok := PacketName(p.state, p.direction, p.id); ok {
+		attrs = append(attrs, slog.String("Name", name))
+	}
+	return slog.GroupValue(append(attrs, slog.Int("Size", len(p.Data)))...)
+}
+
+var _ Packet = (*RawPacket)(nil)
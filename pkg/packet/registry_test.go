@@ -0,0 +1,69 @@
+package packet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	pkt, ok := DefaultRegistry.Lookup(Config, Serverbound, ClientConfigKeepAliveID)
+	require.True(t, ok)
+	require.IsType(t, new(ClientKeepAlive), pkt)
+
+	pkt, ok = DefaultRegistry.Lookup(Play, Clientbound, ServerPlayKeepAliveID)
+	require.True(t, ok)
+	require.IsType(t, new(ServerKeepAlive), pkt)
+
+	// Same ID, other direction
+	_, ok = DefaultRegistry.Lookup(Handshake, Clientbound, 0)
+	require.False(t, ok)
+
+	// Every registered packet reports the ID it is registered with.
+	for key, factory := range DefaultRegistry.factories {
+		pkt := factory()
+		require.Equal(t, key.direction, pkt.Direction(), "%T", pkt)
+		require.Equal(t, key.id, pkt.ID(key.state), "%T", pkt)
+	}
+}
+
+type customPacket struct {
+	Value string
+}
+
+func (p *customPacket) Direction() Direction { return Clientbound }
+func (p *customPacket) ID(state State) int {
+	return stateId1(state, Play, ServerPlaySystemChatID)
+}
+func (p *customPacket) Read(r io.Reader) (err error) {
+	p.Value, err = buffer.String.Read(r)
+	return
+}
+func (p *customPacket) Write(w io.Writer) (err error) {
+	return buffer.String.Write(w, p.Value)
+}
+
+func TestRegistry_Custom(t *testing.T) {
+	r := DefaultRegistry.Clone()
+	r.Register(func() Packet { return new(customPacket) })
+	r.RegisterID(Config, Clientbound, 0x7F, func() Packet { return new(customPacket) })
+
+	require.IsType(t, new(customPacket), r.New(Play, Clientbound, ServerPlaySystemChatID))
+	require.IsType(t, new(customPacket), r.New(Config, Clientbound, 0x7F))
+	require.IsType(t, new(RawPacket), DefaultRegistry.New(Play, Clientbound, ServerPlaySystemChatID))
+}
+
+func TestRawPacket(t *testing.T) {
+	pkt := DefaultRegistry.New(Play, Clientbound, ServerPlaySystemChatID)
+	require.NoError(t, pkt.Read(bytes.NewReader([]byte{1, 2, 3})))
+	require.Equal(t, ServerPlaySystemChatID, pkt.ID(Play))
+	require.Equal(t, InvalidState, pkt.ID(Config))
+	require.Equal(t, Clientbound, pkt.Direction())
+
+	var out bytes.Buffer
+	require.NoError(t, pkt.Write(&out))
+	require.Equal(t, []byte{1, 2, 3}, out.Bytes())
+}
@@ -7,11 +7,6 @@ import (
 	"github.com/mworzala/kite/pkg/buffer"
 )
 
-const (
-	ClientStatusStatusRequestID = iota
-	ClientStatusPingRequestID
-)
-
 type ClientStatusRequest struct{}
 
 func (p *ClientStatusRequest) Direction() Direction { return Serverbound }
@@ -39,11 +34,6 @@ func (p *ClientStatusPingRequest) Write(w io.Writer) (err error) {
 
 // Server
 
-const (
-	ServerStatusStatusResponseID = iota
-	ServerStatusPingResponseID
-)
-
 type (
 	ServerStatusResponse struct {
 		Payload StatusResponse
@@ -38,3 +38,406 @@ func (s ResourcePackStatus) String() string {
 	}
 	return "unknown"
 }
+
+type ChatMode int
+
+const (
+	ChatModeEnabled ChatMode = iota
+	ChatModeCommandsOnly
+	ChatModeHidden
+)
+
+func (m ChatMode) Validate() bool {
+	return m >= ChatModeEnabled && m <= ChatModeHidden
+}
+
+func (m ChatMode) String() string {
+	switch m {
+	case ChatModeEnabled:
+		return "enabled"
+	case ChatModeCommandsOnly:
+		return "commands_only"
+	case ChatModeHidden:
+		return "hidden"
+	}
+	return "unknown"
+}
+
+type MainHand int
+
+const (
+	MainHandLeft MainHand = iota
+	MainHandRight
+)
+
+func (h MainHand) Validate() bool {
+	return h == MainHandLeft || h == MainHandRight
+}
+
+func (h MainHand) String() string {
+	switch h {
+	case MainHandLeft:
+		return "left"
+	case MainHandRight:
+		return "right"
+	}
+	return "unknown"
+}
+
+type ParticleStatus int
+
+const (
+	ParticleStatusAll ParticleStatus = iota
+	ParticleStatusDecreased
+	ParticleStatusMinimal
+)
+
+func (s ParticleStatus) Validate() bool {
+	return s >= ParticleStatusAll && s <= ParticleStatusMinimal
+}
+
+func (s ParticleStatus) String() string {
+	switch s {
+	case ParticleStatusAll:
+		return "all"
+	case ParticleStatusDecreased:
+		return "decreased"
+	case ParticleStatusMinimal:
+		return "minimal"
+	}
+	return "unknown"
+}
+
+// A ServerLinkLabel is one of the labels built into the client for ServerLink.
+type ServerLinkLabel int
+
+const (
+	ServerLinkBugReport ServerLinkLabel = iota
+	ServerLinkCommunityGuidelines
+	ServerLinkSupport
+	ServerLinkStatus
+	ServerLinkFeedback
+	ServerLinkCommunity
+	ServerLinkWebsite
+	ServerLinkForums
+	ServerLinkNews
+	ServerLinkAnnouncements
+)
+
+func (l ServerLinkLabel) Validate() bool {
+	return l >= ServerLinkBugReport && l <= ServerLinkAnnouncements
+}
+
+func (l ServerLinkLabel) String() string {
+	switch l {
+	case ServerLinkBugReport:
+		return "bug_report"
+	case ServerLinkCommunityGuidelines:
+		return "community_guidelines"
+	case ServerLinkSupport:
+		return "support"
+	case ServerLinkStatus:
+		return "status"
+	case ServerLinkFeedback:
+		return "feedback"
+	case ServerLinkCommunity:
+		return "community"
+	case ServerLinkWebsite:
+		return "website"
+	case ServerLinkForums:
+		return "forums"
+	case ServerLinkNews:
+		return "news"
+	case ServerLinkAnnouncements:
+		return "announcements"
+	}
+	return "unknown"
+}
+
+type GameMode int8
+
+const (
+	GameModeUndefined GameMode = iota - 1 // Only valid as the previous game mode
+	GameModeSurvival
+	GameModeCreative
+	GameModeAdventure
+	GameModeSpectator
+)
+
+func (m GameMode) Validate() bool {
+	return m >= GameModeUndefined && m <= GameModeSpectator
+}
+
+func (m GameMode) String() string {
+	switch m {
+	case GameModeUndefined:
+		return "undefined"
+	case GameModeSurvival:
+		return "survival"
+	case GameModeCreative:
+		return "creative"
+	case GameModeAdventure:
+		return "adventure"
+	case GameModeSpectator:
+		return "spectator"
+	}
+	return "unknown"
+}
+
+// PlayerInfoActions is a bit set of the fields sent by ServerPlayPlayerInfoUpdate.
+type PlayerInfoActions byte
+
+const (
+	PlayerInfoAddPlayer PlayerInfoActions = 1 << iota
+	PlayerInfoInitializeChat
+	PlayerInfoUpdateGameMode
+	PlayerInfoUpdateListed
+	PlayerInfoUpdateLatency
+	PlayerInfoUpdateDisplayName
+	PlayerInfoUpdateListPriority // Since 1.21.2
+)
+
+func (a PlayerInfoActions) Has(action PlayerInfoActions) bool {
+	return a&action != 0
+}
+
+type BossBarAction int
+
+const (
+	BossBarAdd BossBarAction = iota
+	BossBarRemove
+	BossBarUpdateHealth
+	BossBarUpdateTitle
+	BossBarUpdateStyle
+	BossBarUpdateFlags
+)
+
+func (a BossBarAction) Validate() bool {
+	return a >= BossBarAdd && a <= BossBarUpdateFlags
+}
+
+func (a BossBarAction) String() string {
+	switch a {
+	case BossBarAdd:
+		return "add"
+	case BossBarRemove:
+		return "remove"
+	case BossBarUpdateHealth:
+		return "update_health"
+	case BossBarUpdateTitle:
+		return "update_title"
+	case BossBarUpdateStyle:
+		return "update_style"
+	case BossBarUpdateFlags:
+		return "update_flags"
+	}
+	return "unknown"
+}
+
+type BossBarColor int
+
+const (
+	BossBarPink BossBarColor = iota
+	BossBarBlue
+	BossBarRed
+	BossBarGreen
+	BossBarYellow
+	BossBarPurple
+	BossBarWhite
+)
+
+func (c BossBarColor) Validate() bool {
+	return c >= BossBarPink && c <= BossBarWhite
+}
+
+func (c BossBarColor) String() string {
+	switch c {
+	case BossBarPink:
+		return "pink"
+	case BossBarBlue:
+		return "blue"
+	case BossBarRed:
+		return "red"
+	case BossBarGreen:
+		return "green"
+	case BossBarYellow:
+		return "yellow"
+	case BossBarPurple:
+		return "purple"
+	case BossBarWhite:
+		return "white"
+	}
+	return "unknown"
+}
+
+type BossBarDivision int
+
+const (
+	BossBarProgress BossBarDivision = iota
+	BossBarNotched6
+	BossBarNotched10
+	BossBarNotched12
+	BossBarNotched20
+)
+
+func (d BossBarDivision) Validate() bool {
+	return d >= BossBarProgress && d <= BossBarNotched20
+}
+
+func (d BossBarDivision) String() string {
+	switch d {
+	case BossBarProgress:
+		return "progress"
+	case BossBarNotched6:
+		return "notched_6"
+	case BossBarNotched10:
+		return "notched_10"
+	case BossBarNotched12:
+		return "notched_12"
+	case BossBarNotched20:
+		return "notched_20"
+	}
+	return "unknown"
+}
+
+// Boss bar flags.
+const (
+	BossBarDarkenScreen byte = 1 << iota
+	BossBarPlayMusic
+	BossBarCreateFog
+)
+
+type TeamMethod byte
+
+const (
+	TeamCreate TeamMethod = iota
+	TeamRemove
+	TeamUpdateInfo
+	TeamAddPlayers
+	TeamRemovePlayers
+)
+
+func (m TeamMethod) Validate() bool {
+	return m <= TeamRemovePlayers
+}
+
+func (m TeamMethod) String() string {
+	switch m {
+	case TeamCreate:
+		return "create"
+	case TeamRemove:
+		return "remove"
+	case TeamUpdateInfo:
+		return "update_info"
+	case TeamAddPlayers:
+		return "add_players"
+	case TeamRemovePlayers:
+		return "remove_players"
+	}
+	return "unknown"
+}
+
+type ObjectiveMode byte
+
+const (
+	ObjectiveCreate ObjectiveMode = iota
+	ObjectiveRemove
+	ObjectiveUpdate
+)
+
+func (m ObjectiveMode) Validate() bool {
+	return m <= ObjectiveUpdate
+}
+
+func (m ObjectiveMode) String() string {
+	switch m {
+	case ObjectiveCreate:
+		return "create"
+	case ObjectiveRemove:
+		return "remove"
+	case ObjectiveUpdate:
+		return "update"
+	}
+	return "unknown"
+}
+
+type ObjectiveRenderType int
+
+const (
+	ObjectiveInteger ObjectiveRenderType = iota
+	ObjectiveHearts
+)
+
+func (t ObjectiveRenderType) Validate() bool {
+	return t == ObjectiveInteger || t == ObjectiveHearts
+}
+
+func (t ObjectiveRenderType) String() string {
+	switch t {
+	case ObjectiveInteger:
+		return "integer"
+	case ObjectiveHearts:
+		return "hearts"
+	}
+	return "unknown"
+}
+
+type NumberFormatType int
+
+const (
+	NumberFormatBlank NumberFormatType = iota
+	NumberFormatStyled
+	NumberFormatFixed
+)
+
+func (t NumberFormatType) Validate() bool {
+	return t >= NumberFormatBlank && t <= NumberFormatFixed
+}
+
+func (t NumberFormatType) String() string {
+	switch t {
+	case NumberFormatBlank:
+		return "blank"
+	case NumberFormatStyled:
+		return "styled"
+	case NumberFormatFixed:
+		return "fixed"
+	}
+	return "unknown"
+}
+
+type EquipmentSlot byte
+
+const (
+	EquipmentMainHand EquipmentSlot = iota
+	EquipmentOffHand
+	EquipmentFeet
+	EquipmentLegs
+	EquipmentChest
+	EquipmentHead
+	EquipmentBody // Since 1.20.5, the armor of horses and wolves
+)
+
+func (s EquipmentSlot) Validate() bool {
+	return s <= EquipmentBody
+}
+
+func (s EquipmentSlot) String() string {
+	switch s {
+	case EquipmentMainHand:
+		return "mainhand"
+	case EquipmentOffHand:
+		return "offhand"
+	case EquipmentFeet:
+		return "feet"
+	case EquipmentLegs:
+		return "legs"
+	case EquipmentChest:
+		return "chest"
+	case EquipmentHead:
+		return "head"
+	case EquipmentBody:
+		return "body"
+	}
+	return "unknown"
+}
@@ -0,0 +1,140 @@
+package packet
+
+import (
+	"fmt"
+	"io"
+)
+
+//go:generate go run ../../internal/cmd/packetgen
+
+// A Version is a protocol version number, as sent by the client in ClientHandshake. The supported
+// versions and their packet IDs are generated from the files in data.
+type Version int32
+
+// Supported returns whether packet IDs are known for the version. The handshake and status states are
+// the same in every version, so can be used to answer server list pings from unsupported clients.
+func (v Version) Supported() bool {
+	return v >= OldestVersion && v <= LatestVersion
+}
+
+func (v Version) String() string {
+	if name, ok := versionNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown (%d)", int32(v))
+}
+
+// WireID returns the ID used on the wire by version v for the packet with the given ID constant.
+// ok is false if the packet does not exist in the version.
+func (v Version) WireID(state State, direction Direction, id int) (wireID int, ok bool) {
+	table := v.table(state, direction)
+	if table == nil || id < 0 || id >= len(table.toWire) || table.toWire[id] < 0 {
+		return InvalidState, false
+	}
+	return table.toWire[id], true
+}
+
+// PacketID returns the ID constant for a packet ID received from version v, the inverse of WireID.
+// ok is false if the ID is not valid in the version.
+func (v Version) PacketID(state State, direction Direction, wireID int) (id int, ok bool) {
+	table := v.table(state, direction)
+	if table == nil || wireID < 0 || wireID >= len(table.fromWire) {
+		return InvalidState, false
+	}
+	return table.fromWire[wireID], true
+}
+
+// PacketName returns the name of the packet with the given ID constant, for example KeepAlive for
+// ClientPlayKeepAliveID. ok is false if there is no such packet.
+func PacketName(state State, direction Direction, id int) (name string, ok bool) {
+	if !state.Validate() || (direction != Clientbound && direction != Serverbound) {
+		return "", false
+	}
+	names := packetNames[state][direction]
+	if id < 0 || id >= len(names) {
+		return "", false
+	}
+	return names[id], true
+}
+
+func (v Version) table(state State, direction Direction) *idTable {
+	if !state.Validate() || (direction != Clientbound && direction != Serverbound) {
+		return nil
+	}
+	tables, ok := versionTables[v]
+	if !ok {
+		if state > Status {
+			return nil
+		}
+		tables = versionTables[LatestVersion]
+	}
+	return tables[state][direction]
+}
+
+// A VersionedPacket is a Packet whose encoding differs between protocol versions. Its Read and Write
+// methods use LatestVersion.
+type VersionedPacket interface {
+	Packet
+
+	ReadVersion(r io.Reader, v Version) error
+	WriteVersion(w io.Writer, v Version) error
+}
+
+// Read reads pkt as encoded by version v.
+func Read(r io.Reader, pkt Packet, v Version) error {
+	if vp, ok := pkt.(VersionedPacket); ok {
+		return vp.ReadVersion(r, v)
+	}
+	return pkt.Read(r)
+}
+
+// Write writes pkt as encoded by version v.
+func Write(w io.Writer, pkt Packet, v Version) error {
+	if vp, ok := pkt.(VersionedPacket); ok {
+		return vp.WriteVersion(w, v)
+	}
+	return pkt.Write(w)
+}
+
+// idTable maps between the ID constants and the wire IDs of a version, for one state and direction.
+type idTable struct {
+	toWire   []int // Indexed by ID constant, -1 if the packet does not exist
+	fromWire []int // Indexed by wire ID
+}
+
+func newIDTable(ids idList) *idTable {
+	size := 0
+	for _, id := range ids {
+		size = max(size, id+1)
+	}
+	t := &idTable{fromWire: ids, toWire: make([]int, size)}
+	for i := range t.toWire {
+		t.toWire[i] = -1
+	}
+	for wireID, id := range ids {
+		t.toWire[id] = wireID
+	}
+	return t
+}
+
+// An idList holds the ID constants of the packets in a state and direction, in wire ID order.
+type idList []int
+
+// versionIDs holds the packets of a version for every state and direction.
+type versionIDs [Play + 1][Serverbound + 1]idList
+
+var versionTables = buildVersionTables()
+
+func buildVersionTables() map[Version]*[Play + 1][Serverbound + 1]*idTable {
+	tables := make(map[Version]*[Play + 1][Serverbound + 1]*idTable, len(versionPacketIDs))
+	for version, lists := range versionPacketIDs {
+		t := new([Play + 1][Serverbound + 1]*idTable)
+		for state := range lists {
+			for direction, list := range lists[state] {
+				t[state][direction] = newIDTable(list)
+			}
+		}
+		tables[version] = t
+	}
+	return tables
+}
@@ -0,0 +1,138 @@
+package packet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Tnze/go-mc/data/packetid"
+	"github.com/google/uuid"
+	"github.com/mworzala/kite/pkg/buffer"
+	"github.com/mworzala/kite/pkg/mojang"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion_IDs(t *testing.T) {
+	tests := []struct {
+		version   Version
+		state     State
+		direction Direction
+		id        int
+		wireID    int
+	}{
+		{Version1_21_2, Play, Serverbound, ClientPlayKeepAliveID, 0x1A},
+		{Version1_21, Play, Serverbound, ClientPlayKeepAliveID, 0x18},
+		{Version1_20_5, Play, Serverbound, ClientPlayKeepAliveID, 0x18},
+		{Version1_20_3, Play, Serverbound, ClientPlayKeepAliveID, 0x15},
+		{Version1_20_2, Play, Serverbound, ClientPlayKeepAliveID, 0x14},
+
+		{Version1_21_2, Play, Clientbound, ServerPlayLoginID, 0x2C},
+		{Version1_21, Play, Clientbound, ServerPlayLoginID, 0x2B},
+		{Version1_20_5, Play, Clientbound, ServerPlayLoginID, 0x2B},
+		{Version1_20_3, Play, Clientbound, ServerPlayLoginID, 0x29},
+		{Version1_20_2, Play, Clientbound, ServerPlayLoginID, 0x29},
+
+		{Version1_21_2, Play, Clientbound, ServerPlaySystemChatID, 0x73},
+		{Version1_21, Play, Clientbound, ServerPlaySystemChatID, 0x6C},
+		{Version1_20_3, Play, Clientbound, ServerPlaySystemChatID, 0x69},
+		{Version1_20_2, Play, Clientbound, ServerPlaySystemChatID, 0x67},
+
+		{Version1_21, Play, Clientbound, ServerPlayRecipeID, 0x41},
+		{Version1_21, Play, Clientbound, ServerPlaySetCarriedItemChangeID, 0x53},
+		{Version1_21_2, Play, Clientbound, ServerPlaySetCarriedItemChangeID, 0x63},
+
+		{Version1_21_2, Config, Clientbound, ServerConfigRegistryDataID, 0x07},
+		{Version1_20_3, Config, Clientbound, ServerConfigRegistryDataID, 0x05},
+		{Version1_20_2, Config, Clientbound, ServerConfigAddResourcePackID, 0x06},
+		{Version1_20_2, Config, Serverbound, ClientConfigFinishConfigurationID, 0x02},
+
+		// Unsupported versions can still ping
+		{Version(47), Status, Serverbound, ClientStatusPingRequestID, 0x01},
+	}
+	for _, tt := range tests {
+		wireID, ok := tt.version.WireID(tt.state, tt.direction, tt.id)
+		require.True(t, ok, "%s %s %s %#x", tt.version, tt.state, tt.direction, tt.id)
+		require.Equal(t, tt.wireID, wireID, "%s %s %s %#x", tt.version, tt.state, tt.direction, tt.id)
+
+		id, ok := tt.version.PacketID(tt.state, tt.direction, tt.wireID)
+		require.True(t, ok)
+		require.Equal(t, tt.id, id)
+	}
+
+	// Packets which do not exist in a version
+	for _, v := range []Version{Version1_20_2, Version1_20_3} {
+		_, ok := v.WireID(Config, Clientbound, ServerConfigKnownPacksID)
+		require.False(t, ok)
+	}
+	_, ok := Version1_21_2.WireID(Play, Clientbound, ServerPlayRecipeID)
+	require.False(t, ok)
+	_, ok = Version1_21_2.PacketID(Play, Clientbound, ServerPlayServerLinksID+1)
+	require.False(t, ok)
+	_, ok = Version(47).PacketID(Login, Serverbound, 0)
+	require.False(t, ok)
+}
+
+func TestPacketName(t *testing.T) {
+	for _, tt := range []struct {
+		state     State
+		direction Direction
+		id        int
+		name      string
+	}{
+		{Handshake, Serverbound, ClientHandshakeHandshakeID, "Handshake"},
+		{Play, Serverbound, ClientPlayKeepAliveID, "KeepAlive"},
+		{Play, Clientbound, ServerPlaySystemChatID, "SystemChat"},
+		{Play, Clientbound, ServerPlayRecipeID, "Recipe"}, // Removed
+		{Config, Clientbound, ServerConfigRegistryDataID, "RegistryData"},
+	} {
+		name, ok := PacketName(tt.state, tt.direction, tt.id)
+		require.True(t, ok)
+		require.Equal(t, tt.name, name)
+	}
+
+	for _, id := range []int{-1, ServerPlayRecipeID + 1} {
+		_, ok := PacketName(Play, Clientbound, id)
+		require.False(t, ok)
+	}
+	_, ok := PacketName(State(7), Clientbound, 0)
+	require.False(t, ok)
+}
+
+func TestVersion_MatchesGoMC(t *testing.T) {
+	// go-mc implements 1.21, compare the number of packets in each state.
+	counts := map[State][2]int{
+		Login:  {int(packetid.ClientboundLoginCookieRequest) + 1, int(packetid.ServerboundLoginCookieResponse) + 1},
+		Config: {int(packetid.ClientboundConfigServerLinks) + 1, int(packetid.ServerboundConfigSelectKnownPacks) + 1},
+		Play:   {int(packetid.ClientboundPacketIDGuard), int(packetid.ServerboundPacketIDGuard)},
+	}
+	for state, count := range counts {
+		for direction := range count {
+			table := Version1_21.table(state, Direction(direction))
+			require.Len(t, table.fromWire, count[direction], "%s %s", state, Direction(direction))
+		}
+	}
+}
+
+func TestVersionedPacket(t *testing.T) {
+	pkt := &ServerLoginSuccess{
+		GameProfile:         mojang.GameProfile{ID: uuid.New(), Name: "kite", Properties: []mojang.ProfileProperty{}},
+		StrictErrorHandling: true,
+	}
+	for _, tt := range []struct {
+		version Version
+		length  int
+	}{
+		{Version1_20_2, 16 + 5 + 1},
+		{Version1_21, 16 + 5 + 1 + 1},
+		{Version1_21_2, 16 + 5 + 1},
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, pkt, tt.version))
+		require.Equal(t, tt.length, buf.Len(), tt.version.String())
+
+		read, r := new(ServerLoginSuccess), buffer.Wrap(buf.Bytes())
+		require.NoError(t, Read(r, read, tt.version))
+		require.Equal(t, pkt.GameProfile, read.GameProfile)
+		require.Equal(t, tt.version == Version1_21, read.StrictErrorHandling)
+		require.Zero(t, r.Remaining())
+	}
+}
@@ -0,0 +1,281 @@
+// Package proxyproto implements parsing and writing of HAProxy PROXY protocol (v1 and v2) headers.
+//
+// See https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt for the specification.
+package proxyproto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxV1Length is the maximum length of a v1 header including the trailing CRLF.
+	maxV1Length = 107
+	// v2HeaderLength is the length of the fixed part of a v2 header.
+	v2HeaderLength = 16
+
+	v2Version        = 0x20
+	v2FamilyInet     = 0x10
+	v2FamilyInet6    = 0x20
+	v2TransportTCP   = 0x01
+	v2TransportUDP   = 0x02
+	v2MaxExtraLength = 4096 // Limit on address and TLV data we are willing to buffer
+)
+
+var (
+	v1Signature = []byte("PROXY ")
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+var (
+	// ErrNoHeader is returned when the data does not start with a PROXY protocol header.
+	ErrNoHeader = errors.New("no proxy protocol header")
+	// ErrIncomplete is returned when more data is required to parse the header.
+	ErrIncomplete = errors.New("incomplete proxy protocol header")
+	// ErrInvalidHeader is returned when the header is malformed or unsupported.
+	ErrInvalidHeader = errors.New("invalid proxy protocol header")
+)
+
+// Command is the v2 header command. Version 1 headers are always CommandProxy.
+type Command byte
+
+const (
+	// CommandLocal indicates the connection was established by the proxy itself, for example a health
+	// check. The addresses should be ignored.
+	CommandLocal Command = 0x00
+	// CommandProxy indicates the connection was proxied on behalf of another node.
+	CommandProxy Command = 0x01
+)
+
+// A Header contains the information sent by a proxy before the proxied stream.
+type Header struct {
+	Version int // 1 or 2
+	Command Command
+
+	// Source and Destination are the original client and server addresses, as either *net.TCPAddr or
+	// *net.UDPAddr. Both are nil if the header did not contain addresses (UNKNOWN, LOCAL or unix sockets).
+	Source      net.Addr
+	Destination net.Addr
+}
+
+// NewHeader creates a header for a proxied TCP connection between source and destination.
+func NewHeader(version int, source, destination net.Addr) *Header {
+	return &Header{Version: version, Command: CommandProxy, Source: source, Destination: destination}
+}
+
+// Parse parses a header from the start of data, returning it and the number of bytes it occupied.
+//
+// ErrNoHeader is returned if data does not start with a header, and ErrIncomplete if it may but more data
+// is needed to tell. Both are decided using as few bytes as possible, so callers may pass a partial buffer.
+func Parse(data []byte) (*Header, int, error) {
+	switch {
+	case hasPrefix(data, v2Signature):
+		if len(data) < len(v2Signature) {
+			return nil, 0, ErrIncomplete
+		}
+		return parseV2(data)
+	case hasPrefix(data, v1Signature):
+		if len(data) < len(v1Signature) {
+			return nil, 0, ErrIncomplete
+		}
+		return parseV1(data)
+	default:
+		return nil, 0, ErrNoHeader
+	}
+}
+
+// hasPrefix returns true if data is a prefix of signature, or signature is a prefix of data. Empty data
+// matches any signature, since it cannot yet be ruled out.
+func hasPrefix(data, signature []byte) bool {
+	n := min(len(data), len(signature))
+	return bytes.Equal(data[:n], signature[:n])
+}
+
+func parseV1(data []byte) (*Header, int, error) {
+	end := bytes.Index(data[:min(len(data), maxV1Length)], []byte("\r\n"))
+	if end < 0 {
+		if len(data) >= maxV1Length {
+			return nil, 0, fmt.Errorf("%w: v1 header too long", ErrInvalidHeader)
+		}
+		return nil, 0, ErrIncomplete
+	}
+
+	h := &Header{Version: 1, Command: CommandProxy}
+	fields := strings.Split(string(data[len(v1Signature):end]), " ")
+	switch fields[0] {
+	case "UNKNOWN":
+		// The remainder of the line must be ignored.
+		return h, end + 2, nil
+	case "TCP4", "TCP6":
+		if len(fields) != 5 {
+			return nil, 0, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidHeader, len(fields))
+		}
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown protocol %q", ErrInvalidHeader, fields[0])
+	}
+
+	src, err := parseV1Addr(fields[0], fields[1], fields[3])
+	if err != nil {
+		return nil, 0, err
+	}
+	dst, err := parseV1Addr(fields[0], fields[2], fields[4])
+	if err != nil {
+		return nil, 0, err
+	}
+	h.Source, h.Destination = src, dst
+	return h, end + 2, nil
+}
+
+func parseV1Addr(protocol, rawIP, rawPort string) (*net.TCPAddr, error) {
+	ip := net.ParseIP(rawIP)
+	// TCP6 may carry IPv4 mapped addresses, so the family is decided by the textual form.
+	if ip == nil || (protocol == "TCP6") != strings.Contains(rawIP, ":") {
+		return nil, fmt.Errorf("%w: invalid %s address %q", ErrInvalidHeader, protocol, rawIP)
+	}
+	if protocol == "TCP4" {
+		ip = ip.To4() // Match the representation used by v2 headers
+	} else {
+		ip = ip.To16()
+	}
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil || (len(rawPort) > 1 && rawPort[0] == '0') {
+		return nil, fmt.Errorf("%w: invalid port %q", ErrInvalidHeader, rawPort)
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+func parseV2(data []byte) (*Header, int, error) {
+	if len(data) < v2HeaderLength {
+		return nil, 0, ErrIncomplete
+	}
+
+	versionCommand, familyTransport := data[12], data[13]
+	length := int(binary.BigEndian.Uint16(data[14:16]))
+	if versionCommand&0xF0 != v2Version {
+		return nil, 0, fmt.Errorf("%w: unsupported version %d", ErrInvalidHeader, versionCommand>>4)
+	}
+	if length > v2MaxExtraLength {
+		return nil, 0, fmt.Errorf("%w: header length %d too large", ErrInvalidHeader, length)
+	}
+	if len(data) < v2HeaderLength+length {
+		return nil, 0, ErrIncomplete
+	}
+
+	h := &Header{Version: 2, Command: Command(versionCommand & 0x0F)}
+	if h.Command != CommandLocal && h.Command != CommandProxy {
+		return nil, 0, fmt.Errorf("%w: unknown command %d", ErrInvalidHeader, h.Command)
+	}
+
+	// Addresses are ignored for LOCAL connections, and unknown families are treated as UNSPEC.
+	addrs := data[v2HeaderLength : v2HeaderLength+length]
+	if h.Command == CommandProxy {
+		var ipLength int
+		switch familyTransport & 0xF0 {
+		case v2FamilyInet:
+			ipLength = net.IPv4len
+		case v2FamilyInet6:
+			ipLength = net.IPv6len
+		}
+		transport := familyTransport & 0x0F
+		if ipLength != 0 && (transport == v2TransportTCP || transport == v2TransportUDP) {
+			if len(addrs) < ipLength*2+4 {
+				return nil, 0, fmt.Errorf("%w: address block too short", ErrInvalidHeader)
+			}
+			srcIP := net.IP(bytes.Clone(addrs[:ipLength]))
+			dstIP := net.IP(bytes.Clone(addrs[ipLength : ipLength*2]))
+			srcPort := int(binary.BigEndian.Uint16(addrs[ipLength*2:]))
+			dstPort := int(binary.BigEndian.Uint16(addrs[ipLength*2+2:]))
+			if transport == v2TransportTCP {
+				h.Source = &net.TCPAddr{IP: srcIP, Port: srcPort}
+				h.Destination = &net.TCPAddr{IP: dstIP, Port: dstPort}
+			} else {
+				h.Source = &net.UDPAddr{IP: srcIP, Port: srcPort}
+				h.Destination = &net.UDPAddr{IP: dstIP, Port: dstPort}
+			}
+		}
+	}
+	return h, v2HeaderLength + length, nil
+}
+
+// WriteTo writes the header in its configured version. A header without (or with mismatched) TCP
+// addresses is written as UNKNOWN for v1, or LOCAL/UNSPEC for v2.
+func (h *Header) WriteTo(w io.Writer) (int64, error) {
+	var data []byte
+	switch h.Version {
+	case 1:
+		data = h.appendV1(nil)
+	case 2:
+		data = h.appendV2(nil)
+	default:
+		return 0, fmt.Errorf("unsupported proxy protocol version %d", h.Version)
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// addrs returns the source and destination IPs and ports if they are usable in a header.
+func (h *Header) addrs() (srcIP, dstIP net.IP, srcPort, dstPort int, ok bool) {
+	src, srcOk := h.Source.(*net.TCPAddr)
+	dst, dstOk := h.Destination.(*net.TCPAddr)
+	if !srcOk || !dstOk || h.Command != CommandProxy {
+		return
+	}
+	srcIP, dstIP = src.IP, dst.IP
+	if src4, dst4 := srcIP.To4(), dstIP.To4(); src4 != nil && dst4 != nil {
+		srcIP, dstIP = src4, dst4
+	} else {
+		// Mixed families are sent as IPv6, using IPv4 mapped addresses if needed.
+		srcIP, dstIP = srcIP.To16(), dstIP.To16()
+	}
+	return srcIP, dstIP, src.Port, dst.Port, srcIP != nil && dstIP != nil
+}
+
+func (h *Header) appendV1(b []byte) []byte {
+	b = append(b, v1Signature...)
+	srcIP, dstIP, srcPort, dstPort, ok := h.addrs()
+	if !ok {
+		return append(b, "UNKNOWN\r\n"...)
+	}
+	if len(srcIP) == net.IPv4len {
+		b = append(b, "TCP4 "...)
+	} else {
+		b = append(b, "TCP6 "...)
+	}
+	b = fmt.Appendf(b, "%s %s %d %d\r\n", formatV1IP(srcIP), formatV1IP(dstIP), srcPort, dstPort)
+	return b
+}
+
+// formatV1IP formats ip for a v1 header. IPv4 mapped addresses are written in their IPv6 form,
+// since net.IP.String would write them like a plain IPv4 address.
+func formatV1IP(ip net.IP) string {
+	if ip4 := ip.To4(); len(ip) == net.IPv6len && ip4 != nil {
+		return "::ffff:" + ip4.String()
+	}
+	return ip.String()
+}
+
+func (h *Header) appendV2(b []byte) []byte {
+	b = append(b, v2Signature...)
+	srcIP, dstIP, srcPort, dstPort, ok := h.addrs()
+	if !ok {
+		return append(b, v2Version|byte(CommandLocal), 0x00, 0x00, 0x00)
+	}
+
+	family := byte(v2FamilyInet)
+	if len(srcIP) == net.IPv6len {
+		family = v2FamilyInet6
+	}
+	b = append(b, v2Version|byte(CommandProxy), family|v2TransportTCP)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(srcIP)*2+4))
+	b = append(b, srcIP...)
+	b = append(b, dstIP...)
+	b = binary.BigEndian.AppendUint16(b, uint16(srcPort))
+	b = binary.BigEndian.AppendUint16(b, uint16(dstPort))
+	return b
+}
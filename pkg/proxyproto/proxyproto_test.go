@@ -0,0 +1,169 @@
+package proxyproto
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	client4 = &net.TCPAddr{IP: net.IPv4(192, 168, 0, 1).To4(), Port: 56324}
+	server4 = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1).To4(), Port: 25565}
+	client6 = &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 56324}
+	server6 = &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 25565}
+)
+
+func TestParse_V1(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		src, dst net.Addr
+	}{
+		{"tcp4", "PROXY TCP4 192.168.0.1 10.0.0.1 56324 25565\r\n", client4, server4},
+		{"tcp6", "PROXY TCP6 2001:db8::1 2001:db8::2 56324 25565\r\n", client6, server6},
+		{"unknown", "PROXY UNKNOWN\r\n", nil, nil},
+		{"unknown with addresses", "PROXY UNKNOWN ffff:: ffff:: 1 2\r\n", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, n, err := Parse([]byte(tt.data + "\x10\x00"))
+			require.NoError(t, err)
+			require.Equal(t, len(tt.data), n)
+			require.Equal(t, &Header{Version: 1, Command: CommandProxy, Source: tt.src, Destination: tt.dst}, h)
+		})
+	}
+}
+
+func TestParse_V2(t *testing.T) {
+	sig := "\r\n\r\n\x00\r\nQUIT\n"
+	tests := []struct {
+		name     string
+		data     string
+		expected *Header
+	}{
+		{
+			name:     "tcp4",
+			data:     sig + "\x21\x11\x00\x0c\xc0\xa8\x00\x01\x0a\x00\x00\x01\xdc\x04\x63\xdd",
+			expected: &Header{Version: 2, Command: CommandProxy, Source: client4, Destination: server4},
+		},
+		{
+			name: "tcp6 with tlv",
+			data: sig + "\x21\x21\x00\x28" +
+				"\x20\x01\x0d\xb8\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01" +
+				"\x20\x01\x0d\xb8\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x02" +
+				"\xdc\x04\x63\xdd" + "\x04\x00\x01\x00",
+			expected: &Header{Version: 2, Command: CommandProxy, Source: client6, Destination: server6},
+		},
+		{
+			name:     "local",
+			data:     sig + "\x20\x00\x00\x00",
+			expected: &Header{Version: 2, Command: CommandLocal},
+		},
+		{
+			name:     "unix",
+			data:     sig + "\x21\x31\x00\x04abcd",
+			expected: &Header{Version: 2, Command: CommandProxy},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, n, err := Parse([]byte(tt.data + "\x10\x00"))
+			require.NoError(t, err)
+			require.Equal(t, len(tt.data), n)
+			require.Equal(t, tt.expected, h)
+		})
+	}
+}
+
+func TestParse_Incomplete(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"PRO",
+		"PROXY TCP4 192.168.0.1",
+		"\r\n\r\n\x00",
+		"\r\n\r\n\x00\r\nQUIT\n\x21\x11\x00\x0c\xc0\xa8",
+	} {
+		_, _, err := Parse([]byte(data))
+		require.ErrorIs(t, err, ErrIncomplete, "%q", data)
+	}
+}
+
+func TestParse_NoHeader(t *testing.T) {
+	for _, data := range []string{
+		"\x10\x00\xff\x05", // Handshake packet
+		"\xfe\x01",         // Legacy ping
+		"PROXZ",
+		"\r\n\r\n\x01",
+	} {
+		_, _, err := Parse([]byte(data))
+		require.ErrorIs(t, err, ErrNoHeader, "%q", data)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, data := range []string{
+		"PROXY TCP5 192.168.0.1 10.0.0.1 56324 25565\r\n",
+		"PROXY TCP4 192.168.0.1 10.0.0.1 56324\r\n",
+		"PROXY TCP4 2001:db8::1 10.0.0.1 56324 25565\r\n",
+		"PROXY TCP4 192.168.0.1 10.0.0.1 65536 25565\r\n",
+		"PROXY TCP4 192.168.0.1 10.0.0.1 056324 25565\r\n",
+		"PROXY " + string(bytes.Repeat([]byte{'a'}, 120)),
+		"\r\n\r\n\x00\r\nQUIT\n\x11\x11\x00\x00",
+		"\r\n\r\n\x00\r\nQUIT\n\x22\x11\x00\x00",
+		"\r\n\r\n\x00\r\nQUIT\n\x21\x11\x00\x04abcd",
+		"\r\n\r\n\x00\r\nQUIT\n\x21\x11\xff\xff",
+	} {
+		_, _, err := Parse([]byte(data))
+		require.ErrorIs(t, err, ErrInvalidHeader, "%q", data)
+	}
+}
+
+func TestHeader_WriteTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   *Header
+		expected *Header
+	}{
+		{"v1 tcp4", NewHeader(1, client4, server4), nil},
+		{"v1 tcp6", NewHeader(1, client6, server6), nil},
+		{
+			name:   "v1 mixed families",
+			header: NewHeader(1, client4, server6),
+			expected: NewHeader(1,
+				&net.TCPAddr{IP: client4.IP.To16(), Port: client4.Port}, server6),
+		},
+		{"v1 unknown", NewHeader(1, nil, nil), nil},
+		{"v2 tcp4", NewHeader(2, client4, server4), nil},
+		{"v2 tcp6", NewHeader(2, client6, server6), nil},
+		{"v2 local", &Header{Version: 2, Command: CommandLocal}, nil},
+		{
+			name:   "v2 mixed families",
+			header: NewHeader(2, client4, server6),
+			expected: NewHeader(2,
+				&net.TCPAddr{IP: client4.IP.To16(), Port: client4.Port}, server6),
+		},
+		{
+			name:     "v2 without addresses",
+			header:   NewHeader(2, nil, nil),
+			expected: &Header{Version: 2, Command: CommandLocal},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := tt.header.WriteTo(&buf)
+			require.NoError(t, err)
+			require.Equal(t, int64(buf.Len()), n)
+
+			h, read, err := Parse(buf.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, buf.Len(), read)
+			if tt.expected == nil {
+				tt.expected = tt.header
+			}
+			require.Equal(t, tt.expected, h)
+		})
+	}
+}
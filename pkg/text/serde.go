@@ -6,7 +6,7 @@ import (
 	"io"
 	"strings"
 
-	"github.com/Tnze/go-mc/nbt"
+	"github.com/mworzala/kite/pkg/nbt"
 )
 
 func MarshalJSON(c Component) ([]byte, error) {
@@ -22,24 +22,32 @@ func UnmarshalJSON(r io.Reader) (Component, error) {
 	if err = json.Unmarshal(data, &tree); err != nil {
 		return nil, err
 	}
-	return unmarshalTree(tree.(map[string]any))
+	return unmarshalTree(tree)
 }
 
 func MarshalNBT(w io.Writer, c Component, networkFormat bool) error {
-	enc := nbt.NewEncoder(w)
-	enc.NetworkFormat(networkFormat)
-	return enc.Encode(marshalTree(c), "")
+	tag, err := treeToNBT(marshalTree(c))
+	if err != nil {
+		return err
+	}
+	if networkFormat {
+		return nbt.WriteNetwork(w, tag)
+	}
+	return nbt.WriteFile(w, "", tag)
 }
 
 func UnmarshalNBT(r io.Reader, networkFormat bool) (Component, error) {
-	dec := nbt.NewDecoder(r)
-	dec.NetworkFormat(networkFormat)
-
-	var tree any
-	if _, err := dec.Decode(&tree); err != nil {
+	var tag nbt.Tag
+	var err error
+	if networkFormat {
+		tag, err = nbt.ReadNetwork(r)
+	} else {
+		_, tag, err = nbt.ReadFile(r)
+	}
+	if err != nil {
 		return nil, err
 	}
-	return unmarshalTree(tree.(map[string]any))
+	return unmarshalTree(nbtToTree(tag))
 }
 
 func MarshalPlain(c Component) string {
@@ -62,7 +70,7 @@ func marshalTree(c Component) map[string]any {
 		}
 		with := marshalChildrenTree(c.With)
 		if len(with) > 0 {
-			result["with"] = c.With
+			result["with"] = with
 		}
 	case *Score:
 		result["type"] = "score"
@@ -166,6 +174,61 @@ func appendStyleTree(result map[string]any, s Style) {
 	}
 }
 
+// treeToNBT converts a tree from marshalTree to NBT, where booleans are bytes.
+func treeToNBT(v any) (nbt.Tag, error) {
+	switch v := v.(type) {
+	case string:
+		return nbt.String(v), nil
+	case bool:
+		return nbt.Bool(v), nil
+	case Component:
+		return treeToNBT(marshalTree(v))
+	case map[string]any:
+		compound := make(nbt.Compound, len(v))
+		for key, value := range v {
+			var err error
+			if compound[key], err = treeToNBT(value); err != nil {
+				return nil, err
+			}
+		}
+		return compound, nil
+	case []map[string]any:
+		list := make(nbt.List, len(v))
+		for i, value := range v {
+			var err error
+			if list[i], err = treeToNBT(value); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to nbt", v)
+}
+
+// nbtToTree converts NBT to a tree for unmarshalTree, where bytes are booleans (the only use of bytes by
+// components).
+func nbtToTree(tag nbt.Tag) any {
+	switch tag := tag.(type) {
+	case nbt.String:
+		return string(tag)
+	case nbt.Byte:
+		return tag != 0
+	case nbt.Compound:
+		tree := make(map[string]any, len(tag))
+		for key, value := range tag {
+			tree[key] = nbtToTree(value)
+		}
+		return tree
+	case nbt.List:
+		tree := make([]any, len(tag))
+		for i, value := range tag {
+			tree[i] = nbtToTree(value)
+		}
+		return tree
+	}
+	return tag
+}
+
 func marshalPlain(c Component, b *strings.Builder) {
 	switch c := c.(type) {
 	case *Text:
@@ -277,19 +340,85 @@ func unmarshalTranslate(obj map[string]any) (c Component, err error) {
 }
 
 func unmarshalScore(obj map[string]any) (c Component, err error) {
-	panic("todo")
+	t := &Score{}
+	if score, ok := obj["score"].(map[string]any); ok {
+		if name, ok := score["name"].(string); ok {
+			t.Name = name
+		}
+		if objective, ok := score["objective"].(string); ok {
+			t.Objective = objective
+		}
+	}
+	if err = unmarshalStyle(obj, &t.S); err != nil {
+		return nil, err
+	}
+	t.Extra, err = unmarshalExtra(obj)
+	return t, err
 }
 
 func unmarshalSelector(obj map[string]any) (c Component, err error) {
-	panic("todo")
+	t := &Selector{}
+	if selector, ok := obj["selector"].(string); ok {
+		t.Selector = selector
+	}
+	if t.Separator, err = unmarshalSeparator(obj); err != nil {
+		return nil, err
+	}
+	if err = unmarshalStyle(obj, &t.S); err != nil {
+		return nil, err
+	}
+	t.Extra, err = unmarshalExtra(obj)
+	return t, err
 }
 
 func unmarshalKeybind(obj map[string]any) (c Component, err error) {
-	panic("todo")
+	t := &Keybind{}
+	if keybind, ok := obj["keybind"].(string); ok {
+		t.Keybind = keybind
+	}
+	if err = unmarshalStyle(obj, &t.S); err != nil {
+		return nil, err
+	}
+	t.Extra, err = unmarshalExtra(obj)
+	return t, err
 }
 
 func unmarshalNBT(obj map[string]any) (c Component, err error) {
-	panic("todo")
+	t := &NBT{}
+	if source, ok := obj["source"].(string); ok {
+		t.Source = source
+	}
+	if nbtPath, ok := obj["nbt"].(string); ok {
+		t.NBT = nbtPath
+	}
+	if interpret, ok := obj["interpret"].(bool); ok {
+		t.Interpret = interpret
+	}
+	if t.Separator, err = unmarshalSeparator(obj); err != nil {
+		return nil, err
+	}
+	if block, ok := obj["block"].(string); ok {
+		t.Block = block
+	}
+	if entity, ok := obj["entity"].(string); ok {
+		t.Entity = entity
+	}
+	if storage, ok := obj["storage"].(string); ok {
+		t.Storage = storage
+	}
+	if err = unmarshalStyle(obj, &t.S); err != nil {
+		return nil, err
+	}
+	t.Extra, err = unmarshalExtra(obj)
+	return t, err
+}
+
+func unmarshalSeparator(obj map[string]any) (Component, error) {
+	separator, ok := obj["separator"]
+	if !ok {
+		return nil, nil
+	}
+	return unmarshalTree(separator)
 }
 
 func unmarshalStyle(obj map[string]any, s *Style) (err error) {
@@ -2,6 +2,7 @@ package text
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -57,3 +58,101 @@ func TestJson_Marshal_Regression(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, `{"color":"#ff5555","text":"Hello","type":"text"}`, string(s))
 }
+
+func TestJson_Marshal_TranslateWith(t *testing.T) {
+	// Arguments are marshalled as components, not as their Go fields.
+	c := &Translate{Translate: "chat.type.text", With: []Component{&Text{Text: "kite"}}}
+	s, err := MarshalJSON(c)
+	require.NoError(t, err)
+	require.Equal(t, `{"translate":"chat.type.text","type":"translate","with":[{"text":"kite","type":"text"}]}`, string(s))
+}
+
+func TestNBT_RoundTrip(t *testing.T) {
+	for _, network := range []bool{true, false} {
+		var buf bytes.Buffer
+		require.NoError(t, MarshalNBT(&buf, txt, network))
+		c, err := UnmarshalNBT(bytes.NewReader(buf.Bytes()), network)
+		require.NoError(t, err)
+		require.Equal(t, txt, c)
+
+		// Encoding is deterministic.
+		var again bytes.Buffer
+		require.NoError(t, MarshalNBT(&again, c, network))
+		require.Equal(t, buf.Bytes(), again.Bytes())
+	}
+
+	translate := &Translate{Translate: "chat.type.text", With: []Component{&Text{Text: "kite"}}}
+	var buf bytes.Buffer
+	require.NoError(t, MarshalNBT(&buf, translate, true))
+	c, err := UnmarshalNBT(bytes.NewReader(buf.Bytes()), true)
+	require.NoError(t, err)
+	require.Equal(t, translate, c)
+}
+
+func TestNBT_Unmarshal_String(t *testing.T) {
+	// Plain text components are sent as a string tag.
+	c, err := UnmarshalNBT(bytes.NewReader([]byte{0x08, 0x00, 0x02, 'h', 'i'}), true)
+	require.NoError(t, err)
+	require.Equal(t, &Text{Text: "hi"}, c)
+}
+
+func TestJson_RoundTrip_Types(t *testing.T) {
+	for _, c := range []Component{
+		&Score{Name: "kite", Objective: "kills", S: Style{Color: Red, Bold: True}},
+		&Selector{Selector: "@a", Separator: &Text{Text: ", "}},
+		&Keybind{Keybind: "key.jump", Extra: []Component{&Text{Text: "!"}}},
+		&NBT{Source: "entity", NBT: "Health", Interpret: true, Separator: &Text{Text: "-"}, Entity: "@s"},
+	} {
+		s, err := MarshalJSON(c)
+		require.NoError(t, err)
+		read, err := UnmarshalJSON(bytes.NewReader(s))
+		require.NoError(t, err)
+		require.Equal(t, c, read)
+	}
+}
+
+func TestJson_Unmarshal_Invalid(t *testing.T) {
+	for _, s := range []string{`null`, `1`, `[1]`, `{}`, `{"text":"a","extra":[true]}`, `{"text":"a","color":"#12"}`} {
+		_, err := UnmarshalJSON(bytes.NewReader([]byte(s)))
+		require.Error(t, err, s)
+	}
+
+	// Plain text may be a string, or a list of components.
+	c, err := UnmarshalJSON(bytes.NewReader([]byte(`["a",{"text":"b"}]`)))
+	require.NoError(t, err)
+	require.Equal(t, &Text{Extra: []Component{&Text{Text: "a"}, &Text{Text: "b"}}}, c)
+}
+
+func FuzzUnmarshalJSON(f *testing.F) {
+	f.Add([]byte(jsonTxt))
+	f.Add([]byte(`"hello"`))
+	f.Add([]byte(`{"translate":"chat.type.text","with":["kite",{"selector":"@p","separator":""}]}`))
+	f.Add([]byte(`{"score":{"name":"kite","objective":"kills"},"hoverEvent":{"action":"show_text","value":[1]}}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		c, err := UnmarshalJSON(bytes.NewReader(data))
+		if err == nil {
+			// Anything decoded can be encoded again.
+			s, err := MarshalJSON(c)
+			require.NoError(t, err)
+			_, err = UnmarshalJSON(bytes.NewReader(s))
+			require.NoError(t, err, string(s))
+		}
+	})
+}
+
+func FuzzUnmarshalNBT(f *testing.F) {
+	for _, network := range []bool{true, false} {
+		var buf bytes.Buffer
+		require.NoError(f, MarshalNBT(&buf, txt, network))
+		f.Add(buf.Bytes(), network)
+	}
+	f.Add([]byte{0x08, 0x00, 0x02, 'h', 'i'}, true)
+	f.Fuzz(func(t *testing.T, data []byte, network bool) {
+		c, err := UnmarshalNBT(bytes.NewReader(data), network)
+		if err == nil {
+			// Hover event values are kept as decoded, and may not be representable.
+			_, _ = MarshalJSON(c)
+			_ = MarshalNBT(io.Discard, c, network)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package kite
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/mworzala/kite/pkg/proxyproto"
+)
+
+// ProxyProtocolMode controls whether a Conn expects a PROXY protocol header before the handshake.
+type ProxyProtocolMode int
+
+const (
+	// ProxyProtocolDisabled treats all data as Minecraft packets. This is the default.
+	ProxyProtocolDisabled ProxyProtocolMode = iota
+	// ProxyProtocolOptional accepts connections with or without a PROXY protocol header.
+	ProxyProtocolOptional
+	// ProxyProtocolRequired closes connections which do not start with a PROXY protocol header.
+	ProxyProtocolRequired
+)
+
+// SetProxyProtocol sets whether a PROXY protocol header is expected at the start of the connection,
+// as sent by load balancers such as HAProxy. It must be called before Serve.
+//
+// Only enable this when connections come from a trusted proxy, because the header decides the address
+// returned by RemoteAddr. Optional mode additionally allows clients to connect directly.
+func (c *Conn) SetProxyProtocol(mode ProxyProtocolMode) {
+	c.proxyMode = mode
+}
+
+// ProxyHeader returns the PROXY protocol header received on this connection, or nil if there was none.
+func (c *Conn) ProxyHeader() *proxyproto.Header {
+	return c.proxyHeader.Load()
+}
+
+// SendProxyHeader writes a PROXY protocol header to the connection, for example to pass the address of
+// a client on to a backend server. It must be called before any packets are sent.
+func (c *Conn) SendProxyHeader(header *proxyproto.Header) error {
+	c.lockWriter()
+	defer c.wlock.Unlock()
+
+	var buf bytes.Buffer
+	if _, err := header.WriteTo(&buf); err != nil {
+		return err
+	}
+	return c.writeLocked(buf.Bytes(), -1)
+}
+
+// readProxyHeader reads the PROXY protocol header from the start of data if one is expected, returning
+// the number of bytes it occupied. ok is false if more data is required.
+func (c *Conn) readProxyHeader(data []byte) (n int, ok bool, err error) {
+	if c.proxyMode == ProxyProtocolDisabled || c.proxyDone {
+		return 0, true, nil
+	}
+
+	header, n, err := proxyproto.Parse(data)
+	switch {
+	case errors.Is(err, proxyproto.ErrIncomplete):
+		return 0, false, nil
+	case errors.Is(err, proxyproto.ErrNoHeader) && c.proxyMode == ProxyProtocolOptional:
+		n = 0
+	case err != nil:
+		return 0, false, err
+	default:
+		c.proxyHeader.Store(header)
+	}
+	c.proxyDone = true
+	return n, true, nil
+}
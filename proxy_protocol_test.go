@@ -0,0 +1,89 @@
+package kite
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/mworzala/kite/pkg/proxyproto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn_ProxyProtocol(t *testing.T) {
+	client := &net.TCPAddr{IP: net.IPv4(192, 168, 0, 1).To4(), Port: 56324}
+	server := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1).To4(), Port: 25565}
+	frames := []byte{0x02, 0x00, 0x01, 0x02, 0x00, 0x02}
+
+	var v1, v2 bytes.Buffer
+	_, _ = proxyproto.NewHeader(1, client, server).WriteTo(&v1)
+	_, _ = proxyproto.NewHeader(2, client, server).WriteTo(&v2)
+
+	tests := []struct {
+		name   string
+		mode   ProxyProtocolMode
+		header []byte
+		addr   net.Addr
+		err    error
+	}{
+		{"disabled", ProxyProtocolDisabled, nil, &net.TCPAddr{}, nil},
+		{"optional v1", ProxyProtocolOptional, v1.Bytes(), client, nil},
+		{"optional v2", ProxyProtocolOptional, v2.Bytes(), client, nil},
+		{"optional without header", ProxyProtocolOptional, nil, &net.TCPAddr{}, nil},
+		{"required v1", ProxyProtocolRequired, v1.Bytes(), client, nil},
+		{"required v2", ProxyProtocolRequired, v2.Bytes(), client, nil},
+		{"required without header", ProxyProtocolRequired, nil, nil, proxyproto.ErrNoHeader},
+		{"invalid header", ProxyProtocolOptional, []byte("PROXY TCP7\r\n"), nil, proxyproto.ErrInvalidHeader},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Deliver one byte per read to exercise partial headers.
+			data := append(bytes.Clone(tt.header), frames...)
+			var c *Conn
+			var received []byte
+			c = NewConn(packet.Serverbound, &memConn{r: &chunkReader{data: data, chunk: 1}}, func(pb PacketBuffer) error {
+				require.Equal(t, tt.addr, c.RemoteAddr())
+				received = append(received, pb.internal.RemainingSlice()...)
+				pb.Consume()
+				return nil
+			})
+			c.SetProxyProtocol(tt.mode)
+
+			err := c.Serve(context.Background())
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				require.Empty(t, received)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []byte{0x01, 0x02}, received)
+			require.Equal(t, tt.header != nil, c.ProxyHeader() != nil)
+		})
+	}
+}
+
+func TestConn_SendProxyHeader(t *testing.T) {
+	cc, sc := net.Pipe()
+	received := make(chan net.Addr, 1)
+	var server *Conn
+	server = NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error {
+		pb.Consume()
+		received <- server.RemoteAddr()
+		return nil
+	})
+	server.SetProxyProtocol(ProxyProtocolRequired)
+	client := NewConn(packet.Clientbound, cc, func(pb PacketBuffer) error { return nil })
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	go server.ReadLoop()
+
+	source := &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 56324}
+	go func() {
+		require.NoError(t, client.SendProxyHeader(proxyproto.NewHeader(2, source, &net.TCPAddr{IP: net.IPv6loopback, Port: 25565})))
+		require.NoError(t, client.SendPacket(&packet.ClientHandshake{ProtocolVersion: 767, ServerAddress: "localhost", ServerPort: 25565, Intent: packet.IntentStatus}))
+	}()
+	require.Equal(t, source, receive(t, received))
+}
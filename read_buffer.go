@@ -0,0 +1,110 @@
+package kite
+
+import (
+	"math/bits"
+	"sync"
+
+	"github.com/mworzala/kite/pkg/packet"
+)
+
+const (
+	// minReadBufferSize is the size of the read buffer held by a connection between packets. Larger
+	// buffers are only taken from the pool while a frame bigger than this is being received.
+	minReadBufferSize = 4 * 1024
+
+	// maxReadBurstSize is the largest buffer used to batch reads of many smaller frames. Bigger buffers
+	// are still used when a single frame requires it.
+	maxReadBurstSize = 64 * 1024
+
+	minReadBufferClass = 12 // log2(minReadBufferSize)
+	// maxReadBufferClass is the smallest power of two able to hold a maximum size frame and its length prefix.
+	maxReadBufferClass = 22
+)
+
+// readBufferPools contains a pool of *[]byte per power of two size class from minReadBufferSize
+// up to 1<<maxReadBufferClass.
+var readBufferPools [maxReadBufferClass - minReadBufferClass + 1]sync.Pool
+
+// readBufferClass returns the index into readBufferPools of the smallest class holding size bytes.
+func readBufferClass(size int) int {
+	if size <= minReadBufferSize {
+		return 0
+	}
+	return bits.Len(uint(size-1)) - minReadBufferClass
+}
+
+// getReadBuffer returns a buffer of at least size bytes from the shared pool.
+func getReadBuffer(size int) *[]byte {
+	class := readBufferClass(size)
+	if buf, ok := readBufferPools[class].Get().(*[]byte); ok {
+		return buf
+	}
+	buf := make([]byte, 1<<(class+minReadBufferClass))
+	return &buf
+}
+
+// putReadBuffer returns a buffer obtained from getReadBuffer to the shared pool.
+func putReadBuffer(buf *[]byte) {
+	readBufferPools[readBufferClass(len(*buf))].Put(buf)
+}
+
+// readBufferSize returns the size of buffer to use for the next read. Without pending data, a read which
+// did not fill the buffer means no more data is available and the next read is likely to block, so the
+// buffer shrinks to the smallest size class rather than being held by an idle connection.
+func (c *Conn) readBufferSize() int {
+	if c.readStart == c.readEnd && !c.readFull {
+		return minReadBufferSize
+	}
+	return max(c.readHint, c.readNeeded, c.readEnd-c.readStart+1)
+}
+
+// adaptReadBuffer adjusts the preferred buffer size after a read of n bytes, growing it while reads fill
+// the buffer and shrinking it again once they do not. The hint never exceeds what the current state allows.
+func (c *Conn) adaptReadBuffer(n, available int) {
+	limit := maxReadBurstSize
+	if c.state <= packet.Login {
+		limit = maxPacketSizePreConfig + maxFrameLengthSize
+	}
+
+	size := len(*c.readBuffer)
+	c.readFull = n == available
+	if n == available {
+		c.readHint = min(size*2, limit)
+	} else if n <= size/4 {
+		c.readHint = max(n*2, minReadBufferSize)
+	}
+	c.readHint = min(c.readHint, limit)
+}
+
+// ensureReadBuffer makes sure the connection has a read buffer which can hold size bytes, moving any
+// pending data to the start of it. Buffers larger than required are returned to the pool.
+func (c *Conn) ensureReadBuffer(size int) {
+	size = max(size, minReadBufferSize)
+	if c.readBuffer != nil && len(*c.readBuffer) >= size && readBufferClass(len(*c.readBuffer)) == readBufferClass(size) {
+		// Correctly sized already, just compact the pending data.
+		if c.readStart > 0 {
+			c.readEnd = copy(*c.readBuffer, (*c.readBuffer)[c.readStart:c.readEnd])
+			c.readStart = 0
+		}
+		return
+	}
+
+	next := getReadBuffer(size)
+	if c.readBuffer != nil {
+		c.readEnd = copy(*next, (*c.readBuffer)[c.readStart:c.readEnd])
+		c.readStart = 0
+		putReadBuffer(c.readBuffer)
+	}
+	c.readBuffer = next
+}
+
+// releaseReadBuffer returns the read buffer to the pool if it does not contain any pending data,
+// or the connection has been closed.
+func (c *Conn) releaseReadBuffer() {
+	if c.readBuffer == nil || (c.readStart != c.readEnd && !c.closed.Load()) {
+		return
+	}
+	putReadBuffer(c.readBuffer)
+	c.readBuffer = nil
+	c.readStart, c.readEnd = 0, 0
+}
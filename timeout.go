@@ -0,0 +1,75 @@
+package kite
+
+import (
+	"context"
+	"time"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/mworzala/kite/pkg/text"
+)
+
+// DefaultTimeoutReason is the disconnect reason used by DefaultTimeouts and KeepAlive.
+var DefaultTimeoutReason text.Component = &text.Text{Text: "Timed out"}
+
+// Timeouts configures how long a Conn waits for the remote, see Conn.SetTimeouts. States missing
+// from the maps (or with a zero duration) have no timeout.
+type Timeouts struct {
+	// State is the maximum time the connection may stay in each state, for example to require a
+	// login to complete within a fixed time.
+	State map[packet.State]time.Duration
+	// Idle is the maximum time between receiving data in each state.
+	Idle map[packet.State]time.Duration
+
+	// Reason is sent to the remote when a timeout expires, if possible in the current state.
+	// If nil, the connection is closed without a message.
+	Reason text.Component
+}
+
+// DefaultTimeouts are timeouts suitable for client connections. A client must log in within 30 seconds,
+// and once logged in must send something every 30 seconds, as vanilla clients do in response to keep-alives.
+var DefaultTimeouts = Timeouts{
+	State: map[packet.State]time.Duration{
+		packet.Handshake: 10 * time.Second,
+		packet.Status:    10 * time.Second,
+		packet.Login:     30 * time.Second,
+	},
+	Idle: map[packet.State]time.Duration{
+		packet.Config: 30 * time.Second,
+		packet.Play:   30 * time.Second,
+	},
+	Reason: DefaultTimeoutReason,
+}
+
+// SetTimeouts sets the timeouts enforced by Serve. When a timeout expires, the remote is disconnected
+// and Serve returns ErrTimeout. It must be called before Serve.
+func (c *Conn) SetTimeouts(timeouts Timeouts) {
+	c.timeouts = timeouts
+}
+
+// readDeadline returns the deadline for the next read in the current state, or zero if there is none.
+func (c *Conn) readDeadline() (deadline time.Time) {
+	if c.timeouts.State == nil && c.timeouts.Idle == nil {
+		return deadline
+	}
+	if d := c.timeouts.State[c.state]; d > 0 {
+		deadline = c.stateSince.Add(d)
+	}
+	if d := c.timeouts.Idle[c.state]; d > 0 {
+		if idle := time.Now().Add(d); deadline.IsZero() || idle.Before(deadline) {
+			deadline = idle
+		}
+	}
+	return deadline
+}
+
+// updateReadDeadline sets the read deadline for the next read. Setting the deadline would override one set
+// to interrupt the read loop when ctx is cancelled, so the context error is returned if that happened.
+func (c *Conn) updateReadDeadline(ctx context.Context) error {
+	deadline := c.readDeadline()
+	if deadline.IsZero() && !c.deadlineSet {
+		return nil
+	}
+	c.deadlineSet = !deadline.IsZero()
+	_ = c.delegate.SetReadDeadline(deadline)
+	return ctx.Err()
+}
@@ -0,0 +1,204 @@
+package kite
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/mworzala/kite/pkg/text"
+	"github.com/stretchr/testify/require"
+)
+
+// serveTimeouts serves a fresh server connection with the given timeouts, returning the client side of
+// the pipe and a channel receiving the result of Serve.
+func serveTimeouts(t *testing.T, state packet.State, timeouts Timeouts) (net.Conn, <-chan error) {
+	t.Helper()
+	cc, sc := net.Pipe()
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error {
+		pb.Consume()
+		return nil
+	})
+	server.SetState(state)
+	server.SetTimeouts(timeouts)
+	t.Cleanup(func() {
+		_ = cc.Close()
+		server.Close()
+	})
+
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(context.Background()) }()
+	return cc, result
+}
+
+func TestConn_Timeouts_State(t *testing.T) {
+	start := time.Now()
+	cc, result := serveTimeouts(t, packet.Handshake, Timeouts{
+		State: map[packet.State]time.Duration{packet.Handshake: 100 * time.Millisecond},
+	})
+
+	// Sending data does not extend the state timeout.
+	go func() {
+		for range 10 {
+			if _, err := cc.Write([]byte{0x01, 0x00}); err != nil {
+				return
+			}
+			time.Sleep(30 * time.Millisecond)
+		}
+	}()
+	require.ErrorIs(t, receive(t, result), ErrTimeout)
+	require.Less(t, time.Since(start), 250*time.Millisecond)
+}
+
+func TestConn_Timeouts_Idle(t *testing.T) {
+	start := time.Now()
+	cc, result := serveTimeouts(t, packet.Play, Timeouts{
+		State: map[packet.State]time.Duration{packet.Handshake: time.Millisecond}, // Not the current state
+		Idle:  map[packet.State]time.Duration{packet.Play: 50 * time.Millisecond},
+	})
+
+	for range 5 {
+		_, err := cc.Write([]byte{0x01, 0x00})
+		require.NoError(t, err)
+		time.Sleep(20 * time.Millisecond)
+	}
+	require.ErrorIs(t, receive(t, result), ErrTimeout)
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestConn_Timeouts_Disconnect(t *testing.T) {
+	reason := &text.Text{Text: "Too slow"}
+	cc, sc := net.Pipe()
+	received := make(chan *packet.ServerLoginDisconnect, 1)
+	client := NewConn(packet.Clientbound, cc, func(pb PacketBuffer) error {
+		pkt := new(packet.ServerLoginDisconnect)
+		if err := pb.Read(pkt); err != nil {
+			return err
+		}
+		received <- pkt
+		return nil
+	})
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error { return nil })
+	client.SetState(packet.Login)
+	server.SetState(packet.Login)
+	server.SetTimeouts(Timeouts{State: map[packet.State]time.Duration{packet.Login: 10 * time.Millisecond}, Reason: reason})
+
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(context.Background()) }()
+	go client.ReadLoop()
+
+	require.Equal(t, reason, receive(t, received).Reason)
+	require.ErrorIs(t, receive(t, result), ErrTimeout)
+}
+
+func TestConn_Timeouts_ContextCancelled(t *testing.T) {
+	cc, sc := net.Pipe()
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error { return nil })
+	server.SetTimeouts(DefaultTimeouts)
+	t.Cleanup(func() {
+		_ = cc.Close()
+		server.Close()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- server.Serve(ctx) }()
+	cancel()
+	require.ErrorIs(t, receive(t, result), context.Canceled)
+}
+
+func TestKeepAlive(t *testing.T) {
+	cc, sc := net.Pipe()
+	var client *Conn
+	respond := make(chan bool, 1)
+	respond <- true
+	disconnected := make(chan *packet.ServerDisconnect, 1)
+	client = NewConn(packet.Clientbound, cc, func(pb PacketBuffer) error {
+		if pb.Id == packet.ServerPlayDisconnectID {
+			pkt := new(packet.ServerDisconnect)
+			if err := pb.Read(pkt); err != nil {
+				return err
+			}
+			disconnected <- pkt
+			return nil
+		}
+
+		pkt := new(packet.ServerKeepAlive)
+		if err := pb.Read(pkt); err != nil {
+			return err
+		}
+		ok := <-respond
+		respond <- ok
+		if !ok {
+			return nil
+		}
+		go func() { _ = client.SendPacket(&packet.ClientKeepAlive{KeepAliveID: pkt.KeepAliveID}) }()
+		return nil
+	})
+
+	handled := make(chan int, 16)
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error {
+		pb.Consume()
+		handled <- pb.Id
+		return nil
+	})
+	for _, c := range []*Conn{client, server} {
+		c.SetState(packet.Play)
+	}
+	keepAlive := NewKeepAlive(server, 10*time.Millisecond, 30*time.Millisecond)
+	t.Cleanup(func() {
+		keepAlive.Stop()
+		client.Close()
+		server.Close()
+	})
+	go client.ReadLoop()
+	go server.ReadLoop()
+
+	// Responses to our keep-alives are consumed, anything else is passed on.
+	keepAlive.Start()
+	require.Eventually(t, func() bool { return keepAlive.Latency() > 0 }, time.Second, time.Millisecond)
+	require.NoError(t, client.SendPacket(&packet.ClientKeepAlive{KeepAliveID: -1}))
+	require.Equal(t, packet.ClientPlayKeepAliveID, receive(t, handled))
+
+	// The client is disconnected once it stops responding.
+	<-respond
+	respond <- false
+	require.Equal(t, DefaultTimeoutReason, receive(t, disconnected).Reason)
+}
+
+func TestKeepAlive_SendFailed(t *testing.T) {
+	cc, sc := net.Pipe()
+	_ = cc.Close()
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error { return nil })
+	server.SetState(packet.Play)
+	server.Close()
+	keepAlive := NewKeepAlive(server, time.Millisecond, time.Second)
+
+	// Once sending fails the keep-alive stops, without a pending response, so that it can be started again.
+	for range 2 {
+		keepAlive.Start()
+		require.Eventually(t, func() bool {
+			keepAlive.mu.Lock()
+			defer keepAlive.mu.Unlock()
+			return keepAlive.stop == nil && keepAlive.pendingSince.IsZero()
+		}, time.Second, time.Millisecond)
+	}
+}
+
+func TestKeepAlive_UnknownPacket(t *testing.T) {
+	handled := make(chan int, 1)
+	c := NewConn(packet.Serverbound, &memConn{r: bytes.NewReader([]byte{0x02, 0x7f, 0x01})}, func(pb PacketBuffer) error {
+		pb.Consume()
+		handled <- pb.Id
+		return nil
+	})
+	c.SetState(packet.Login)
+	c.SetVersion(packet.LatestVersion + 1)
+	NewKeepAlive(c, time.Second, time.Second)
+
+	// Unknown packets outside the config and play states are not mistaken for keep-alive responses.
+	require.NoError(t, c.Serve(context.Background()))
+	require.Equal(t, packet.InvalidState, receive(t, handled))
+}
@@ -0,0 +1,261 @@
+package kite
+
+import (
+	"net"
+	"sync"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/valyala/bytebufferpool"
+)
+
+// WriteQueuePolicy decides what happens to packets written while the write queue is full.
+type WriteQueuePolicy int
+
+const (
+	// WriteQueueBlock blocks writers until the queue drains below the low watermark.
+	WriteQueueBlock WriteQueuePolicy = iota
+	// WriteQueueDropNonCritical drops packets reported as non-critical by WriteQueueConfig.NonCritical,
+	// while other packets are still queued up to WriteQueueConfig.MaxBytes.
+	WriteQueueDropNonCritical
+	// WriteQueueDisconnect closes the connection, and the write fails with ErrWriteQueueFull.
+	WriteQueueDisconnect
+)
+
+// WriteQueueConfig configures the write queue of a Conn, see Conn.EnableWriteQueue.
+type WriteQueueConfig struct {
+	// HighWatermark is the number of queued bytes at which the queue is considered full and Policy applies.
+	HighWatermark int
+	// LowWatermark is the number of queued bytes the queue must drain to before it is no longer full.
+	LowWatermark int
+
+	// MaxBytes is the number of queued bytes at which WriteQueueDropNonCritical closes the connection, as
+	// packets which are not dropped are still queued past HighWatermark. Zero means twice HighWatermark.
+	MaxBytes int
+
+	Policy WriteQueuePolicy
+	// NonCritical reports whether a packet may be dropped by WriteQueueDropNonCritical, for example
+	// particles or sounds. If nil, no packets are dropped.
+	NonCritical func(state packet.State, id int) bool
+}
+
+// DefaultWriteQueueConfig is a write queue configuration suitable for client connections.
+var DefaultWriteQueueConfig = WriteQueueConfig{
+	HighWatermark: 4 * 1024 * 1024,
+	LowWatermark:  1024 * 1024,
+	Policy:        WriteQueueBlock,
+}
+
+// WriteQueueStats is a snapshot of the state of a write queue.
+type WriteQueueStats struct {
+	QueuedBytes   int // Bytes waiting to be written
+	QueuedPackets int // Packets waiting to be written
+	PeakBytes     int // Highest value of QueuedBytes
+
+	WrittenBytes   uint64
+	WrittenPackets uint64
+	Writes         uint64 // Number of writes to the connection, each containing one or more packets
+	DroppedPackets uint64 // Packets dropped by WriteQueueDropNonCritical
+	BlockedWrites  uint64 // Writes which had to wait with WriteQueueBlock
+}
+
+// writeQueue buffers frames written to a Conn, which are written to the remote by a separate goroutine.
+// Frames queued while a write is in progress are coalesced into the next write.
+type writeQueue struct {
+	config WriteQueueConfig
+
+	mu      sync.Mutex
+	cond    sync.Cond // Signalled when the queue drains, fails or is closed
+	pending *bytebufferpool.ByteBuffer
+	packets int
+	queued  int // Bytes queued, including those being written
+	full    bool
+	writing bool
+	err     error
+	stats   WriteQueueStats
+
+	wake chan struct{}
+}
+
+// EnableWriteQueue makes packets be written by a dedicated goroutine rather than by the caller of
+// SendPacket or ForwardPacket, so that a slow remote does not stall the goroutine writing to it.
+// Packets written while the queue is full are handled according to config.Policy.
+//
+// EnableWriteQueue must be called before any packets are written. The queue is discarded when the
+// connection is closed, use Flush to wait until queued packets have been written.
+func (c *Conn) EnableWriteQueue(config WriteQueueConfig) {
+	q := &writeQueue{config: config, wake: make(chan struct{}, 1)}
+	q.cond.L = &q.mu
+	if c.queue.CompareAndSwap(nil, q) {
+		go c.runWriteQueue(q)
+	}
+}
+
+// WriteQueueStats returns the current statistics of the write queue, or zero values if it is not enabled.
+func (c *Conn) WriteQueueStats() WriteQueueStats {
+	q := c.queue.Load()
+	if q == nil {
+		return WriteQueueStats{}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := q.stats
+	stats.QueuedBytes = q.queued
+	stats.QueuedPackets = q.packets
+	return stats
+}
+
+// Flush waits until all queued packets have been written, returning the error which stopped the
+// queue if they could not be. It returns immediately if the write queue is not enabled.
+func (c *Conn) Flush() error {
+	q := c.queue.Load()
+	if q == nil {
+		return nil
+	}
+	return q.flush()
+}
+
+// runWriteQueue writes queued data until the queue is closed or a write fails.
+func (c *Conn) runWriteQueue(q *writeQueue) {
+	for range q.wake {
+		q.mu.Lock()
+		if q.err != nil {
+			q.mu.Unlock()
+			return
+		}
+		if q.pending == nil {
+			q.mu.Unlock()
+			continue
+		}
+		data, packets := q.pending, q.packets
+		q.pending, q.packets = nil, 0
+		q.writing = true
+		// Access to writer is synchronized through wlock by enqueue, and by flush in EnableEncryption.
+		writer := c.writer
+		q.mu.Unlock()
+
+		_, err := writer.Write(data.B)
+
+		q.mu.Lock()
+		q.writing = false
+		q.queued -= len(data.B)
+		q.stats.Writes++
+		q.stats.WrittenBytes += uint64(len(data.B))
+		q.stats.WrittenPackets += uint64(packets)
+		if q.queued <= q.config.LowWatermark {
+			q.full = false
+		}
+		if err != nil && q.err == nil {
+			q.err = err
+		}
+		q.cond.Broadcast()
+		q.mu.Unlock()
+		writePool.Put(data)
+
+		if err != nil {
+			c.Close()
+			return
+		}
+	}
+}
+
+// wait blocks while the queue is full with WriteQueueBlock. It is called before taking wlock, so that a
+// slow remote does not stall other writers or state changes while they hold it.
+func (q *writeQueue) wait() {
+	if q.config.Policy != WriteQueueBlock {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.queued >= q.config.HighWatermark {
+		q.full = true
+	}
+	if !q.full || q.err != nil {
+		return
+	}
+	q.stats.BlockedWrites++
+	for q.full && q.err == nil {
+		q.cond.Wait()
+	}
+}
+
+// enqueue adds data to the queue, applying the queue policy if it is full. Must be called while holding
+// wlock, which guarantees the order of queued frames. With WriteQueueBlock, writers have already waited
+// for room in wait, so data written by writers which waited at the same time is queued regardless.
+func (q *writeQueue) enqueue(data []byte, state packet.State, id int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.queued >= q.config.HighWatermark {
+		q.full = true
+	}
+	if q.full && q.err == nil {
+		switch q.config.Policy {
+		case WriteQueueDropNonCritical:
+			if id >= 0 && q.config.NonCritical != nil && q.config.NonCritical(state, id) {
+				q.stats.DroppedPackets++
+				return nil
+			}
+			maxBytes := q.config.MaxBytes
+			if maxBytes <= 0 {
+				maxBytes = 2 * q.config.HighWatermark
+			}
+			if q.queued+len(data) > maxBytes {
+				q.err = ErrWriteQueueFull
+				q.cond.Broadcast()
+				return ErrWriteQueueFull
+			}
+		case WriteQueueDisconnect:
+			q.err = ErrWriteQueueFull
+			q.cond.Broadcast()
+			return ErrWriteQueueFull
+		}
+	}
+	if q.err != nil {
+		return q.err
+	}
+
+	if q.pending == nil {
+		q.pending = writePool.Get()
+	}
+	_, _ = q.pending.Write(data)
+	q.packets++
+	q.queued += len(data)
+	q.stats.PeakBytes = max(q.stats.PeakBytes, q.queued)
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// flush waits until the queue is empty or has failed.
+func (q *writeQueue) flush() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for (q.pending != nil || q.writing) && q.err == nil {
+		q.cond.Wait()
+	}
+	if q.err == net.ErrClosed && q.pending == nil && !q.writing {
+		return nil // Closed after everything was written
+	}
+	return q.err
+}
+
+// close stops the queue, discarding any data which has not been written yet.
+func (q *writeQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err == nil {
+		q.err = net.ErrClosed
+	}
+	if q.pending != nil {
+		q.queued -= q.pending.Len()
+		writePool.Put(q.pending)
+		q.pending, q.packets = nil, 0
+	}
+	q.cond.Broadcast()
+	close(q.wake)
+}
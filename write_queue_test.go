@@ -0,0 +1,189 @@
+package kite
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mworzala/kite/pkg/packet"
+	"github.com/stretchr/testify/require"
+)
+
+// gateConn is a net.Conn recording writes, which block until the gate is opened.
+type gateConn struct {
+	net.Conn
+	gate chan struct{}
+
+	mu     sync.Mutex
+	writes [][]byte
+}
+
+func newGateConn() *gateConn {
+	return &gateConn{gate: make(chan struct{})}
+}
+
+func (c *gateConn) Write(p []byte) (int, error) {
+	<-c.gate
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes = append(c.writes, bytes.Clone(p))
+	return len(p), nil
+}
+
+func (c *gateConn) written() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return bytes.Join(c.writes, nil)
+}
+
+func (c *gateConn) Close() error                       { return nil }
+func (c *gateConn) SetWriteDeadline(_ time.Time) error { return nil }
+
+// newQueuedConn creates a connection in the config state writing to a gateConn through a write queue.
+func newQueuedConn(t *testing.T, config WriteQueueConfig) (*Conn, *gateConn) {
+	t.Helper()
+	gc := newGateConn()
+	c := NewConn(packet.Clientbound, gc, func(pb PacketBuffer) error { return nil })
+	c.SetState(packet.Config)
+	c.EnableWriteQueue(config)
+	t.Cleanup(c.Close)
+	return c, gc
+}
+
+// waitWriting waits until the queue writer is blocked writing everything queued so far.
+func waitWriting(t *testing.T, c *Conn) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		stats := c.WriteQueueStats()
+		return stats.QueuedPackets == 0 && stats.QueuedBytes > 0
+	}, time.Second, time.Millisecond)
+}
+
+func pluginMessage(data string) *packet.ClientPluginMessage {
+	return &packet.ClientPluginMessage{Channel: "kite:test", Data: []byte(data)}
+}
+
+func TestConn_WriteQueue_Coalesce(t *testing.T) {
+	c, gc := newQueuedConn(t, DefaultWriteQueueConfig)
+
+	require.NoError(t, c.SendPacket(pluginMessage("first")))
+	waitWriting(t, c)
+	for range 9 {
+		require.NoError(t, c.SendPacket(pluginMessage("next")))
+	}
+	require.Equal(t, 9, c.WriteQueueStats().QueuedPackets)
+
+	close(gc.gate)
+	require.NoError(t, c.Flush())
+
+	stats := c.WriteQueueStats()
+	require.Equal(t, uint64(2), stats.Writes)
+	require.Equal(t, uint64(10), stats.WrittenPackets)
+	require.Zero(t, stats.QueuedBytes)
+	require.Len(t, gc.writes, 2)
+
+	// The data written is identical to writing each packet synchronously.
+	expected := newGateConn()
+	close(expected.gate)
+	direct := NewConn(packet.Clientbound, expected, func(pb PacketBuffer) error { return nil })
+	direct.SetState(packet.Config)
+	require.NoError(t, direct.SendPacket(pluginMessage("first")))
+	for range 9 {
+		require.NoError(t, direct.SendPacket(pluginMessage("next")))
+	}
+	require.Equal(t, expected.written(), gc.written())
+}
+
+func TestConn_WriteQueue_Block(t *testing.T) {
+	c, gc := newQueuedConn(t, WriteQueueConfig{HighWatermark: 64, LowWatermark: 0, Policy: WriteQueueBlock})
+
+	require.NoError(t, c.SendPacket(pluginMessage(string(make([]byte, 100)))))
+	waitWriting(t, c)
+
+	sent := make(chan error, 1)
+	go func() { sent <- c.SendPacket(pluginMessage("blocked")) }()
+	select {
+	case <-sent:
+		t.Fatal("write should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The blocked writer does not hold the write lock.
+	require.True(t, c.wlock.TryLock())
+	c.wlock.Unlock()
+
+	close(gc.gate)
+	require.NoError(t, receive(t, sent))
+	require.NoError(t, c.Flush())
+	require.Equal(t, uint64(1), c.WriteQueueStats().BlockedWrites)
+	require.Equal(t, uint64(2), c.WriteQueueStats().WrittenPackets)
+}
+
+func TestConn_WriteQueue_DropNonCritical(t *testing.T) {
+	c, gc := newQueuedConn(t, WriteQueueConfig{
+		HighWatermark: 64,
+		Policy:        WriteQueueDropNonCritical,
+		NonCritical: func(state packet.State, id int) bool {
+			return state == packet.Config && id == packet.ClientConfigPluginMessageID
+		},
+	})
+
+	require.NoError(t, c.SendPacket(pluginMessage(string(make([]byte, 100)))))
+	waitWriting(t, c)
+	require.NoError(t, c.SendPacket(pluginMessage("dropped")))
+	require.NoError(t, c.SendPacket(&packet.ClientConfigFinishConfiguration{}))
+
+	close(gc.gate)
+	require.NoError(t, c.Flush())
+	stats := c.WriteQueueStats()
+	require.Equal(t, uint64(1), stats.DroppedPackets)
+	require.Equal(t, uint64(2), stats.WrittenPackets)
+	require.NotContains(t, string(gc.written()), "dropped")
+}
+
+func TestConn_WriteQueue_DropNonCritical_MaxBytes(t *testing.T) {
+	c, gc := newQueuedConn(t, WriteQueueConfig{HighWatermark: 64, MaxBytes: 128, Policy: WriteQueueDropNonCritical})
+
+	require.NoError(t, c.SendPacket(pluginMessage(string(make([]byte, 100)))))
+	waitWriting(t, c)
+	require.NoError(t, c.SendPacket(&packet.ClientConfigFinishConfiguration{}))
+
+	// Critical packets are queued past the high watermark, but not past MaxBytes.
+	require.ErrorIs(t, c.SendPacket(pluginMessage(string(make([]byte, 100)))), ErrWriteQueueFull)
+	require.True(t, c.closed.Load())
+	close(gc.gate)
+}
+
+func TestConn_WriteQueue_Disconnect(t *testing.T) {
+	c, gc := newQueuedConn(t, WriteQueueConfig{HighWatermark: 64, Policy: WriteQueueDisconnect})
+
+	require.NoError(t, c.SendPacket(pluginMessage(string(make([]byte, 100)))))
+	waitWriting(t, c)
+	require.ErrorIs(t, c.SendPacket(pluginMessage("too much")), ErrWriteQueueFull)
+	require.True(t, c.closed.Load())
+	close(gc.gate)
+}
+
+func TestConn_WriteQueue_DisconnectFlushes(t *testing.T) {
+	received := make(chan *packet.ServerDisconnect, 1)
+	cc, sc := net.Pipe()
+	client := NewConn(packet.Clientbound, cc, func(pb PacketBuffer) error {
+		pkt := new(packet.ServerDisconnect)
+		if err := pb.Read(pkt); err != nil {
+			return err
+		}
+		received <- pkt
+		return nil
+	})
+	server := NewConn(packet.Serverbound, sc, func(pb PacketBuffer) error { return nil })
+	for _, c := range []*Conn{client, server} {
+		c.SetState(packet.Play)
+	}
+	server.EnableWriteQueue(DefaultWriteQueueConfig)
+	go client.ReadLoop()
+
+	require.NoError(t, server.Disconnect(DefaultPacketTooLargeReason))
+	require.Equal(t, DefaultPacketTooLargeReason, receive(t, received).Reason)
+}